@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/app"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+)
+
+// runArchive implements `app archive`: a single archival pass.
+func runArchive(ctx context.Context, cfg *config.Config, st *app.Storage) error {
+	// Archival publishes no events and does not read through the cache.
+	archived, err := st.Services(cfg, nil, nil, nil, nil).Archive.ArchiveMerged(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("archived %d pull requests\n", archived)
+
+	return nil
+}
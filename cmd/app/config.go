@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+)
+
+const configUsage = "usage: app config print"
+
+// runConfig implements `app config print`. The output is a flat YAML file
+// that CONFIG_FILE accepts, each value annotated with its source.
+func runConfig(cfg *config.Config, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return errors.New(configUsage)
+	}
+
+	for _, entry := range cfg.Entries() {
+		fmt.Printf("%s: %s # %s\n", strings.ToLower(entry.Key), strconv.Quote(entry.Value), entry.Source)
+	}
+
+	return nil
+}
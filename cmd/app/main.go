@@ -2,56 +2,132 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/app"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
-	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/server"
-	pullrequestservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/pull_request"
-	teamservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/team"
-	userservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/user"
-
-	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
-	postgresrepo "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres/repo"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
 )
 
 func main() {
 	err := godotenv.Load(".env")
 	if err != nil {
-		log.Println("no .env file found, using environment only")
+		slog.Info("no .env file found, using environment only")
 	}
 
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal()
+		// Logging is not set up yet, and a list of problems reads better as plain text.
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err = runConfig(cfg, os.Args[2:]); err != nil {
+			fatal(err)
+		}
+
+		return
 	}
 
+	logging.Setup(cfg.LogConfig)
+
 	ctx := context.Background()
 
-	pool, err := postgres.NewPool(ctx, cfg.DBConfig)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TracingConfig)
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
-	defer pool.Close()
+	defer flushTraces(shutdownTracing, cfg.WebServerConfig.ShutdownTimeout)
 
-	txManager := postgres.NewTxManager(pool)
-	builder := postgres.NewStatementBuilder()
-	repoFactory := postgresrepo.NewRepoFactory(builder)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err = runMigrate(ctx, cfg, os.Args[2:]); err != nil {
+			fatal(err)
+		}
 
-	teamService := teamservice.NewTeamService(txManager, pool, repoFactory)
-	userService := userservice.NewUserService(txManager, pool, repoFactory)
-	pullRequestService := pullrequestservice.NewPullRequestService(txManager, pool, repoFactory)
+		return
+	}
 
-	server := server.NewServer(teamService, userService, pullRequestService)
+	st, err := app.OpenStorage(ctx, cfg)
+	if err != nil {
+		fatal(err)
+	}
+	defer st.Close()
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "archive":
+			err = runArchive(ctx, cfg, st)
+		case "export":
+			err = runExport(ctx, cfg, st, os.Args[2:])
+		case "import":
+			err = runImport(ctx, cfg, st, os.Args[2:])
+		case "import-teams":
+			err = runImportTeams(ctx, cfg, st, os.Args[2:])
+		default:
+			fatal(fmt.Errorf("unknown command %s", os.Args[1]))
+		}
 
-	go func() {
-		err = server.Start(fmt.Sprintf("%s:%d", cfg.WebServerConfig.Address, cfg.WebServerConfig.Port))
 		if err != nil {
-			log.Fatal(err)
+			fatal(err)
+		}
+
+		return
+	}
+
+	instance, err := app.New(cfg, st)
+	if err != nil {
+		fatal(err)
+	}
+
+	backgroundCtx, stopBackground := context.WithCancel(ctx)
+	defer stopBackground()
+
+	instance.RunBackground(backgroundCtx)
+
+	server := instance.Server
+
+	addr := fmt.Sprintf("%s:%d", cfg.WebServerConfig.Address, cfg.WebServerConfig.Port)
+
+	go func() {
+		err := server.Start(addr)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal(err)
+		}
+	}()
+
+	slog.Info("server started", slog.String("addr", addr))
+
+	grpcServer := instance.GRPCServer
+
+	if grpcServer != nil {
+		grpcAddr := fmt.Sprintf("%s:%d", cfg.GRPCConfig.Address, cfg.GRPCConfig.Port)
+
+		go func() {
+			if err := grpcServer.Start(grpcAddr); err != nil {
+				fatal(err)
+			}
+		}()
+
+		slog.Info("grpc server started", slog.String("addr", grpcAddr))
+	}
+
+	// SIGHUP reloads the assignment policy without dropping connections.
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	go func() {
+		for range reload {
+			instance.ReloadPolicy()
 		}
 	}()
 
@@ -59,15 +135,45 @@ func main() {
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("shutting down server", slog.String("drain_delay", cfg.WebServerConfig.DrainDelay.String()))
+
+	// Report not ready first and keep serving while load balancers notice it.
+	instance.StartDraining()
+	time.Sleep(cfg.WebServerConfig.DrainDelay)
+
+	stopBackground()
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.WebServerConfig.ShutdownTimeout)
 
 	defer cancel()
 
+	instance.Close(ctx)
+
 	if err = server.Stop(ctx); err != nil {
-		log.Printf("error stopping server: %v\n", err)
+		slog.Error("error stopping server", logging.Error(err))
+	}
+
+	if grpcServer != nil {
+		if err = grpcServer.Stop(ctx); err != nil {
+			slog.Error("error stopping grpc server", logging.Error(err))
+		}
+	}
+
+	slog.Info("server stopped")
+}
+
+// flushTraces exports the spans still buffered by the tracer provider.
+func flushTraces(shutdown func(context.Context) error, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := shutdown(ctx); err != nil {
+		slog.Error("error flushing traces", logging.Error(err))
 	}
+}
 
-	log.Println("Server stopped")
+// fatal logs err and exits. Deferred calls do not run.
+func fatal(err error) {
+	slog.Error("fatal error", logging.Error(err))
+	os.Exit(1)
 }
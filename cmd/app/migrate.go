@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/migrations"
+)
+
+const migrateUsage = "usage: app migrate up|down [steps]|status|version"
+
+// runMigrate implements `app migrate up|down [steps]|status|version`.
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	if cfg.StorageConfig.Backend != config.StorageBackendPostgres {
+		return fmt.Errorf("migrations are managed only for postgres, STORAGE_BACKEND is %s", cfg.StorageConfig.Backend)
+	}
+
+	pool, err := postgres.NewPool(ctx, cfg.DBConfig)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	migrator, err := postgres.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("applied %d migrations\n", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid steps %q: %s", args[1], migrateUsage)
+			}
+		}
+
+		rolledBack, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("rolled back %d migrations\n", rolledBack)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied"
+			}
+
+			fmt.Printf("%06d_%s\t%s\n", status.Version, status.Name, state)
+		}
+	case "version":
+		version, dirty, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+
+		if dirty {
+			fmt.Printf("%d (dirty)\n", version)
+		} else {
+			fmt.Println(version)
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/app"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/handlers"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/snapshot"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/cache"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+)
+
+const (
+	exportUsage = "usage: app export [FILE]"
+	importUsage = "usage: app import [-mode merge|replace] [-dry-run] FILE"
+)
+
+// newSnapshotService builds the service for the CLI. With postgres cache
+// invalidation enabled, an import also resets the caches of running instances.
+func newSnapshotService(cfg *config.Config, st *app.Storage) handlers.SnapshotService {
+	if cfg.CacheConfig.Enabled && cfg.CacheConfig.PGInvalidationEnabled && st.Pool != nil {
+		local := cache.New(1, cfg.CacheConfig.TTL)
+		bridge := postgres.NewCacheBridge(st.Pool, local, cfg.CacheConfig.PGInvalidationChannel)
+
+		return st.Services(cfg, nil, local, bridge, nil).Snapshot
+	}
+
+	return st.Services(cfg, nil, nil, nil, nil).Snapshot
+}
+
+// runExport implements `app export [FILE]`, writing to stdout without FILE.
+func runExport(ctx context.Context, cfg *config.Config, st *app.Storage, args []string) error {
+	if len(args) > 1 {
+		return errors.New(exportUsage)
+	}
+
+	out := io.Writer(os.Stdout)
+
+	if len(args) == 1 && args[0] != "-" {
+		file, err := os.Create(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		out = file
+	}
+
+	w := snapshot.NewWriter(out, time.Now())
+
+	if err := newSnapshotService(cfg, st).Export(ctx, w); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+// runImport implements `app import`. It prints the report and fails
+// when the snapshot is invalid.
+func runImport(ctx context.Context, cfg *config.Config, st *app.Storage, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	mode := flags.String("mode", string(domain.ImportModeMerge), "merge or replace")
+	dryRun := flags.Bool("dry-run", false, "validate and report without writing")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errors.New(importUsage)
+	}
+
+	importMode := domain.ImportMode(*mode)
+	if importMode != domain.ImportModeMerge && importMode != domain.ImportModeReplace {
+		return errors.New(importUsage)
+	}
+
+	in := io.Reader(os.Stdin)
+
+	if path := flags.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		in = file
+	}
+
+	snap, err := snapshot.Decode(in)
+	if err != nil {
+		return err
+	}
+
+	report, err := newSnapshotService(cfg, st).Import(ctx, snap, importMode, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	if err = encoder.Encode(snapshot.NewReportJSON(report)); err != nil {
+		return err
+	}
+
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("snapshot is invalid: %d errors", len(report.Errors))
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/app"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/handlers"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/events"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/orgchart"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/cache"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+)
+
+const importTeamsUsage = "usage: app import-teams [-dry-run] [-format csv|yaml] FILE"
+
+// newCLITeamService builds the team service for the CLI. With the postgres
+// bridges enabled, running instances receive the events and cache resets;
+// the returned flush sends the queued events and must be called before exit.
+func newCLITeamService(
+	cfg *config.Config,
+	st *app.Storage,
+) (handlers.TeamService, func(ctx context.Context) error, error) {
+	var (
+		publisher postgres.EventPublisher = events.NewBroker(1)
+		flush                             = func(context.Context) error { return nil }
+		teamCache *cache.Cache
+		notifier  cache.Notifier
+	)
+
+	if cfg.EventsConfig.PGBridgeEnabled && st.Pool != nil {
+		bridge, err := postgres.NewEventBridge(st.Pool, publisher, cfg.EventsConfig.PGBridgeChannel)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		publisher = bridge
+		flush = bridge.Flush
+	}
+
+	if cfg.CacheConfig.Enabled && cfg.CacheConfig.PGInvalidationEnabled && st.Pool != nil {
+		teamCache = cache.New(1, cfg.CacheConfig.TTL)
+		notifier = postgres.NewCacheBridge(st.Pool, teamCache, cfg.CacheConfig.PGInvalidationChannel)
+	}
+
+	return st.Services(cfg, publisher, teamCache, notifier, nil).Team, flush, nil
+}
+
+// runImportTeams implements `app import-teams`. The format defaults to the
+// file extension; FILE may be - for stdin, then -format is required.
+func runImportTeams(ctx context.Context, cfg *config.Config, st *app.Storage, args []string) error {
+	flags := flag.NewFlagSet("import-teams", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print the diff without applying it")
+	formatFlag := flags.String("format", "", "csv or yaml")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errors.New(importTeamsUsage)
+	}
+
+	path := flags.Arg(0)
+
+	format, ok := orgchart.Format(*formatFlag), true
+	switch format {
+	case "":
+		format, ok = orgchart.FormatFromPath(path)
+	case orgchart.FormatCSV, orgchart.FormatYAML:
+	default:
+		ok = false
+	}
+
+	if !ok {
+		return errors.New(importTeamsUsage)
+	}
+
+	in := io.Reader(os.Stdin)
+
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		in = file
+	}
+
+	chart, err := orgchart.Parse(in, format)
+	if err != nil {
+		return err
+	}
+
+	teamService, flush, err := newCLITeamService(cfg, st)
+	if err != nil {
+		return err
+	}
+
+	diff, err := teamService.ImportOrgChart(ctx, chart, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if err = flush(ctx); err != nil {
+		return err
+	}
+
+	return orgchart.WriteDiff(os.Stdout, diff)
+}
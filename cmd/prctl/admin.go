@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/snapshot"
+)
+
+var statsCommands = map[string]command{
+	"transactions": {usage: "stats transactions", run: runStatsTransactions},
+	"pools":        {usage: "stats pools", run: runStatsPools},
+	"cache":        {usage: "stats cache", run: runStatsCache},
+}
+
+func runStatsTransactions(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	var stats dto.TxStatsDTO
+	if err := env.client.request(ctx, http.MethodGet, "/admin/stats/transactions", nil, "", nil, &stats); err != nil {
+		return err
+	}
+
+	t := table{header: []string{"TRANSACTIONS", "COMMITS", "RETRIES", "EXHAUSTED", "SERIALIZATION", "DEADLOCKS"}}
+	t.add(formatUint(stats.Transactions), formatUint(stats.Commits), formatUint(stats.Retries),
+		formatUint(stats.RetriesExhausted), formatUint(stats.SerializationFailures), formatUint(stats.Deadlocks))
+
+	return env.printer.print(stats, t)
+}
+
+func runStatsPools(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	var res struct {
+		Pools []dto.PoolStatsDTO `json:"pools"`
+	}
+
+	if err := env.client.request(ctx, http.MethodGet, "/admin/stats/pools", nil, "", nil, &res); err != nil {
+		return err
+	}
+
+	t := table{header: []string{"POOL", "MAX", "TOTAL", "IDLE", "ACQUIRED", "ACQUIRE_MS", "HEALTHY", "LAG_MS"}}
+	for _, pool := range res.Pools {
+		t.add(pool.Name, strconv.Itoa(int(pool.MaxConns)), strconv.Itoa(int(pool.TotalConns)),
+			strconv.Itoa(int(pool.IdleConns)), strconv.Itoa(int(pool.AcquiredConns)),
+			strconv.FormatInt(pool.AcquireDurationMs, 10), formatBool(pool.Healthy), strconv.FormatInt(pool.LagMs, 10))
+	}
+
+	return env.printer.print(res, t)
+}
+
+func runStatsCache(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	var stats dto.CacheStatsDTO
+	if err := env.client.request(ctx, http.MethodGet, "/admin/stats/cache", nil, "", nil, &stats); err != nil {
+		return err
+	}
+
+	t := table{header: []string{"ENABLED", "TEAM_HITS", "TEAM_MISSES", "USER_HITS", "USER_MISSES"}}
+	t.add(formatBool(stats.Enabled), formatUint(stats.TeamHits), formatUint(stats.TeamMisses),
+		formatUint(stats.UserHits), formatUint(stats.UserMisses))
+
+	return env.printer.print(stats, t)
+}
+
+// runExport writes the snapshot to FILE or to stdout.
+func runExport(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) > 1 {
+		return errUsage
+	}
+
+	body, err := env.client.stream(ctx, "/admin/export", nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	out := io.Writer(os.Stdout)
+
+	if len(args) == 1 && args[0] != "-" {
+		file, err := os.Create(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		out = file
+	}
+
+	_, err = io.Copy(out, body)
+
+	return err
+}
+
+// runImport prints the report, also for a snapshot that failed validation.
+func runImport(ctx context.Context, env *cmdEnv, args []string) error {
+	flags := newFlagSet("import")
+	mode := flags.String("mode", "merge", "merge or replace")
+	dryRun := flags.Bool("dry-run", false, "validate and report without writing")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	in, closeFn, err := openInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	query := url.Values{"mode": {*mode}, "dry_run": {strconv.FormatBool(*dryRun)}}
+
+	var res struct {
+		Report snapshot.ReportJSON `json:"report"`
+	}
+
+	err = env.client.request(ctx, http.MethodPost, "/admin/import", query, "application/json", in, &res,
+		http.StatusUnprocessableEntity)
+
+	invalid := isStatus(err, http.StatusUnprocessableEntity)
+	if err != nil && !invalid {
+		return err
+	}
+
+	report := res.Report
+
+	t := table{header: []string{"KIND", "CREATED", "UPDATED"}}
+	t.add("teams", strconv.Itoa(report.Teams.Created), strconv.Itoa(report.Teams.Updated))
+	t.add("users", strconv.Itoa(report.Users.Created), strconv.Itoa(report.Users.Updated))
+	t.add("pull_requests", strconv.Itoa(report.PullRequests.Created), strconv.Itoa(report.PullRequests.Updated))
+
+	for _, message := range report.Errors {
+		t.add("error", message, "")
+	}
+
+	if err = env.printer.print(res, t); err != nil {
+		return err
+	}
+
+	if invalid {
+		return fmt.Errorf("snapshot is invalid: %d errors", len(report.Errors))
+	}
+
+	return nil
+}
+
+func formatUint(n uint64) string {
+	return strconv.FormatUint(n, 10)
+}
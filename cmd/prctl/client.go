@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
+)
+
+// apiError is an ErrorResponse returned by the service.
+type apiError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s: %s (HTTP %d)", e.Code, e.Message, e.Status)
+}
+
+// client calls the HTTP API. Request and response bodies are the dto
+// types of the service itself.
+type client struct {
+	baseURL    string
+	adminToken string
+	userToken  string
+	http       *http.Client
+}
+
+// request sends body as JSON, or as is when it is an io.Reader, and decodes
+// a 2xx response into out unless out is nil. Statuses listed in accept are
+// decoded into out as well and returned as an apiError.
+func (c *client) request(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	contentType string,
+	body any,
+	out any,
+	accept ...int,
+) error {
+	res, err := c.send(ctx, method, path, query, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+
+	ok := res.StatusCode >= 200 && res.StatusCode < 300
+	accepted := false
+
+	for _, status := range accept {
+		accepted = accepted || res.StatusCode == status
+	}
+
+	if !ok && !accepted {
+		return responseError(res.StatusCode, data)
+	}
+
+	if out != nil && len(data) > 0 {
+		if err = json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("error decoding response: %w", err)
+		}
+	}
+
+	if accepted {
+		return &apiError{Status: res.StatusCode, Code: http.StatusText(res.StatusCode)}
+	}
+
+	return nil
+}
+
+// stream returns the body of a successful GET for the caller to read.
+func (c *client) stream(ctx context.Context, path string, query url.Values) (io.ReadCloser, error) {
+	res, err := c.send(ctx, http.MethodGet, path, query, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+
+		data, _ := io.ReadAll(res.Body)
+
+		return nil, responseError(res.StatusCode, data)
+	}
+
+	return res.Body, nil
+}
+
+func (c *client) send(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	contentType string,
+	body any,
+) (*http.Response, error) {
+	var reader io.Reader
+
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding request: %w", err)
+		}
+
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+
+	target := strings.TrimRight(c.baseURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if c.adminToken != "" {
+		req.Header.Set("X-Admin-Token", c.adminToken)
+	}
+
+	if c.userToken != "" {
+		req.Header.Set("X-User-Token", c.userToken)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling %s: %w", path, err)
+	}
+
+	return res, nil
+}
+
+func responseError(status int, data []byte) error {
+	var body dto.ErrorDTO
+	if err := json.Unmarshal(data, &body); err != nil || body.Error.Code == "" {
+		return &apiError{Status: status, Code: http.StatusText(status), Message: strings.TrimSpace(string(data))}
+	}
+
+	return &apiError{Status: status, Code: body.Error.Code, Message: body.Error.Message}
+}
+
+func isStatus(err error, status int) bool {
+	var apiErr *apiError
+
+	return errors.As(err, &apiErr) && apiErr.Status == status
+}
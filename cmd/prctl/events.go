@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
+)
+
+// runEvents follows /events/stream and prints every event as it arrives.
+// The service keeps no history, so this is the audit trail from now on.
+func runEvents(ctx context.Context, env *cmdEnv, args []string) error {
+	if env.maintenance {
+		return errors.New("events are not available in maintenance mode")
+	}
+
+	flags := newFlagSet("events")
+	userID := flags.String("user", "", "only events of this user")
+	teamName := flags.String("team", "", "only events of this team")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		return errUsage
+	}
+
+	query := url.Values{}
+	if *userID != "" {
+		query.Set("user_id", *userID)
+	}
+
+	if *teamName != "" {
+		query.Set("team_name", *teamName)
+	}
+
+	body, err := env.client.stream(ctx, "/events/stream", query)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if env.printer.format == outputTable {
+		if err = env.printer.message("OCCURRED_AT\tTYPE\tPULL_REQUEST_ID\tUSER_ID\tTEAM\tDETAILS"); err != nil {
+			return err
+		}
+	}
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+
+		var event dto.EventDTO
+		if err = json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("error decoding event: %w", err)
+		}
+
+		if err = printEvent(env.printer, event); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// printEvent prints one line per event so the output can be piped.
+func printEvent(p printer, event dto.EventDTO) error {
+	if p.format == outputJSON {
+		return json.NewEncoder(p.w).Encode(event)
+	}
+
+	var details []string
+
+	if len(event.Reviewers) > 0 {
+		details = append(details, "reviewers="+strings.Join(event.Reviewers, ","))
+	}
+
+	if event.OldUserID != "" {
+		details = append(details, "old_user="+event.OldUserID)
+	}
+
+	if event.IsActive != nil {
+		details = append(details, fmt.Sprintf("is_active=%t", *event.IsActive))
+	}
+
+	return p.message("%s\t%s\t%s\t%s\t%s\t%s", event.OccurredAt, event.Type, dash(event.PullRequestID),
+		event.UserID, event.TeamName, strings.Join(details, " "))
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,209 @@
+// Command prctl operates the service through its HTTP API, or directly on
+// the database in maintenance mode.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+var errUsage = errors.New("invalid arguments")
+
+type cmdEnv struct {
+	client      *client
+	printer     printer
+	maintenance bool
+}
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, env *cmdEnv, args []string) error
+}
+
+// groups are the commands with subcommands; single commands have the
+// group name as their only key.
+var groups = map[string]map[string]command{
+	"team":   teamCommands,
+	"user":   userCommands,
+	"pr":     pullRequestCommands,
+	"stats":  statsCommands,
+	"export": {"": {usage: "export [FILE]", run: runExport}},
+	"import": {"": {usage: "import [-mode merge|replace] [-dry-run] FILE", run: runImport}},
+	"events": {"": {usage: "events [-user USER_ID] [-team TEAM]", run: runEvents}},
+}
+
+func main() {
+	flags := flag.NewFlagSet("prctl", flag.ContinueOnError)
+	flags.Usage = func() { printUsage(flags) }
+
+	profileName := flags.String("profile", "", "profile from the config file (PRCTL_PROFILE)")
+	baseURL := flags.String("url", "", "API address, overrides the profile")
+	adminToken := flags.String("token", "", "admin token, overrides the profile")
+	output := flags.String("o", "", "output format: table or json")
+	maintenance := flags.Bool("maintenance", false, "work on the database from the app environment, without the API")
+
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+
+	cmd, args, ok := findCommand(flags.Args())
+	if !ok {
+		printUsage(flags)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	env, closeFn, err := newEnv(ctx, *profileName, *baseURL, *adminToken, *output, *maintenance)
+	if err != nil {
+		fatal(err)
+	}
+
+	err = cmd.run(ctx, env, args)
+	closeFn()
+
+	if errors.Is(err, errUsage) {
+		fmt.Fprintln(os.Stderr, "usage: prctl "+cmd.usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func newEnv(
+	ctx context.Context,
+	profileName string,
+	baseURL string,
+	adminToken string,
+	output string,
+	maintenance bool,
+) (*cmdEnv, func(), error) {
+	p, err := loadProfile(profileName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if output == "" {
+		output = p.Output
+	}
+
+	switch output {
+	case "":
+		output = outputTable
+	case outputTable, outputJSON:
+	default:
+		return nil, nil, fmt.Errorf("unknown output format %s", output)
+	}
+
+	env := &cmdEnv{
+		printer:     printer{format: output, w: os.Stdout},
+		maintenance: maintenance,
+	}
+
+	if maintenance {
+		c, closeFn, err := openMaintenance(ctx, p.EnvFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		env.client = c
+
+		return env, closeFn, nil
+	}
+
+	if baseURL == "" {
+		baseURL = p.URL
+	}
+
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+
+	if adminToken == "" {
+		adminToken = p.AdminToken
+	}
+
+	env.client = &client{
+		baseURL:    baseURL,
+		adminToken: adminToken,
+		userToken:  p.UserToken,
+		http:       http.DefaultClient,
+	}
+
+	return env, func() {}, nil
+}
+
+func findCommand(args []string) (command, []string, bool) {
+	if len(args) == 0 {
+		return command{}, nil, false
+	}
+
+	group, ok := groups[args[0]]
+	if !ok {
+		return command{}, nil, false
+	}
+
+	if cmd, ok := group[""]; ok {
+		return cmd, args[1:], true
+	}
+
+	if len(args) < 2 {
+		return command{}, nil, false
+	}
+
+	cmd, ok := group[args[1]]
+
+	return cmd, args[2:], ok
+}
+
+func printUsage(flags *flag.FlagSet) {
+	var usages []string
+	for _, group := range groups {
+		for _, cmd := range group {
+			usages = append(usages, "  prctl [flags] "+cmd.usage)
+		}
+	}
+
+	sort.Strings(usages)
+
+	fmt.Fprintf(os.Stderr, "usage:\n%s\n\nflags:\n", strings.Join(usages, "\n"))
+	flags.PrintDefaults()
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	return flags
+}
+
+// openInput opens a file, or stdin for -.
+func openInput(path string) (io.Reader, func(), error) {
+	if path == "-" {
+		return os.Stdin, func() {}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, func() { _ = file.Close() }, nil
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "prctl:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/joho/godotenv"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/app"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+// maintenanceURL is the base URL of the in-process API.
+const maintenanceURL = "http://maintenance"
+
+// handlerTransport serves requests with the API handler in process.
+// Responses are buffered, so streams such as /events/stream never end.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+
+	return rec.Result(), nil
+}
+
+// openMaintenance builds the API of the app over the database configured
+// in the environment, without a listener or background jobs. Postgres
+// events and cache invalidations still reach running instances.
+func openMaintenance(ctx context.Context, envFile string) (*client, func(), error) {
+	if envFile != "" {
+		if err := godotenv.Load(envFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logging.Setup(cfg.LogConfig)
+
+	// The operator is the only caller of the in-process API.
+	cfg.LimitsConfig.RateLimitEnabled = false
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TracingConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	st, err := app.OpenStorage(ctx, cfg)
+	if err != nil {
+		_ = shutdownTracing(ctx)
+		return nil, nil, err
+	}
+
+	instance, err := app.New(cfg, st)
+	if err != nil {
+		st.Close()
+		_ = shutdownTracing(ctx)
+
+		return nil, nil, err
+	}
+
+	closeFn := func() {
+		instance.Close(ctx)
+		st.Close()
+		_ = shutdownTracing(ctx)
+	}
+
+	return &client{
+		baseURL:    maintenanceURL,
+		adminToken: cfg.AuthConfig.AdminToken,
+		http:       &http.Client{Transport: handlerTransport{handler: instance.Server.Handler()}},
+	}, closeFn, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// profile holds the connection settings of one environment.
+type profile struct {
+	URL        string `yaml:"url"`
+	AdminToken string `yaml:"admin_token"`
+	UserToken  string `yaml:"user_token"`
+	Output     string `yaml:"output"`
+	// EnvFile is loaded in maintenance mode: the app configuration
+	// (STORAGE_BACKEND, DATABASE_URL, ...) is read from the environment.
+	EnvFile string `yaml:"env_file"`
+}
+
+// profileFile is the prctl configuration file:
+//
+//	current: local
+//	profiles:
+//	  local:
+//	    url: http://localhost:8080
+//	    admin_token: admin
+type profileFile struct {
+	Current  string             `yaml:"current"`
+	Profiles map[string]profile `yaml:"profiles"`
+}
+
+// profilePath is PRCTL_CONFIG or prctl/config.yaml in the user config directory.
+func profilePath() (string, error) {
+	if path := os.Getenv("PRCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "prctl", "config.yaml"), nil
+}
+
+// loadProfile returns the named profile, falling back to PRCTL_PROFILE and
+// then to current. A missing file gives an empty profile.
+func loadProfile(name string) (profile, error) {
+	path, err := profilePath()
+	if err != nil {
+		return profile{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && name == "" {
+		return profile{}, nil
+	}
+
+	if err != nil {
+		return profile{}, fmt.Errorf("error reading profiles: %w", err)
+	}
+
+	var file profileFile
+	if err = yaml.Unmarshal(data, &file); err != nil {
+		return profile{}, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+
+	if name == "" {
+		name = os.Getenv("PRCTL_PROFILE")
+	}
+
+	if name == "" {
+		name = file.Current
+	}
+
+	if name == "" {
+		return profile{}, nil
+	}
+
+	p, ok := file.Profiles[name]
+	if !ok {
+		return profile{}, fmt.Errorf("profile %s not found in %s", name, path)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
+)
+
+var pullRequestCommands = map[string]command{
+	"create":   {usage: "pr create PULL_REQUEST_ID NAME AUTHOR_ID", run: runPRCreate},
+	"get":      {usage: "pr get PULL_REQUEST_ID", run: runPRGet},
+	"merge":    {usage: "pr merge PULL_REQUEST_ID", run: runPRMerge},
+	"reassign": {usage: "pr reassign PULL_REQUEST_ID OLD_REVIEWER_ID", run: runPRReassign},
+	"delete":   {usage: "pr delete PULL_REQUEST_ID", run: runPRDelete},
+	"restore":  {usage: "pr restore PULL_REQUEST_ID", run: runPRRestore},
+}
+
+type pullRequestIDRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type pullRequestResponse struct {
+	PullRequest dto.PullRequestDTO `json:"pr"`
+}
+
+func pullRequestTable(pr dto.PullRequestDTO) table {
+	t := table{header: []string{"PULL_REQUEST_ID", "NAME", "AUTHOR", "STATUS", "REVIEWERS", "VERSION", "MERGED_AT"}}
+	t.add(pr.ID, pr.Name, pr.AuthorID, pr.Status, strings.Join(pr.AssignedReviewers, ","),
+		strconv.FormatInt(pr.Version, 10), formatOptional(pr.MergedAt))
+
+	return t
+}
+
+func runPRCreate(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 3 {
+		return errUsage
+	}
+
+	req := dto.PullRequestDTO{ID: args[0], Name: args[1], AuthorID: args[2]}
+
+	var res pullRequestResponse
+	if err := env.client.request(ctx, http.MethodPost, "/pullRequest/create", nil, "", req, &res); err != nil {
+		return err
+	}
+
+	return env.printer.print(res, pullRequestTable(res.PullRequest))
+}
+
+func runPRGet(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	query := url.Values{"pull_request_id": {args[0]}}
+
+	var res pullRequestResponse
+	if err := env.client.request(ctx, http.MethodGet, "/pullRequest/get", query, "", nil, &res); err != nil {
+		return err
+	}
+
+	return env.printer.print(res, pullRequestTable(res.PullRequest))
+}
+
+func runPRMerge(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	req := pullRequestIDRequest{PullRequestID: args[0]}
+
+	var res pullRequestResponse
+	if err := env.client.request(ctx, http.MethodPost, "/pullRequest/merge", nil, "", req, &res); err != nil {
+		return err
+	}
+
+	return env.printer.print(res, pullRequestTable(res.PullRequest))
+}
+
+func runPRReassign(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	req := struct {
+		PullRequestID string `json:"pull_request_id"`
+		OldUserID     string `json:"old_user_id"`
+	}{PullRequestID: args[0], OldUserID: args[1]}
+
+	var res struct {
+		PullRequest dto.PullRequestDTO `json:"pr"`
+		ReplacedBy  string             `json:"replaced_by"`
+	}
+
+	if err := env.client.request(ctx, http.MethodPost, "/pullRequest/reassign", nil, "", req, &res); err != nil {
+		return err
+	}
+
+	if err := env.printer.print(res, pullRequestTable(res.PullRequest)); err != nil {
+		return err
+	}
+
+	return env.printer.message("%s replaced by %s", args[1], res.ReplacedBy)
+}
+
+func runPRDelete(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	req := pullRequestIDRequest{PullRequestID: args[0]}
+
+	if err := env.client.request(ctx, http.MethodPost, "/pullRequest/delete", nil, "", req, nil); err != nil {
+		return err
+	}
+
+	return env.printer.message("pull request %s deleted", args[0])
+}
+
+func runPRRestore(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	req := pullRequestIDRequest{PullRequestID: args[0]}
+
+	var res pullRequestResponse
+	if err := env.client.request(ctx, http.MethodPost, "/pullRequest/restore", nil, "", req, &res); err != nil {
+		return err
+	}
+
+	return env.printer.print(res, pullRequestTable(res.PullRequest))
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/orgchart"
+)
+
+var teamCommands = map[string]command{
+	"add":     {usage: "team add FILE", run: runTeamAdd},
+	"get":     {usage: "team get TEAM", run: runTeamGet},
+	"delete":  {usage: "team delete TEAM", run: runTeamDelete},
+	"restore": {usage: "team restore TEAM", run: runTeamRestore},
+	"import":  {usage: "team import [-dry-run] [-format csv|yaml] FILE", run: runTeamImport},
+}
+
+type teamNameRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+type teamResponse struct {
+	Team dto.TeamDTO `json:"team"`
+}
+
+func teamTable(team dto.TeamDTO) table {
+	t := table{header: []string{"TEAM", "USER_ID", "USERNAME", "ACTIVE"}}
+	for _, member := range team.Members {
+		t.add(team.TeamName, member.UserID, member.Username, formatBool(member.IsActive))
+	}
+
+	return t
+}
+
+// runTeamAdd sends a team in the body format of POST /team/add.
+func runTeamAdd(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	in, closeFn, err := openInput(args[0])
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	var team dto.TeamDTO
+	if err = json.NewDecoder(in).Decode(&team); err != nil {
+		return errors.New("team file must be JSON in the format of POST /team/add")
+	}
+
+	var res teamResponse
+	if err = env.client.request(ctx, http.MethodPost, "/team/add", nil, "", team, &res); err != nil {
+		return err
+	}
+
+	return env.printer.print(res, teamTable(res.Team))
+}
+
+func runTeamGet(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	var team dto.TeamDTO
+
+	err := env.client.request(ctx, http.MethodGet, "/team/get", url.Values{"team_name": {args[0]}}, "", nil, &team)
+	if err != nil {
+		return err
+	}
+
+	return env.printer.print(team, teamTable(team))
+}
+
+func runTeamDelete(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	err := env.client.request(ctx, http.MethodPost, "/team/delete", nil, "", teamNameRequest{TeamName: args[0]}, nil)
+	if err != nil {
+		return err
+	}
+
+	return env.printer.message("team %s deleted", args[0])
+}
+
+func runTeamRestore(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	var res teamResponse
+
+	err := env.client.request(ctx, http.MethodPost, "/team/restore", nil, "", teamNameRequest{TeamName: args[0]}, &res)
+	if err != nil {
+		return err
+	}
+
+	return env.printer.print(res, teamTable(res.Team))
+}
+
+func runTeamImport(ctx context.Context, env *cmdEnv, args []string) error {
+	flags := newFlagSet("team import")
+	dryRun := flags.Bool("dry-run", false, "show the diff without applying it")
+	format := flags.String("format", "", "csv or yaml, by default from the file extension")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	chartFormat := orgchart.Format(*format)
+	if chartFormat == "" {
+		detected, ok := orgchart.FormatFromPath(flags.Arg(0))
+		if !ok {
+			return errors.New("cannot detect the format, set -format")
+		}
+
+		chartFormat = detected
+	}
+
+	in, closeFn, err := openInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	query := url.Values{"format": {string(chartFormat)}, "dry_run": {strconv.FormatBool(*dryRun)}}
+
+	var res struct {
+		Diff orgchart.DiffJSON `json:"diff"`
+	}
+
+	if err = env.client.request(ctx, http.MethodPost, "/team/import", query, "text/plain", in, &res); err != nil {
+		return err
+	}
+
+	t := table{header: []string{"CHANGE", "USER_ID", "USERNAME", "TEAM", "FROM_TEAM", "ACTIVE"}}
+
+	for _, teamName := range res.Diff.TeamsCreated {
+		t.add("team created", "-", "-", teamName, "-", "-")
+	}
+
+	groups := []struct {
+		name    string
+		changes []orgchart.UserChangeJSON
+	}{
+		{"created", res.Diff.UsersCreated},
+		{"restored", res.Diff.UsersRestored},
+		{"moved", res.Diff.UsersMoved},
+		{"updated", res.Diff.UsersUpdated},
+		{"deactivated", res.Diff.UsersDeactivated},
+	}
+
+	for _, group := range groups {
+		for _, change := range group.changes {
+			fromTeam := change.FromTeam
+			if fromTeam == "" {
+				fromTeam = "-"
+			}
+
+			t.add(group.name, change.UserID, change.Username, change.TeamName, fromTeam, formatBool(change.IsActive))
+		}
+	}
+
+	if err = env.printer.print(res, t); err != nil {
+		return err
+	}
+
+	if res.Diff.DryRun {
+		return env.printer.message("dry run, %d users unchanged, nothing applied", res.Diff.Unchanged)
+	}
+
+	return env.printer.message("applied, %d users unchanged", res.Diff.Unchanged)
+}
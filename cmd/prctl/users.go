@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
+)
+
+var userCommands = map[string]command{
+	"set-active": {usage: "user set-active USER_ID true|false", run: runUserSetActive},
+	"reviews":    {usage: "user reviews [-archived] USER_ID", run: runUserReviews},
+	"delete":     {usage: "user delete USER_ID", run: runUserDelete},
+	"restore":    {usage: "user restore USER_ID", run: runUserRestore},
+}
+
+type userIDRequest struct {
+	UserID string `json:"user_id"`
+}
+
+type userResponse struct {
+	User dto.UserDTO `json:"user"`
+}
+
+func userTable(user dto.UserDTO) table {
+	t := table{header: []string{"USER_ID", "USERNAME", "TEAM", "ACTIVE"}}
+	t.add(user.UserID, user.Username, user.TeamName, formatBool(user.IsActive))
+
+	return t
+}
+
+func runUserSetActive(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	isActive, err := strconv.ParseBool(args[1])
+	if err != nil {
+		return errUsage
+	}
+
+	req := struct {
+		UserID   string `json:"user_id"`
+		IsActive bool   `json:"is_active"`
+	}{UserID: args[0], IsActive: isActive}
+
+	var res userResponse
+	if err = env.client.request(ctx, http.MethodPost, "/users/setIsActive", nil, "", req, &res); err != nil {
+		return err
+	}
+
+	return env.printer.print(res, userTable(res.User))
+}
+
+func runUserReviews(ctx context.Context, env *cmdEnv, args []string) error {
+	flags := newFlagSet("user reviews")
+	archived := flags.Bool("archived", false, "include archived pull requests")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	query := url.Values{"user_id": {flags.Arg(0)}, "include_archived": {strconv.FormatBool(*archived)}}
+
+	var res struct {
+		UserID       string                    `json:"user_id"`
+		PullRequests []dto.PullRequestShortDTO `json:"pull_requests"`
+	}
+
+	if err := env.client.request(ctx, http.MethodGet, "/users/getReview", query, "", nil, &res); err != nil {
+		return err
+	}
+
+	t := table{header: []string{"PULL_REQUEST_ID", "NAME", "AUTHOR", "STATUS", "ARCHIVED"}}
+	for _, pr := range res.PullRequests {
+		t.add(pr.ID, pr.Name, pr.AuthorID, pr.Status, formatBool(pr.Archived))
+	}
+
+	return env.printer.print(res, t)
+}
+
+func runUserDelete(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	err := env.client.request(ctx, http.MethodPost, "/users/delete", nil, "", userIDRequest{UserID: args[0]}, nil)
+	if err != nil {
+		return err
+	}
+
+	return env.printer.message("user %s deleted", args[0])
+}
+
+func runUserRestore(ctx context.Context, env *cmdEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	var res userResponse
+
+	err := env.client.request(ctx, http.MethodPost, "/users/restore", nil, "", userIDRequest{UserID: args[0]}, &res)
+	if err != nil {
+		return err
+	}
+
+	return env.printer.print(res, userTable(res.User))
+}
@@ -0,0 +1,8 @@
+// Package docs embeds the OpenAPI document into the app binary, which
+// serves it and validates requests against it.
+package docs
+
+import _ "embed"
+
+//go:embed openapi.yml
+var OpenAPI []byte
@@ -0,0 +1,263 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/docs"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	deliverygrpc "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/grpc"
+	deliveryhttp "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/server"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/events"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/health"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/metrics"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/openapi"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/policy"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/ratelimit"
+	archiveservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/archive"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/cache"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/jsonl"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+)
+
+// App is the HTTP server with its services over opened storage.
+// Listeners and periodic jobs start only with RunBackground.
+type App struct {
+	Server *server.Server
+	// GRPCServer is nil unless GRPC_ENABLED is set.
+	GRPCServer *deliverygrpc.Server
+
+	cfg         *config.Config
+	storage     *Storage
+	eventBroker *events.Broker
+	// eventBridge and cacheBridge are nil unless enabled for postgres.
+	eventBridge *postgres.EventBridge
+	cacheBridge *postgres.CacheBridge
+	idempotency IdempotencyService
+	// archive is nil unless ARCHIVE_ENABLED is set.
+	archive   ArchiveService
+	readiness *health.Checker
+	policy    *policy.Store
+}
+
+func New(cfg *config.Config, st *Storage) (*App, error) {
+	policyStore, err := policy.NewStore(cfg.PolicyConfig.File)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &App{
+		cfg:         cfg,
+		storage:     st,
+		eventBroker: events.NewBroker(cfg.EventsConfig.SubscriberBufferSize),
+		policy:      policyStore,
+	}
+
+	var eventPublisher postgres.EventPublisher = a.eventBroker
+
+	if cfg.EventsConfig.PGBridgeEnabled && st.Pool != nil {
+		bridge, err := postgres.NewEventBridge(st.Pool, a.eventBroker, cfg.EventsConfig.PGBridgeChannel)
+		if err != nil {
+			return nil, err
+		}
+
+		a.eventBridge = bridge
+		eventPublisher = bridge
+	}
+
+	// serverMetrics stays a nil interface when metrics are disabled.
+	var (
+		appMetrics    *metrics.Metrics
+		serverMetrics server.Metrics
+	)
+
+	if cfg.MetricsConfig.Enabled {
+		appMetrics = metrics.New()
+		serverMetrics = appMetrics
+		eventPublisher = appMetrics.CountingPublisher(eventPublisher)
+	}
+
+	var (
+		teamCache *cache.Cache
+		notifier  cache.Notifier
+	)
+
+	if cfg.CacheConfig.Enabled {
+		teamCache = cache.New(cfg.CacheConfig.Size, cfg.CacheConfig.TTL)
+
+		if cfg.CacheConfig.PGInvalidationEnabled && st.Pool != nil {
+			a.cacheBridge = postgres.NewCacheBridge(st.Pool, teamCache, cfg.CacheConfig.PGInvalidationChannel)
+			notifier = a.cacheBridge
+		}
+	}
+
+	svc := st.Services(cfg, eventPublisher, teamCache, notifier, a.policy)
+
+	a.idempotency = svc.Idempotency
+
+	if appMetrics != nil {
+		appMetrics.CollectOpenPullRequests(svc.PullRequest)
+		appMetrics.CollectPools(st)
+	}
+
+	if cfg.ArchiveConfig.Enabled {
+		a.archive = svc.Archive
+	}
+
+	checks, err := st.ReadinessChecks()
+	if err != nil {
+		return nil, err
+	}
+
+	if a.eventBridge != nil || a.cacheBridge != nil {
+		checks = append(checks, health.Check{Name: "notify_queue", Run: func(ctx context.Context) error {
+			return postgres.CheckNotifyQueue(ctx, st.Pool, cfg.ReadinessConfig.MaxNotifyQueueUsage)
+		}})
+	}
+
+	a.readiness = health.NewChecker(cfg.ReadinessConfig.CheckTimeout, checks...)
+
+	apiDoc, err := openapi.Load(docs.OpenAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Server = server.NewServer(
+		svc.Team,
+		svc.User,
+		svc.PullRequest,
+		a.eventBroker,
+		deliveryhttp.NewAuth(cfg.AuthConfig.AdminToken, cfg.AuthConfig.UserToken),
+		svc.Idempotency,
+		svc.TxStats,
+		st,
+		teamCache,
+		svc.Snapshot,
+		a.policy,
+		a.readiness,
+		serverMetrics,
+		newLimits(cfg.LimitsConfig),
+		server.APISpec{
+			Raw:               docs.OpenAPI,
+			Document:          apiDoc,
+			ValidateResponses: cfg.OpenAPIConfig.ValidateResponses,
+		},
+		cfg.EventsConfig.HeartbeatInterval,
+	)
+
+	if cfg.GRPCConfig.Enabled {
+		a.GRPCServer = deliverygrpc.NewServer(
+			svc.Team,
+			svc.User,
+			svc.PullRequest,
+			a.eventBroker,
+			cfg.AuthConfig.AdminToken,
+			cfg.AuthConfig.UserToken,
+		)
+	}
+
+	return a, nil
+}
+
+// RunBackground starts the postgres listeners, the replica checks and the
+// periodic jobs. They stop when ctx is done.
+func (a *App) RunBackground(ctx context.Context) {
+	if a.eventBridge != nil {
+		go a.eventBridge.Listen(ctx)
+		go a.eventBridge.Run(ctx)
+	}
+
+	if a.cacheBridge != nil {
+		go a.cacheBridge.Listen(ctx)
+	}
+
+	if a.storage.ReadRouter != nil {
+		go a.storage.ReadRouter.Run(ctx, a.cfg.DBConfig.ReplicaCheckInterval)
+	}
+
+	go a.idempotency.RunCleanup(ctx, a.cfg.IdempotencyConfig.CleanupInterval)
+
+	if a.archive != nil {
+		go a.archive.RunArchival(ctx, a.cfg.ArchiveConfig.Interval)
+	}
+
+	go a.policy.Watch(ctx, a.cfg.PolicyConfig.WatchInterval)
+}
+
+// ReloadPolicy re-reads the policy file, e.g. on SIGHUP. An invalid file
+// is logged and the active policy is kept.
+func (a *App) ReloadPolicy() {
+	p, changed, err := a.policy.Reload()
+	if err != nil {
+		slog.Error("error reloading policy", slog.String("active_version", p.Version), logging.Error(err))
+		return
+	}
+
+	if changed {
+		slog.Info("policy reloaded", slog.String("version", p.Version), slog.String("trigger", "signal"))
+	} else {
+		slog.Info("policy unchanged", slog.String("version", p.Version))
+	}
+}
+
+// StartDraining makes /readyz and the gRPC health check fail, so that load
+// balancers stop sending requests before the servers are stopped.
+func (a *App) StartDraining() {
+	a.readiness.StartDraining()
+
+	if a.GRPCServer != nil {
+		a.GRPCServer.StartDraining()
+	}
+}
+
+// Close sends the events still queued for other instances and ends
+// the event streams of connected subscribers.
+func (a *App) Close(ctx context.Context) {
+	if a.eventBridge != nil {
+		if err := a.eventBridge.Flush(ctx); err != nil {
+			logging.FromContext(ctx).Error("error flushing events", logging.Error(err))
+		}
+	}
+
+	a.eventBroker.Close()
+}
+
+// newLimits builds a bucket set per overridden route, so that a flood on one
+// route does not use up the tokens of the others.
+func newLimits(cfg *config.LimitsConfig) server.Limits {
+	limits := server.Limits{
+		MaxBodyBytes: cfg.MaxBodyBytes,
+		RouteMaxBodyBytes: map[string]int64{
+			"POST /admin/import": cfg.MaxImportBodyBytes,
+			"POST /team/import":  cfg.MaxImportBodyBytes,
+		},
+	}
+
+	if !cfg.RateLimitEnabled {
+		return limits
+	}
+
+	limits.RateLimiter = ratelimit.New(cfg.RateLimit.Rate, cfg.RateLimit.Burst)
+	limits.RouteRateLimiters = make(map[string]deliveryhttp.RateLimiter, len(cfg.RouteRateLimits))
+
+	for route, limit := range cfg.RouteRateLimits {
+		limits.RouteRateLimiters[route] = ratelimit.New(limit.Rate, limit.Burst)
+	}
+
+	return limits
+}
+
+func newArchiveService[E any](
+	cfg *config.ArchiveConfig,
+	txManager archiveservice.TxManager[E],
+	repoFact archiveservice.RepoFactory[E],
+) *archiveservice.ArchiveService[E] {
+	var exporter archiveservice.Exporter
+	if cfg.Mode == config.ArchiveModeJSONL {
+		exporter = jsonl.NewExporter(cfg.ExportDir)
+	}
+
+	return archiveservice.NewArchiveService(txManager, repoFact, cfg.Retention, cfg.BatchSize, exporter)
+}
@@ -0,0 +1,286 @@
+// Package app wires storage and services into a running instance of the
+// service. It is shared by cmd/app and the maintenance mode of cmd/prctl.
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	deliveryhttp "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/handlers"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/health"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/repository"
+	idempotencyservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/idempotency"
+	pullrequestservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/pull_request"
+	snapshotservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/snapshot"
+	teamservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/team"
+	userservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/user"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/cache"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+	postgresrepo "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres/repo"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite"
+	sqliterepo "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite/repo"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/migrations"
+)
+
+type txManager[E any] interface {
+	pullrequestservice.TxManager[E]
+	Stats() store.TxStats
+}
+
+type repoFactory[E any] interface {
+	TeamRepository(exec E) repository.TeamRepository
+	UserRepository(exec E) repository.UserRepository
+	PullRequestRepository(exec E) repository.PullRequestRepository
+	IdempotencyRepository(exec E) repository.IdempotencyRepository
+	ArchiveRepository(exec E) repository.ArchiveRepository
+	SnapshotRepository(exec E) repository.SnapshotRepository
+}
+
+// backend is a storage backend whose transactions pass executors of type E
+// to its repositories.
+type backend[E comparable] struct {
+	txManager txManager[E]
+	readExec  E
+	// replicaReadExec serves reads that tolerate replication lag.
+	replicaReadExec E
+	repoFactory     repoFactory[E]
+}
+
+type IdempotencyService interface {
+	deliveryhttp.IdempotencyService
+	RunCleanup(ctx context.Context, interval time.Duration)
+}
+
+type PullRequestService interface {
+	handlers.PullRequestService
+	CountOpenByTeam(ctx context.Context) (map[string]int, error)
+}
+
+type ArchiveService interface {
+	ArchiveMerged(ctx context.Context) (int, error)
+	RunArchival(ctx context.Context, interval time.Duration)
+}
+
+// Services are the services built on top of the storage backend.
+type Services struct {
+	Team        handlers.TeamService
+	User        handlers.UserService
+	PullRequest PullRequestService
+	Idempotency IdempotencyService
+	Archive     ArchiveService
+	Snapshot    handlers.SnapshotService
+	TxStats     handlers.TxStatsProvider
+}
+
+func (b backend[E]) services(
+	cfg *config.Config,
+	publisher postgres.EventPublisher,
+	teamCache *cache.Cache,
+	notifier cache.Notifier,
+	policies pullrequestservice.PolicySource,
+) Services {
+	var repoFact repoFactory[E] = b.repoFactory
+	if teamCache != nil {
+		repoFact = cache.NewRepoFactory(b.repoFactory, b.readExec, teamCache, notifier)
+	}
+
+	return Services{
+		Team: teamservice.NewTeamService(b.txManager, b.replicaReadExec, repoFact, publisher),
+		User: userservice.NewUserService(b.txManager, b.replicaReadExec, repoFact, publisher),
+		PullRequest: pullrequestservice.NewPullRequestService(
+			b.txManager,
+			b.readExec,
+			repoFact,
+			publisher,
+			policies,
+		),
+		Idempotency: idempotencyservice.NewIdempotencyService(
+			b.txManager,
+			b.readExec,
+			repoFact,
+			cfg.IdempotencyConfig.TTL,
+			cfg.IdempotencyConfig.PendingLease,
+		),
+		Archive:  newArchiveService(cfg.ArchiveConfig, b.txManager, repoFact),
+		Snapshot: snapshotservice.NewSnapshotService(b.txManager, repoFact),
+		TxStats:  b.txManager,
+	}
+}
+
+// Storage is the opened data backend chosen by STORAGE_BACKEND.
+type Storage struct {
+	// newServices hides the executor type of the backend.
+	newServices func(
+		cfg *config.Config,
+		publisher postgres.EventPublisher,
+		teamCache *cache.Cache,
+		notifier cache.Notifier,
+		policies pullrequestservice.PolicySource,
+	) Services
+
+	// Pool is nil unless the data lives in Postgres.
+	Pool *pgxpool.Pool
+	// ReplicaPool is nil unless READ_DATABASE_URL is set.
+	ReplicaPool *pgxpool.Pool
+	// ReadRouter is nil unless the data lives in Postgres.
+	ReadRouter *postgres.ReadRouter
+	// SQLiteDB is nil unless the data lives in SQLite.
+	SQLiteDB *sql.DB
+}
+
+func OpenStorage(ctx context.Context, cfg *config.Config) (*Storage, error) {
+	switch cfg.StorageConfig.Backend {
+	case config.StorageBackendMemory:
+		memoryStore := memory.NewStore()
+
+		return &Storage{
+			newServices: backend[memory.Executor]{
+				txManager:       memory.NewTxManager(memoryStore),
+				readExec:        memoryStore,
+				replicaReadExec: memoryStore,
+				repoFactory:     memory.NewRepoFactory(),
+			}.services,
+		}, nil
+	case config.StorageBackendPostgres:
+		pool, err := postgres.NewPool(ctx, cfg.DBConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = prepareSchema(ctx, pool, cfg.DBConfig); err != nil {
+			pool.Close()
+			return nil, err
+		}
+
+		var replicaPool *pgxpool.Pool
+		if cfg.DBConfig.ReadDatabaseURL != "" {
+			replicaPool, err = postgres.NewReadPool(ctx, cfg.DBConfig)
+			if err != nil {
+				pool.Close()
+				return nil, fmt.Errorf("error opening read replica: %w", err)
+			}
+		}
+
+		readRouter := postgres.NewReadRouter(pool, replicaPool, cfg.DBConfig.ReplicaMaxLag)
+
+		return &Storage{
+			newServices: backend[postgres.Execer]{
+				txManager:       postgres.NewTxManager(pool, cfg.TxConfig),
+				readExec:        pool,
+				replicaReadExec: readRouter,
+				repoFactory:     postgresrepo.NewRepoFactory(postgres.NewStatementBuilder()),
+			}.services,
+			Pool:        pool,
+			ReplicaPool: replicaPool,
+			ReadRouter:  readRouter,
+		}, nil
+	case config.StorageBackendSQLite:
+		db, err := sqlite.Open(ctx, cfg.SQLiteConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Storage{
+			newServices: backend[sqlite.Execer]{
+				txManager:       sqlite.NewTxManager(db),
+				readExec:        db,
+				replicaReadExec: db,
+				repoFactory:     sqliterepo.NewRepoFactory(sqlite.NewStatementBuilder()),
+			}.services,
+			SQLiteDB: db,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageConfig.Backend)
+	}
+}
+
+// prepareSchema applies pending migrations when enabled and refuses
+// to start on a schema older than the binary.
+func prepareSchema(ctx context.Context, pool *pgxpool.Pool, cfg *config.DBConfig) error {
+	migrator, err := postgres.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	if cfg.MigrateOnStart {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("error migrating schema: %w", err)
+		}
+
+		logging.FromContext(ctx).Info("applied migrations", slog.Int("count", applied))
+	}
+
+	return migrator.CheckVersion(ctx)
+}
+
+// Services builds the services. teamCache is nil when the cache is disabled,
+// notifier is nil for a single instance, policies is nil for commands that
+// never assign reviewers.
+func (s *Storage) Services(
+	cfg *config.Config,
+	publisher postgres.EventPublisher,
+	teamCache *cache.Cache,
+	notifier cache.Notifier,
+	policies pullrequestservice.PolicySource,
+) Services {
+	return s.newServices(cfg, publisher, teamCache, notifier, policies)
+}
+
+// ReadinessChecks probe the database and its schema version. The in-memory
+// storage has nothing to check.
+func (s *Storage) ReadinessChecks() ([]health.Check, error) {
+	switch {
+	case s.Pool != nil:
+		migrator, err := postgres.NewMigrator(s.Pool, migrations.FS)
+		if err != nil {
+			return nil, err
+		}
+
+		return []health.Check{
+			{Name: "database", Run: s.Pool.Ping},
+			{Name: "schema", Run: migrator.CheckVersion},
+		}, nil
+	case s.SQLiteDB != nil:
+		return []health.Check{
+			{Name: "database", Run: s.SQLiteDB.PingContext},
+			{Name: "schema", Run: func(ctx context.Context) error {
+				return sqlite.CheckVersion(ctx, s.SQLiteDB)
+			}},
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// PoolStats returns no stats unless the data lives in Postgres.
+func (s *Storage) PoolStats() []store.PoolStats {
+	if s.ReadRouter == nil {
+		return []store.PoolStats{}
+	}
+
+	return s.ReadRouter.PoolStats()
+}
+
+func (s *Storage) Close() {
+	if s.ReplicaPool != nil {
+		s.ReplicaPool.Close()
+	}
+
+	if s.Pool != nil {
+		s.Pool.Close()
+	}
+
+	if s.SQLiteDB != nil {
+		_ = s.SQLiteDB.Close()
+	}
+}
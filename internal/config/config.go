@@ -1,16 +1,53 @@
 package config
 
 import (
-	"fmt"
+	"log/slog"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
+	StorageConfig   *StorageConfig
 	DBConfig        *DBConfig
+	SQLiteConfig    *SQLiteConfig
+	TxConfig        *TxConfig
 	WebServerConfig *WebServerConfig
 	AuthConfig      *AuthConfig
+	EventsConfig    *EventsConfig
+
+	IdempotencyConfig *IdempotencyConfig
+	CacheConfig       *CacheConfig
+	ArchiveConfig     *ArchiveConfig
+	MetricsConfig     *MetricsConfig
+	TracingConfig     *TracingConfig
+	LogConfig         *LogConfig
+	ReadinessConfig   *ReadinessConfig
+	PolicyConfig      *PolicyConfig
+	LimitsConfig      *LimitsConfig
+	OpenAPIConfig     *OpenAPIConfig
+	GRPCConfig        *GRPCConfig
+
+	// entries are the settings as read, for `app config print`.
+	entries []Entry
+}
+
+type StorageBackend string
+
+const (
+	StorageBackendPostgres StorageBackend = "postgres"
+	StorageBackendMemory   StorageBackend = "memory"
+	StorageBackendSQLite   StorageBackend = "sqlite"
+)
+
+type StorageConfig struct {
+	Backend StorageBackend
+}
+
+type SQLiteConfig struct {
+	Path        string
+	BusyTimeout time.Duration
 }
 
 type DBConfig struct {
@@ -18,6 +55,18 @@ type DBConfig struct {
 	MaxConns            int32
 	MinConns            int32
 	HealthCheckInterval time.Duration
+	MigrateOnStart      bool
+
+	// ReadDatabaseURL is empty when reads are served by the primary.
+	ReadDatabaseURL      string
+	ReplicaMaxLag        time.Duration
+	ReplicaCheckInterval time.Duration
+}
+
+type TxConfig struct {
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
 }
 
 type WebServerConfig struct {
@@ -25,6 +74,23 @@ type WebServerConfig struct {
 	Port    int
 
 	ShutdownTimeout time.Duration
+	// DrainDelay is how long /readyz reports not ready before the server
+	// stops accepting connections on shutdown.
+	DrainDelay time.Duration
+}
+
+type ReadinessConfig struct {
+	CheckTimeout time.Duration
+	// MaxNotifyQueueUsage is the share of the Postgres NOTIFY queue (0..1)
+	// above which the bridges are considered backlogged.
+	MaxNotifyQueueUsage float64
+}
+
+type PolicyConfig struct {
+	// File is the YAML or TOML assignment policy, empty for the default policy.
+	File string
+	// WatchInterval is how often File is checked for changes, 0 disables it.
+	WatchInterval time.Duration
 }
 
 type AuthConfig struct {
@@ -32,134 +98,528 @@ type AuthConfig struct {
 	UserToken  string
 }
 
-func envOnly(key string) (string, error) {
-	value, ok := os.LookupEnv(key)
-	if !ok {
-		return "", fmt.Errorf("env var %s not found", key)
-	}
-	return value, nil
+type EventsConfig struct {
+	SubscriberBufferSize int
+	HeartbeatInterval    time.Duration
+
+	PGBridgeEnabled bool
+	PGBridgeChannel string
 }
 
-func envOrDefault(key, defaultValue string) string {
-	value, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
-	}
-	return value
+type CacheConfig struct {
+	Enabled bool
+	Size    int
+	TTL     time.Duration
+
+	PGInvalidationEnabled bool
+	PGInvalidationChannel string
 }
 
-func intEnvOrDefault(key string, defaultValue int) (int, error) {
-	value, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue, nil
-	}
-	intValue, err := strconv.Atoi(value)
-	if err != nil {
-		return 0, fmt.Errorf("error parsing int env var %s: %w", key, err)
-	}
-	return intValue, nil
+type ArchiveMode string
+
+const (
+	ArchiveModeTable ArchiveMode = "table"
+	ArchiveModeJSONL ArchiveMode = "jsonl"
+)
+
+type ArchiveConfig struct {
+	// Enabled turns on the scheduled job; `app archive` works regardless.
+	Enabled   bool
+	Retention time.Duration
+	Interval  time.Duration
+	BatchSize int
+	Mode      ArchiveMode
+	ExportDir string
 }
 
-func int32EnvOrDefault(key string, defaultValue int32) (int32, error) {
-	value, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue, nil
-	}
-	intValue, err := strconv.ParseInt(value, 10, 32)
-	if err != nil {
-		return 0, fmt.Errorf("error parsing int32 env var %s: %w", key, err)
-	}
-	return int32(intValue), nil
+type TracingExporter string
+
+const (
+	TracingExporterNone   TracingExporter = "none"
+	TracingExporterOTLP   TracingExporter = "otlp"
+	TracingExporterFile   TracingExporter = "file"
+	TracingExporterStdout TracingExporter = "stdout"
+)
+
+type TracingConfig struct {
+	Exporter    TracingExporter
+	ServiceName string
+	// OTLPEndpoint is host:port of an OTLP/HTTP collector. When it is empty
+	// and OTEL_EXPORTER_OTLP_ENDPOINT is not set, spans go to FilePath.
+	OTLPEndpoint  string
+	OTLPInsecure  bool
+	FilePath      string
+	SamplePercent int
+}
+
+type LogConfig struct {
+	Level slog.Level
+}
+
+// RateLimit is a token bucket: Rate tokens per second, up to Burst at once.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type LimitsConfig struct {
+	RateLimitEnabled bool
+	RateLimit        RateLimit
+	// RouteRateLimits replace RateLimit for routes keyed like "POST /pullRequest/create".
+	RouteRateLimits map[string]RateLimit
+	// MaxBodyBytes bounds request bodies, except the imports bounded by MaxImportBodyBytes.
+	MaxBodyBytes       int64
+	MaxImportBodyBytes int64
+}
+
+type OpenAPIConfig struct {
+	// ValidateResponses logs responses that do not match docs/openapi.yml.
+	ValidateResponses bool
+}
+
+// GRPCConfig is the gRPC API, served next to the HTTP API on its own port.
+type GRPCConfig struct {
+	Enabled bool
+	Address string
+	Port    int
+}
+
+type MetricsConfig struct {
+	Enabled bool
+}
+
+type IdempotencyConfig struct {
+	TTL time.Duration
+	// PendingLease is how long a key whose request has not completed blocks
+	// retries with REQUEST_IN_PROGRESS.
+	PendingLease    time.Duration
+	CleanupInterval time.Duration
 }
 
+// Load reads the configuration from the environment, then from the optional
+// YAML or TOML file named by CONFIG_FILE, then falls back to defaults.
+// It returns a *ValidationError listing every invalid setting.
 func Load() (*Config, error) {
-	dbCfg, err := loadDBConfig()
-	if err != nil {
-		return nil, err
-	}
+	fileName := os.Getenv("CONFIG_FILE")
 
-	webServerCfg, err := loadWebServerConfig()
+	file, err := readFile(fileName)
 	if err != nil {
 		return nil, err
 	}
 
-	authCfg, err := loadAuthConfig()
-	if err != nil {
-		return nil, err
+	l := newLoader(file, fileName)
+
+	storageCfg := loadStorageConfig(l)
+
+	// The settings of a backend are required only when the data lives there.
+	dbCfg := loadDBConfig(l.section(storageCfg.Backend == StorageBackendPostgres))
+	sqliteCfg := loadSQLiteConfig(l.section(storageCfg.Backend == StorageBackendSQLite))
+
+	cfg := &Config{
+		StorageConfig:     storageCfg,
+		DBConfig:          dbCfg,
+		SQLiteConfig:      sqliteCfg,
+		TxConfig:          loadTxConfig(l),
+		WebServerConfig:   loadWebServerConfig(l),
+		AuthConfig:        loadAuthConfig(l),
+		EventsConfig:      loadEventsConfig(l),
+		IdempotencyConfig: loadIdempotencyConfig(l),
+		CacheConfig:       loadCacheConfig(l),
+		ArchiveConfig:     loadArchiveConfig(l),
+		MetricsConfig:     loadMetricsConfig(l),
+		TracingConfig:     loadTracingConfig(l),
+		LogConfig:         loadLogConfig(l),
+		ReadinessConfig:   loadReadinessConfig(l),
+		PolicyConfig:      loadPolicyConfig(l),
+		LimitsConfig:      loadLimitsConfig(l),
+		OpenAPIConfig:     loadOpenAPIConfig(l),
 	}
 
-	return &Config{
-		DBConfig:        dbCfg,
-		WebServerConfig: webServerCfg,
-		AuthConfig:      authCfg,
-	}, nil
-}
+	grpcEnabled := l.bool("GRPC_ENABLED", defaultGRPCEnabled)
+	cfg.GRPCConfig = loadGRPCConfig(l.section(grpcEnabled), grpcEnabled, cfg.WebServerConfig)
 
-func loadDBConfig() (*DBConfig, error) {
-	databaseURL, err := envOnly("DATABASE_URL")
-	if err != nil {
-		return nil, err
+	if storageCfg.Backend != StorageBackendPostgres {
+		cfg.DBConfig = nil
 	}
 
-	maxConns, err := int32EnvOrDefault("MAX_CONNS", defaultMaxConns)
-	if err != nil {
-		return nil, err
+	if storageCfg.Backend != StorageBackendSQLite {
+		cfg.SQLiteConfig = nil
 	}
 
-	minConns, err := int32EnvOrDefault("MIN_CONNS", defaultMinConns)
-	if err != nil {
+	l.checkFileKeys()
+
+	if err = l.err(); err != nil {
 		return nil, err
 	}
 
-	healthCheckIntervalInSeconds, err := intEnvOrDefault(
-		"HEALTH_CHECK_INTERVAL_IN_SECONDS",
-		defaultHealthCheckIntervalInSeconds,
+	cfg.entries = *l.entries
+
+	return cfg, nil
+}
+
+func loadStorageConfig(l *loader) *StorageConfig {
+	backend := StorageBackend(l.string("STORAGE_BACKEND", string(defaultStorageBackend)))
+	l.oneOf("STORAGE_BACKEND", string(backend),
+		string(StorageBackendPostgres), string(StorageBackendMemory), string(StorageBackendSQLite),
 	)
-	if err != nil {
-		return nil, err
+
+	return &StorageConfig{
+		Backend: backend,
 	}
+}
+
+func loadDBConfig(l *loader) *DBConfig {
+	databaseURL := l.required("DATABASE_URL")
+
+	maxConns := l.int32("MAX_CONNS", defaultMaxConns)
+	l.positive("MAX_CONNS", int(maxConns))
+
+	minConns := l.int32("MIN_CONNS", defaultMinConns)
+	l.nonNegative("MIN_CONNS", int(minConns))
+
+	if minConns > maxConns {
+		l.problemf("MIN_CONNS (%d) must not exceed MAX_CONNS (%d)", minConns, maxConns)
+	}
+
+	healthCheckIntervalInSeconds := l.int("HEALTH_CHECK_INTERVAL_IN_SECONDS", defaultHealthCheckIntervalInSeconds)
+	l.positive("HEALTH_CHECK_INTERVAL_IN_SECONDS", healthCheckIntervalInSeconds)
+
+	migrateOnStart := l.bool("MIGRATE_ON_START", defaultMigrateOnStart)
+
+	readDatabaseURL := l.string("READ_DATABASE_URL", defaultReadDatabaseURL)
+
+	replicaMaxLagInMs := l.int("REPLICA_MAX_LAG_IN_MS", defaultReplicaMaxLagInMs)
+	l.nonNegative("REPLICA_MAX_LAG_IN_MS", replicaMaxLagInMs)
+
+	replicaCheckIntervalInSeconds := l.int("REPLICA_CHECK_INTERVAL_IN_SECONDS", defaultReplicaCheckIntervalInSeconds)
+	l.positive("REPLICA_CHECK_INTERVAL_IN_SECONDS", replicaCheckIntervalInSeconds)
 
 	return &DBConfig{
-		DatabaseURL:         databaseURL,
-		MaxConns:            maxConns,
-		MinConns:            minConns,
-		HealthCheckInterval: time.Duration(healthCheckIntervalInSeconds) * time.Second,
-	}, nil
+		DatabaseURL:          databaseURL,
+		MaxConns:             maxConns,
+		MinConns:             minConns,
+		HealthCheckInterval:  time.Duration(healthCheckIntervalInSeconds) * time.Second,
+		MigrateOnStart:       migrateOnStart,
+		ReadDatabaseURL:      readDatabaseURL,
+		ReplicaMaxLag:        time.Duration(replicaMaxLagInMs) * time.Millisecond,
+		ReplicaCheckInterval: time.Duration(replicaCheckIntervalInSeconds) * time.Second,
+	}
 }
 
-func loadWebServerConfig() (*WebServerConfig, error) {
-	webServerAddress := envOrDefault("WEB_SERVER_ADDRESS", defaultAddress)
+func loadSQLiteConfig(l *loader) *SQLiteConfig {
+	path := l.string("SQLITE_PATH", defaultSQLitePath)
+	if path == "" {
+		l.problemf("SQLITE_PATH must not be empty")
+	}
 
-	webServerPort, err := intEnvOrDefault("WEB_SERVER_PORT", defaultPort)
-	if err != nil {
-		return nil, err
+	busyTimeoutInMs := l.int("SQLITE_BUSY_TIMEOUT_IN_MS", defaultSQLiteBusyTimeoutInMs)
+	l.nonNegative("SQLITE_BUSY_TIMEOUT_IN_MS", busyTimeoutInMs)
+
+	return &SQLiteConfig{
+		Path:        path,
+		BusyTimeout: time.Duration(busyTimeoutInMs) * time.Millisecond,
 	}
+}
 
-	shutdownTimeoutInSeconds, err := intEnvOrDefault("SHUTDOWN_TIMEOUT_IN_SECONDS", defaultShutdownTimeoutInSeconds)
-	if err != nil {
-		return nil, err
+func loadTxConfig(l *loader) *TxConfig {
+	maxRetries := l.int("TX_MAX_RETRIES", defaultTxMaxRetries)
+	l.nonNegative("TX_MAX_RETRIES", maxRetries)
+
+	retryBaseDelayInMs := l.int("TX_RETRY_BASE_DELAY_IN_MS", defaultTxRetryBaseDelayInMs)
+	l.positive("TX_RETRY_BASE_DELAY_IN_MS", retryBaseDelayInMs)
+
+	retryMaxDelayInMs := l.int("TX_RETRY_MAX_DELAY_IN_MS", defaultTxRetryMaxDelayInMs)
+	if retryMaxDelayInMs < retryBaseDelayInMs {
+		l.problemf(
+			"TX_RETRY_MAX_DELAY_IN_MS (%d) must not be less than TX_RETRY_BASE_DELAY_IN_MS (%d)",
+			retryMaxDelayInMs,
+			retryBaseDelayInMs,
+		)
+	}
+
+	return &TxConfig{
+		MaxRetries:     maxRetries,
+		RetryBaseDelay: time.Duration(retryBaseDelayInMs) * time.Millisecond,
+		RetryMaxDelay:  time.Duration(retryMaxDelayInMs) * time.Millisecond,
 	}
+}
+
+func loadWebServerConfig(l *loader) *WebServerConfig {
+	webServerAddress := l.string("WEB_SERVER_ADDRESS", defaultAddress)
+
+	webServerPort := l.int("WEB_SERVER_PORT", defaultPort)
+	l.between("WEB_SERVER_PORT", webServerPort, 1, 65535)
+
+	shutdownTimeoutInSeconds := l.int("SHUTDOWN_TIMEOUT_IN_SECONDS", defaultShutdownTimeoutInSeconds)
+	l.positive("SHUTDOWN_TIMEOUT_IN_SECONDS", shutdownTimeoutInSeconds)
+
+	drainDelayInSeconds := l.int("SHUTDOWN_DRAIN_DELAY_IN_SECONDS", defaultShutdownDrainDelayInSeconds)
+	l.nonNegative("SHUTDOWN_DRAIN_DELAY_IN_SECONDS", drainDelayInSeconds)
 
 	return &WebServerConfig{
 		Address:         webServerAddress,
 		Port:            webServerPort,
 		ShutdownTimeout: time.Duration(shutdownTimeoutInSeconds) * time.Second,
-	}, nil
+		DrainDelay:      time.Duration(drainDelayInSeconds) * time.Second,
+	}
 }
 
-func loadAuthConfig() (*AuthConfig, error) {
-	adminToken, err := envOnly("ADMIN_TOKEN")
-	if err != nil {
-		return nil, err
+func loadReadinessConfig(l *loader) *ReadinessConfig {
+	checkTimeoutInMs := l.int("READINESS_CHECK_TIMEOUT_IN_MS", defaultReadinessCheckTimeoutInMs)
+	l.positive("READINESS_CHECK_TIMEOUT_IN_MS", checkTimeoutInMs)
+
+	maxNotifyQueuePercent := l.int("READINESS_MAX_NOTIFY_QUEUE_PERCENT", defaultReadinessMaxNotifyQueuePercent)
+	l.between("READINESS_MAX_NOTIFY_QUEUE_PERCENT", maxNotifyQueuePercent, 0, 100)
+
+	return &ReadinessConfig{
+		CheckTimeout:        time.Duration(checkTimeoutInMs) * time.Millisecond,
+		MaxNotifyQueueUsage: float64(maxNotifyQueuePercent) / 100,
 	}
+}
 
-	userToken, err := envOnly("USER_TOKEN")
-	if err != nil {
-		return nil, err
+func loadPolicyConfig(l *loader) *PolicyConfig {
+	watchIntervalInSeconds := l.int("POLICY_WATCH_INTERVAL_IN_SECONDS", defaultPolicyWatchIntervalInSeconds)
+	l.nonNegative("POLICY_WATCH_INTERVAL_IN_SECONDS", watchIntervalInSeconds)
+
+	return &PolicyConfig{
+		File:          l.string("POLICY_FILE", defaultPolicyFile),
+		WatchInterval: time.Duration(watchIntervalInSeconds) * time.Second,
 	}
+}
+
+func loadLimitsConfig(l *loader) *LimitsConfig {
+	rate := l.float("RATE_LIMIT_RPS", defaultRateLimitRPS)
+	if rate <= 0 {
+		l.problemf("RATE_LIMIT_RPS must be positive, got %g", rate)
+	}
+
+	burst := l.int("RATE_LIMIT_BURST", defaultRateLimitBurst)
+	l.positive("RATE_LIMIT_BURST", burst)
+
+	maxBodyBytes := l.int("MAX_BODY_BYTES", defaultMaxBodyBytes)
+	l.positive("MAX_BODY_BYTES", maxBodyBytes)
+
+	maxImportBodyBytes := l.int("MAX_IMPORT_BODY_BYTES", defaultMaxImportBodyBytes)
+	l.positive("MAX_IMPORT_BODY_BYTES", maxImportBodyBytes)
 
+	rawRouteRateLimits := l.string("RATE_LIMIT_ROUTES", defaultRateLimitRoutes)
+	routeRateLimits := parseRouteRateLimits(l, "RATE_LIMIT_ROUTES", rawRouteRateLimits)
+
+	return &LimitsConfig{
+		RateLimitEnabled:   l.bool("RATE_LIMIT_ENABLED", defaultRateLimitEnabled),
+		RateLimit:          RateLimit{Rate: rate, Burst: burst},
+		RouteRateLimits:    routeRateLimits,
+		MaxBodyBytes:       int64(maxBodyBytes),
+		MaxImportBodyBytes: int64(maxImportBodyBytes),
+	}
+}
+
+func loadOpenAPIConfig(l *loader) *OpenAPIConfig {
+	return &OpenAPIConfig{
+		ValidateResponses: l.bool("OPENAPI_VALIDATE_RESPONSES", defaultOpenAPIValidateResponses),
+	}
+}
+
+func loadGRPCConfig(l *loader, enabled bool, webServerCfg *WebServerConfig) *GRPCConfig {
+	address := l.string("GRPC_ADDRESS", defaultGRPCAddress)
+
+	port := l.int("GRPC_PORT", defaultGRPCPort)
+	l.between("GRPC_PORT", port, 1, 65535)
+
+	if port == webServerCfg.Port && address == webServerCfg.Address {
+		l.problemf("GRPC_PORT must differ from WEB_SERVER_PORT, got %d", port)
+	}
+
+	return &GRPCConfig{
+		Enabled: enabled,
+		Address: address,
+		Port:    port,
+	}
+}
+
+// parseRouteRateLimits parses "METHOD /path=rate:burst" entries separated by commas,
+// e.g. "POST /pullRequest/create=1:5,POST /admin/import=0.1:1".
+func parseRouteRateLimits(l *loader, key, raw string) map[string]RateLimit {
+	limits := map[string]RateLimit{}
+
+	for entry := range strings.SplitSeq(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		route, limit, ok := strings.Cut(entry, "=")
+		method, path, okRoute := strings.Cut(strings.TrimSpace(route), " ")
+		rawRate, rawBurst, okLimit := strings.Cut(limit, ":")
+
+		if !ok || !okRoute || !okLimit || method == "" || !strings.HasPrefix(path, "/") {
+			l.problemf("%s entry %q must look like \"POST /path=rate:burst\"", key, entry)
+			continue
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rawRate), 64)
+		if err != nil || rate <= 0 {
+			l.problemf("%s entry %q must have a positive rate", key, entry)
+			continue
+		}
+
+		burst, err := strconv.Atoi(strings.TrimSpace(rawBurst))
+		if err != nil || burst <= 0 {
+			l.problemf("%s entry %q must have a positive burst", key, entry)
+			continue
+		}
+
+		limits[strings.ToUpper(method)+" "+path] = RateLimit{Rate: rate, Burst: burst}
+	}
+
+	return limits
+}
+
+func loadAuthConfig(l *loader) *AuthConfig {
 	return &AuthConfig{
-		AdminToken: adminToken,
-		UserToken:  userToken,
-	}, nil
+		AdminToken: l.required("ADMIN_TOKEN"),
+		UserToken:  l.required("USER_TOKEN"),
+	}
+}
+
+func loadEventsConfig(l *loader) *EventsConfig {
+	subscriberBufferSize := l.int("EVENTS_SUBSCRIBER_BUFFER_SIZE", defaultEventsSubscriberBufferSize)
+	l.positive("EVENTS_SUBSCRIBER_BUFFER_SIZE", subscriberBufferSize)
+
+	heartbeatIntervalInSeconds := l.int("EVENTS_HEARTBEAT_INTERVAL_IN_SECONDS", defaultEventsHeartbeatIntervalInSeconds)
+	l.positive("EVENTS_HEARTBEAT_INTERVAL_IN_SECONDS", heartbeatIntervalInSeconds)
+
+	pgBridgeEnabled := l.bool("EVENTS_PG_BRIDGE_ENABLED", defaultEventsPGBridgeEnabled)
+
+	pgBridgeChannel := l.string("EVENTS_PG_BRIDGE_CHANNEL", defaultEventsPGBridgeChannel)
+	if pgBridgeEnabled && pgBridgeChannel == "" {
+		l.problemf("EVENTS_PG_BRIDGE_CHANNEL must not be empty when EVENTS_PG_BRIDGE_ENABLED is set")
+	}
+
+	return &EventsConfig{
+		SubscriberBufferSize: subscriberBufferSize,
+		HeartbeatInterval:    time.Duration(heartbeatIntervalInSeconds) * time.Second,
+		PGBridgeEnabled:      pgBridgeEnabled,
+		PGBridgeChannel:      pgBridgeChannel,
+	}
+}
+
+func loadIdempotencyConfig(l *loader) *IdempotencyConfig {
+	ttlInSeconds := l.int("IDEMPOTENCY_KEY_TTL_IN_SECONDS", defaultIdempotencyKeyTTLInSeconds)
+	l.positive("IDEMPOTENCY_KEY_TTL_IN_SECONDS", ttlInSeconds)
+
+	pendingLeaseInSeconds := l.int(
+		"IDEMPOTENCY_PENDING_LEASE_IN_SECONDS",
+		defaultIdempotencyPendingLeaseInSeconds,
+	)
+	l.positive("IDEMPOTENCY_PENDING_LEASE_IN_SECONDS", pendingLeaseInSeconds)
+
+	cleanupIntervalInSeconds := l.int(
+		"IDEMPOTENCY_CLEANUP_INTERVAL_IN_SECONDS",
+		defaultIdempotencyCleanupIntervalInSeconds,
+	)
+	l.positive("IDEMPOTENCY_CLEANUP_INTERVAL_IN_SECONDS", cleanupIntervalInSeconds)
+
+	return &IdempotencyConfig{
+		TTL:             time.Duration(ttlInSeconds) * time.Second,
+		PendingLease:    time.Duration(pendingLeaseInSeconds) * time.Second,
+		CleanupInterval: time.Duration(cleanupIntervalInSeconds) * time.Second,
+	}
+}
+
+func loadCacheConfig(l *loader) *CacheConfig {
+	enabled := l.bool("CACHE_ENABLED", defaultCacheEnabled)
+
+	size := l.int("CACHE_SIZE", defaultCacheSize)
+	l.positive("CACHE_SIZE", size)
+
+	ttlInSeconds := l.int("CACHE_TTL_IN_SECONDS", defaultCacheTTLInSeconds)
+	l.positive("CACHE_TTL_IN_SECONDS", ttlInSeconds)
+
+	pgInvalidationEnabled := l.bool("CACHE_PG_INVALIDATION_ENABLED", defaultCachePGInvalidationEnabled)
+
+	pgInvalidationChannel := l.string("CACHE_PG_INVALIDATION_CHANNEL", defaultCachePGInvalidationChannel)
+	if pgInvalidationEnabled && pgInvalidationChannel == "" {
+		l.problemf("CACHE_PG_INVALIDATION_CHANNEL must not be empty when CACHE_PG_INVALIDATION_ENABLED is set")
+	}
+
+	return &CacheConfig{
+		Enabled:               enabled,
+		Size:                  size,
+		TTL:                   time.Duration(ttlInSeconds) * time.Second,
+		PGInvalidationEnabled: pgInvalidationEnabled,
+		PGInvalidationChannel: pgInvalidationChannel,
+	}
+}
+
+func loadArchiveConfig(l *loader) *ArchiveConfig {
+	enabled := l.bool("ARCHIVE_ENABLED", defaultArchiveEnabled)
+
+	retentionInDays := l.int("ARCHIVE_RETENTION_IN_DAYS", defaultArchiveRetentionInDays)
+	l.nonNegative("ARCHIVE_RETENTION_IN_DAYS", retentionInDays)
+
+	intervalInSeconds := l.int("ARCHIVE_INTERVAL_IN_SECONDS", defaultArchiveIntervalInSeconds)
+	l.positive("ARCHIVE_INTERVAL_IN_SECONDS", intervalInSeconds)
+
+	batchSize := l.int("ARCHIVE_BATCH_SIZE", defaultArchiveBatchSize)
+	l.positive("ARCHIVE_BATCH_SIZE", batchSize)
+
+	mode := ArchiveMode(l.string("ARCHIVE_MODE", string(defaultArchiveMode)))
+	l.oneOf("ARCHIVE_MODE", string(mode), string(ArchiveModeTable), string(ArchiveModeJSONL))
+
+	exportDir := l.string("ARCHIVE_EXPORT_DIR", defaultArchiveExportDir)
+
+	return &ArchiveConfig{
+		Enabled:   enabled,
+		Retention: time.Duration(retentionInDays) * 24 * time.Hour,
+		Interval:  time.Duration(intervalInSeconds) * time.Second,
+		BatchSize: batchSize,
+		Mode:      mode,
+		ExportDir: exportDir,
+	}
+}
+
+func loadMetricsConfig(l *loader) *MetricsConfig {
+	return &MetricsConfig{
+		Enabled: l.bool("METRICS_ENABLED", defaultMetricsEnabled),
+	}
+}
+
+func loadTracingConfig(l *loader) *TracingConfig {
+	exporter := TracingExporter(l.string("TRACING_EXPORTER", string(defaultTracingExporter)))
+	l.oneOf("TRACING_EXPORTER", string(exporter),
+		string(TracingExporterNone), string(TracingExporterOTLP),
+		string(TracingExporterFile), string(TracingExporterStdout),
+	)
+
+	serviceName := l.string("TRACING_SERVICE_NAME", defaultTracingServiceName)
+	if serviceName == "" {
+		l.problemf("TRACING_SERVICE_NAME must not be empty")
+	}
+
+	samplePercent := l.int("TRACING_SAMPLE_PERCENT", defaultTracingSamplePercent)
+	l.between("TRACING_SAMPLE_PERCENT", samplePercent, 0, 100)
+
+	return &TracingConfig{
+		Exporter:      exporter,
+		ServiceName:   serviceName,
+		OTLPEndpoint:  l.string("TRACING_OTLP_ENDPOINT", ""),
+		OTLPInsecure:  l.bool("TRACING_OTLP_INSECURE", defaultTracingOTLPInsecure),
+		FilePath:      l.string("TRACING_FILE_PATH", defaultTracingFilePath),
+		SamplePercent: samplePercent,
+	}
+}
+
+func loadLogConfig(l *loader) *LogConfig {
+	var level slog.Level
+
+	// Accepts debug, info, warn and error, optionally with an offset like warn+2.
+	rawLevel := l.string("LOG_LEVEL", defaultLogLevel)
+	if err := level.UnmarshalText([]byte(rawLevel)); err != nil {
+		l.problemf("LOG_LEVEL must be debug, info, warn or error, got %q", rawLevel)
+	}
+
+	return &LogConfig{
+		Level: level,
+	}
 }
@@ -0,0 +1,308 @@
+package config_test
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+)
+
+// setEnv sets the required settings for the memory backend plus overrides.
+func setEnv(t *testing.T, overrides map[string]string) {
+	t.Helper()
+
+	env := map[string]string{
+		"CONFIG_FILE":     "",
+		"STORAGE_BACKEND": "memory",
+		"ADMIN_TOKEN":     "admin-secret",
+		"USER_TOKEN":      "user-secret",
+	}
+	for key, value := range overrides {
+		env[key] = value
+	}
+
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	return path
+}
+
+func problemsOf(t *testing.T, err error) []string {
+	t.Helper()
+
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("got error %v, want *config.ValidationError", err)
+	}
+
+	return validationErr.Problems
+}
+
+func TestLoadDefaults(t *testing.T) {
+	setEnv(t, nil)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if cfg.DBConfig != nil || cfg.SQLiteConfig != nil {
+		t.Errorf("got storage configs %+v and %+v for the memory backend, want nil", cfg.DBConfig, cfg.SQLiteConfig)
+	}
+
+	if cfg.WebServerConfig.Port != 8080 {
+		t.Errorf("got port %d, want 8080", cfg.WebServerConfig.Port)
+	}
+
+	if cfg.IdempotencyConfig.PendingLease != time.Minute {
+		t.Errorf("got pending lease %v, want 1m", cfg.IdempotencyConfig.PendingLease)
+	}
+
+	wantRoutes := map[string]config.RateLimit{"POST /pullRequest/create": {Rate: 10, Burst: 20}}
+	if !maps.Equal(cfg.LimitsConfig.RouteRateLimits, wantRoutes) {
+		t.Errorf("got route rate limits %v, want %v", cfg.LimitsConfig.RouteRateLimits, wantRoutes)
+	}
+
+	if cfg.ReadinessConfig.MaxNotifyQueueUsage != 0.5 {
+		t.Errorf("got max notify queue usage %v, want 0.5", cfg.ReadinessConfig.MaxNotifyQueueUsage)
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		problem string
+	}{
+		{
+			name:    "missing token",
+			env:     map[string]string{"ADMIN_TOKEN": ""},
+			problem: "ADMIN_TOKEN is required",
+		},
+		{
+			name:    "unknown backend",
+			env:     map[string]string{"STORAGE_BACKEND": "mysql"},
+			problem: `STORAGE_BACKEND must be one of postgres, memory, sqlite, got "mysql"`,
+		},
+		{
+			name:    "postgres without url",
+			env:     map[string]string{"STORAGE_BACKEND": "postgres", "DATABASE_URL": ""},
+			problem: "DATABASE_URL is required",
+		},
+		{
+			name: "min conns above max",
+			env: map[string]string{
+				"STORAGE_BACKEND": "postgres",
+				"DATABASE_URL":    "postgres://db",
+				"MIN_CONNS":       "20",
+			},
+			problem: "MIN_CONNS (20) must not exceed MAX_CONNS (10)",
+		},
+		{
+			name:    "not an integer",
+			env:     map[string]string{"WEB_SERVER_PORT": "http"},
+			problem: `WEB_SERVER_PORT must be an integer, got "http" in env`,
+		},
+		{
+			name:    "port out of range",
+			env:     map[string]string{"WEB_SERVER_PORT": "70000"},
+			problem: "WEB_SERVER_PORT must be between 1 and 65535, got 70000",
+		},
+		{
+			name:    "not a boolean",
+			env:     map[string]string{"CACHE_ENABLED": "sometimes"},
+			problem: `CACHE_ENABLED must be a boolean, got "sometimes" in env`,
+		},
+		{
+			name:    "zero pending lease",
+			env:     map[string]string{"IDEMPOTENCY_PENDING_LEASE_IN_SECONDS": "0"},
+			problem: "IDEMPOTENCY_PENDING_LEASE_IN_SECONDS must be positive, got 0",
+		},
+		{
+			name:    "max delay below base",
+			env:     map[string]string{"TX_RETRY_BASE_DELAY_IN_MS": "100", "TX_RETRY_MAX_DELAY_IN_MS": "50"},
+			problem: "TX_RETRY_MAX_DELAY_IN_MS (50) must not be less than TX_RETRY_BASE_DELAY_IN_MS (100)",
+		},
+		{
+			name:    "bridge without channel",
+			env:     map[string]string{"EVENTS_PG_BRIDGE_ENABLED": "true", "EVENTS_PG_BRIDGE_CHANNEL": ""},
+			problem: "EVENTS_PG_BRIDGE_CHANNEL must not be empty when EVENTS_PG_BRIDGE_ENABLED is set",
+		},
+		{
+			name:    "bad route rate limit",
+			env:     map[string]string{"RATE_LIMIT_ROUTES": "POST /pullRequest/create=fast:5"},
+			problem: `RATE_LIMIT_ROUTES entry "POST /pullRequest/create=fast:5" must have a positive rate`,
+		},
+		{
+			name:    "malformed route rate limit",
+			env:     map[string]string{"RATE_LIMIT_ROUTES": "/team/add=1:5"},
+			problem: `RATE_LIMIT_ROUTES entry "/team/add=1:5" must look like "POST /path=rate:burst"`,
+		},
+		{
+			name:    "bad log level",
+			env:     map[string]string{"LOG_LEVEL": "loud"},
+			problem: `LOG_LEVEL must be debug, info, warn or error, got "loud"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+
+			_, err := config.Load()
+			if problems := problemsOf(t, err); !slices.Contains(problems, tt.problem) {
+				t.Errorf("got problems %q, want %q among them", problems, tt.problem)
+			}
+		})
+	}
+}
+
+func TestLoadReportsAllProblems(t *testing.T) {
+	setEnv(t, map[string]string{
+		"USER_TOKEN":             "",
+		"CACHE_SIZE":             "-1",
+		"TRACING_SAMPLE_PERCENT": "101",
+	})
+
+	_, err := config.Load()
+
+	want := []string{
+		"USER_TOKEN is required",
+		"CACHE_SIZE must be positive, got -1",
+		"TRACING_SAMPLE_PERCENT must be between 0 and 100, got 101",
+	}
+	if problems := problemsOf(t, err); !slices.Equal(problems, want) {
+		t.Errorf("got problems %q, want %q", problems, want)
+	}
+}
+
+func TestLoadInactiveBackendIsIgnored(t *testing.T) {
+	setEnv(t, map[string]string{"MAX_CONNS": "zero", "SQLITE_PATH": ""})
+
+	if _, err := config.Load(); err != nil {
+		t.Errorf("got error %v for settings of unused backends, want nil", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name:    "yaml",
+			file:    "config.yaml",
+			content: "web_server:\n  port: 9090\n  address: 127.0.0.1\ncache:\n  enabled: false\n",
+		},
+		{
+			name:    "toml",
+			file:    "config.toml",
+			content: "[web_server]\nport = 9090\naddress = \"127.0.0.1\"\n\n[cache]\nenabled = false\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, map[string]string{
+				"CONFIG_FILE":        writeFile(t, tt.file, tt.content),
+				"WEB_SERVER_ADDRESS": "0.0.0.0",
+			})
+
+			cfg, err := config.Load()
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+
+			if cfg.WebServerConfig.Port != 9090 || cfg.CacheConfig.Enabled {
+				t.Errorf("got port %d and cache enabled %v, want the file values", cfg.WebServerConfig.Port,
+					cfg.CacheConfig.Enabled)
+			}
+
+			if cfg.WebServerConfig.Address != "0.0.0.0" {
+				t.Errorf("got address %q, want the environment to win over the file", cfg.WebServerConfig.Address)
+			}
+
+			sources := map[string]config.Source{}
+			for _, entry := range cfg.Entries() {
+				sources[entry.Key] = entry.Source
+			}
+
+			want := map[string]config.Source{
+				"WEB_SERVER_PORT":    config.SourceFile,
+				"WEB_SERVER_ADDRESS": config.SourceEnv,
+				"CACHE_SIZE":         config.SourceDefault,
+			}
+			for key, source := range want {
+				if sources[key] != source {
+					t.Errorf("got source %q for %s, want %q", sources[key], key, source)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFileProblems(t *testing.T) {
+	path := writeFile(t, "config.yaml", "web_server:\n  port: many\nweb_sever:\n  port: 1\n")
+	setEnv(t, map[string]string{"CONFIG_FILE": path})
+
+	_, err := config.Load()
+
+	want := []string{
+		`WEB_SERVER_PORT must be an integer, got "many" in ` + path,
+		"unknown key web_sever_port in " + path,
+	}
+	if problems := problemsOf(t, err); !slices.Equal(problems, want) {
+		t.Errorf("got problems %q, want %q", problems, want)
+	}
+
+	setEnv(t, map[string]string{"CONFIG_FILE": writeFile(t, "config.json", "{}")})
+
+	if _, err = config.Load(); err == nil || !strings.Contains(err.Error(), "must be .yaml, .yml or .toml") {
+		t.Errorf("got error %v, want an unsupported extension error", err)
+	}
+}
+
+func TestEntriesRedactSecrets(t *testing.T) {
+	setEnv(t, map[string]string{
+		"STORAGE_BACKEND": "postgres",
+		"DATABASE_URL":    "postgres://app:hunter2@db:5432/app",
+	})
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	values := map[string]string{}
+	for _, entry := range cfg.Entries() {
+		values[entry.Key] = entry.Value
+	}
+
+	want := map[string]string{
+		"ADMIN_TOKEN":  "<redacted>",
+		"USER_TOKEN":   "<redacted>",
+		"DATABASE_URL": "postgres://app:xxxxx@db:5432/app",
+	}
+	for key, value := range want {
+		if values[key] != value {
+			t.Errorf("got %s=%q, want %q", key, values[key], value)
+		}
+	}
+}
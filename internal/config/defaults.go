@@ -1,11 +1,77 @@
 package config
 
 const (
+	defaultStorageBackend = StorageBackendPostgres
+
+	defaultSQLitePath            = "data/app.db"
+	defaultSQLiteBusyTimeoutInMs = 5000
+
 	defaultMaxConns                     = 10
 	defaultMinConns                     = 1
 	defaultHealthCheckIntervalInSeconds = 5
+	defaultMigrateOnStart               = false
+
+	defaultReadDatabaseURL               = ""
+	defaultReplicaMaxLagInMs             = 1000
+	defaultReplicaCheckIntervalInSeconds = 5
+
+	defaultTxMaxRetries         = 3
+	defaultTxRetryBaseDelayInMs = 10
+	defaultTxRetryMaxDelayInMs  = 500
+
+	defaultAddress                     = ""
+	defaultPort                        = 8080
+	defaultShutdownTimeoutInSeconds    = 5
+	defaultShutdownDrainDelayInSeconds = 5
+
+	defaultEventsSubscriberBufferSize       = 64
+	defaultEventsHeartbeatIntervalInSeconds = 15
+	defaultEventsPGBridgeEnabled            = false
+	defaultEventsPGBridgeChannel            = "pr_events"
+
+	defaultIdempotencyKeyTTLInSeconds          = 24 * 60 * 60
+	defaultIdempotencyPendingLeaseInSeconds    = 60
+	defaultIdempotencyCleanupIntervalInSeconds = 10 * 60
+
+	defaultCacheEnabled               = true
+	defaultCacheSize                  = 1024
+	defaultCacheTTLInSeconds          = 30
+	defaultCachePGInvalidationEnabled = false
+	defaultCachePGInvalidationChannel = "cache_invalidation"
+
+	defaultArchiveEnabled           = false
+	defaultArchiveRetentionInDays   = 90
+	defaultArchiveIntervalInSeconds = 60 * 60
+	defaultArchiveBatchSize         = 500
+	defaultArchiveMode              = ArchiveModeTable
+	defaultArchiveExportDir         = "data/archive"
+
+	defaultMetricsEnabled = true
+
+	defaultTracingExporter      = TracingExporterNone
+	defaultTracingServiceName   = "pr-reviewer"
+	defaultTracingOTLPInsecure  = true
+	defaultTracingFilePath      = "data/traces.jsonl"
+	defaultTracingSamplePercent = 100
+
+	defaultLogLevel = "info"
+
+	defaultReadinessCheckTimeoutInMs      = 2000
+	defaultReadinessMaxNotifyQueuePercent = 50
+
+	defaultPolicyFile                   = ""
+	defaultPolicyWatchIntervalInSeconds = 5
+
+	defaultRateLimitEnabled   = true
+	defaultRateLimitRPS       = 50
+	defaultRateLimitBurst     = 100
+	defaultRateLimitRoutes    = "POST /pullRequest/create=10:20"
+	defaultMaxBodyBytes       = 1 << 20
+	defaultMaxImportBodyBytes = 64 << 20
+
+	defaultOpenAPIValidateResponses = false
 
-	defaultAddress                  = ""
-	defaultPort                     = 8080
-	defaultShutdownTimeoutInSeconds = 5
+	defaultGRPCEnabled = true
+	defaultGRPCAddress = ""
+	defaultGRPCPort    = 9090
 )
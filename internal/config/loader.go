@@ -0,0 +1,344 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Source tells where the effective value of a setting comes from.
+type Source string
+
+const (
+	SourceEnv     Source = "env"
+	SourceFile    Source = "file"
+	SourceDefault Source = "default"
+)
+
+// Entry is a setting as it was read, keyed by its environment variable.
+type Entry struct {
+	Key    string
+	Value  string
+	Source Source
+}
+
+// secretKeys are hidden entirely by Entries, urlKeys only lose the password.
+var (
+	secretKeys = map[string]bool{"ADMIN_TOKEN": true, "USER_TOKEN": true}
+	urlKeys    = map[string]bool{"DATABASE_URL": true, "READ_DATABASE_URL": true}
+)
+
+const redacted = "<redacted>"
+
+// ValidationError lists every problem found in the configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// loader reads settings from the environment, then from the config file,
+// then falls back to defaults. Problems are collected instead of returned,
+// so that Load reports all of them at once.
+type loader struct {
+	file     map[string]string
+	fileName string
+
+	entries  *[]Entry
+	seen     map[string]bool
+	problems *[]string
+}
+
+func newLoader(file map[string]string, fileName string) *loader {
+	return &loader{
+		file:     file,
+		fileName: fileName,
+		entries:  &[]Entry{},
+		seen:     map[string]bool{},
+		problems: &[]string{},
+	}
+}
+
+// section returns a loader for settings that matter only when active, e.g.
+// those of another storage backend: they are still read, so that their keys
+// are known in the config file, but their problems are ignored.
+func (l *loader) section(active bool) *loader {
+	if active {
+		return l
+	}
+
+	sub := *l
+	sub.problems = &[]string{}
+
+	return &sub
+}
+
+func (l *loader) problemf(format string, args ...any) {
+	*l.problems = append(*l.problems, fmt.Sprintf(format, args...))
+}
+
+func (l *loader) record(key, value string, source Source) {
+	if l.seen[key] {
+		return
+	}
+
+	l.seen[key] = true
+	*l.entries = append(*l.entries, Entry{Key: key, Value: value, Source: source})
+}
+
+func (l *loader) lookup(key string) (string, Source, bool) {
+	if value, ok := os.LookupEnv(key); ok {
+		l.record(key, value, SourceEnv)
+		return value, SourceEnv, true
+	}
+
+	if value, ok := l.file[key]; ok {
+		l.record(key, value, SourceFile)
+		return value, SourceFile, true
+	}
+
+	return "", "", false
+}
+
+// origin names the source of a value in problems.
+func (l *loader) origin(source Source) string {
+	if source == SourceFile {
+		return "in " + l.fileName
+	}
+
+	return "in " + string(source)
+}
+
+func (l *loader) required(key string) string {
+	value, _, ok := l.lookup(key)
+	if !ok || value == "" {
+		l.record(key, "", SourceDefault)
+		l.problemf("%s is required", key)
+	}
+
+	return value
+}
+
+func (l *loader) string(key, defaultValue string) string {
+	value, _, ok := l.lookup(key)
+	if !ok {
+		l.record(key, defaultValue, SourceDefault)
+		return defaultValue
+	}
+
+	return value
+}
+
+func (l *loader) int(key string, defaultValue int) int {
+	value, source, ok := l.lookup(key)
+	if !ok {
+		l.record(key, strconv.Itoa(defaultValue), SourceDefault)
+		return defaultValue
+	}
+
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		l.problemf("%s must be an integer, got %q %s", key, value, l.origin(source))
+		return defaultValue
+	}
+
+	return intValue
+}
+
+func (l *loader) int32(key string, defaultValue int32) int32 {
+	intValue := l.int(key, int(defaultValue))
+	if intValue < math.MinInt32 || intValue > math.MaxInt32 {
+		l.problemf("%s is out of the int32 range, got %d", key, intValue)
+		return defaultValue
+	}
+
+	return int32(intValue)
+}
+
+func (l *loader) float(key string, defaultValue float64) float64 {
+	value, source, ok := l.lookup(key)
+	if !ok {
+		l.record(key, strconv.FormatFloat(defaultValue, 'f', -1, 64), SourceDefault)
+		return defaultValue
+	}
+
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		l.problemf("%s must be a number, got %q %s", key, value, l.origin(source))
+		return defaultValue
+	}
+
+	return floatValue
+}
+
+func (l *loader) bool(key string, defaultValue bool) bool {
+	value, source, ok := l.lookup(key)
+	if !ok {
+		l.record(key, strconv.FormatBool(defaultValue), SourceDefault)
+		return defaultValue
+	}
+
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		l.problemf("%s must be a boolean, got %q %s", key, value, l.origin(source))
+		return defaultValue
+	}
+
+	return boolValue
+}
+
+func (l *loader) oneOf(key, value string, allowed ...string) {
+	if !slices.Contains(allowed, value) {
+		l.problemf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), value)
+	}
+}
+
+func (l *loader) positive(key string, value int) {
+	if value <= 0 {
+		l.problemf("%s must be positive, got %d", key, value)
+	}
+}
+
+func (l *loader) nonNegative(key string, value int) {
+	if value < 0 {
+		l.problemf("%s must not be negative, got %d", key, value)
+	}
+}
+
+func (l *loader) between(key string, value, lo, hi int) {
+	if value < lo || value > hi {
+		l.problemf("%s must be between %d and %d, got %d", key, lo, hi, value)
+	}
+}
+
+// checkFileKeys reports keys of the config file that no setting has read.
+func (l *loader) checkFileKeys() {
+	for _, key := range slices.Sorted(maps.Keys(l.file)) {
+		if !l.seen[key] {
+			l.problemf("unknown key %s in %s", strings.ToLower(key), l.fileName)
+		}
+	}
+}
+
+func (l *loader) err() error {
+	if len(*l.problems) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Problems: *l.problems}
+}
+
+// readFile reads the YAML or TOML config file at path into settings keyed
+// like the environment variables: nested tables are joined with "_" and
+// keys are upper-cased, so `web_server: {port: 8080}` sets WEB_SERVER_PORT.
+func readFile(path string) (map[string]string, error) {
+	if path == "" {
+		return map[string]string{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var raw map[string]any
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		if err = decoder.Decode(&raw); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	case ".toml":
+		if _, err = toml.Decode(string(data), &raw); err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("config file %s must be .yaml, .yml or .toml, got %q", path, ext)
+	}
+
+	settings := map[string]string{}
+	if err = flatten(settings, "", raw); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	return settings, nil
+}
+
+func flatten(out map[string]string, prefix string, raw map[string]any) error {
+	for key, value := range raw {
+		name := strings.ToUpper(key)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			if err := flatten(out, name, v); err != nil {
+				return err
+			}
+		case string:
+			out[name] = v
+		case bool:
+			out[name] = strconv.FormatBool(v)
+		case int:
+			out[name] = strconv.Itoa(v)
+		case int64:
+			out[name] = strconv.FormatInt(v, 10)
+		case float64:
+			out[name] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return fmt.Errorf("%s: unsupported value %v", strings.ToLower(name), value)
+		}
+	}
+
+	return nil
+}
+
+// Entries returns the settings read by Load in the order they were read,
+// with tokens and database passwords redacted.
+func (c *Config) Entries() []Entry {
+	entries := make([]Entry, len(c.entries))
+
+	for i, entry := range c.entries {
+		switch {
+		case entry.Value == "":
+		case secretKeys[entry.Key]:
+			entry.Value = redacted
+		case urlKeys[entry.Key]:
+			entry.Value = redactURL(entry.Value)
+		}
+
+		entries[i] = entry
+	}
+
+	return entries
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		// Unparsable URLs and key=value connection strings may still carry a password.
+		return redacted
+	}
+
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+
+	return u.String()
+}
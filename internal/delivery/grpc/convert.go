@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"time"
+
+	reviewerv1 "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/api/reviewer/v1"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func teamToProto(team domain.TeamUpsert) *reviewerv1.Team {
+	members := make([]*reviewerv1.TeamMember, len(team.Members))
+
+	for i, member := range team.Members {
+		members[i] = &reviewerv1.TeamMember{
+			UserId:   member.UserID,
+			Username: member.Username,
+			IsActive: member.IsActive,
+		}
+	}
+
+	return &reviewerv1.Team{
+		TeamName: team.Name,
+		Members:  members,
+	}
+}
+
+func teamFromProto(team *reviewerv1.Team) domain.TeamUpsert {
+	members := make([]domain.TeamMember, len(team.GetMembers()))
+
+	for i, member := range team.GetMembers() {
+		members[i] = domain.TeamMember{
+			UserID:   member.GetUserId(),
+			Username: member.GetUsername(),
+			IsActive: member.GetIsActive(),
+		}
+	}
+
+	return domain.TeamUpsert{
+		Name:    team.GetTeamName(),
+		Members: members,
+	}
+}
+
+func userToProto(user domain.User) *reviewerv1.User {
+	return &reviewerv1.User{
+		UserId:   user.ID,
+		Username: user.Username,
+		TeamName: user.TeamName,
+		IsActive: user.IsActive,
+	}
+}
+
+func pullRequestStatusToProto(status domain.PullRequestStatus) reviewerv1.PullRequestStatus {
+	switch status {
+	case domain.PRStatusOpen:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case domain.PRStatusMerged:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	default:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+	}
+}
+
+func pullRequestToProto(pr domain.PullRequest) *reviewerv1.PullRequest {
+	return &reviewerv1.PullRequest{
+		PullRequestId:     pr.ID,
+		PullRequestName:   pr.Name,
+		AuthorId:          pr.AuthorID,
+		Status:            pullRequestStatusToProto(pr.Status),
+		AssignedReviewers: pr.AssignedReviewers,
+		CreatedAt:         timestampToProto(&pr.CreatedAt),
+		MergedAt:          timestampToProto(pr.MergedAt),
+		Version:           pr.Version,
+		ArchivedAt:        timestampToProto(pr.ArchivedAt),
+	}
+}
+
+func pullRequestShortToProto(pr domain.PullRequest) *reviewerv1.PullRequestShort {
+	return &reviewerv1.PullRequestShort{
+		PullRequestId:   pr.ID,
+		PullRequestName: pr.Name,
+		AuthorId:        pr.AuthorID,
+		Status:          pullRequestStatusToProto(pr.Status),
+		Archived:        pr.ArchivedAt != nil,
+	}
+}
+
+// eventTypes maps the domain event types to the enum of the API.
+var eventTypes = map[domain.EventType]reviewerv1.EventType{
+	domain.EventReviewerAssigned:    reviewerv1.EventType_EVENT_TYPE_REVIEWER_ASSIGNED,
+	domain.EventReviewerReassigned:  reviewerv1.EventType_EVENT_TYPE_REVIEWER_REASSIGNED,
+	domain.EventPullRequestMerged:   reviewerv1.EventType_EVENT_TYPE_PR_MERGED,
+	domain.EventUserActivityChanged: reviewerv1.EventType_EVENT_TYPE_USER_ACTIVITY_CHANGED,
+}
+
+func eventToProto(e domain.Event) *reviewerv1.Event {
+	return &reviewerv1.Event{
+		Type:              eventTypes[e.Type],
+		PullRequestId:     e.PullRequestID,
+		UserId:            e.UserID,
+		OldUserId:         e.OldUserID,
+		AssignedReviewers: e.Reviewers,
+		TeamName:          e.TeamName,
+		IsActive:          e.IsActive,
+		OccurredAt:        timestampToProto(&e.OccurredAt),
+	}
+}
+
+// timestampToProto leaves unset times unset instead of sending year 1.
+func timestampToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the ErrorInfo domain of the service errors.
+const errorDomain = "pr-reviewer"
+
+// errorCodes maps every domain error code to its gRPC code. A test fails
+// when a code declared in the domain package is missing here.
+var errorCodes = map[domain.ErrorCode]codes.Code{
+	domain.ErrCodeTeamExists:           codes.AlreadyExists,
+	domain.ErrCodePRExists:             codes.AlreadyExists,
+	domain.ErrCodePRMerged:             codes.FailedPrecondition,
+	domain.ErrCodeNotAssigned:          codes.FailedPrecondition,
+	domain.ErrCodeNoCandidate:          codes.FailedPrecondition,
+	domain.ErrCodeNotFound:             codes.NotFound,
+	domain.ErrCodeIdempotencyKeyReused: codes.FailedPrecondition,
+	domain.ErrCodeRequestInProgress:    codes.Aborted,
+	// A concurrent change, which gRPC clients retry at a higher level.
+	domain.ErrCodePreconditionFailed: codes.Aborted,
+	domain.ErrCodeInvalidArgument:    codes.InvalidArgument,
+}
+
+// statusError converts a service error to a gRPC status. Domain errors
+// keep their code in an ErrorInfo detail; other errors are logged and
+// hidden behind INTERNAL.
+func statusError(ctx context.Context, err error) error {
+	var domainError *domain.Error
+
+	if !errors.As(err, &domainError) {
+		logging.FromContext(ctx).Error("internal error", logging.Error(err))
+
+		return status.Error(codes.Internal, "something went wrong")
+	}
+
+	code, ok := errorCodes[domainError.Code]
+	if !ok {
+		code = codes.Internal
+	}
+
+	st, detailErr := status.New(code, domainError.Message).WithDetails(&errdetails.ErrorInfo{
+		Reason: string(domainError.Code),
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return status.Error(code, domainError.Message)
+	}
+
+	return st.Err()
+}
+
+// field is a request field checked by requireFields.
+type field struct {
+	name  string
+	value string
+}
+
+// requireFields answers INVALID_ARGUMENT with a BadRequest detail listing
+// the empty fields.
+func requireFields(fields ...field) error {
+	var (
+		names      []string
+		violations []*errdetails.BadRequest_FieldViolation
+	)
+
+	for _, f := range fields {
+		if f.value == "" {
+			names = append(names, f.name)
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.name,
+				Description: "must not be empty",
+			})
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	message := "invalid request: " + strings.Join(names, ", ") + " must not be empty"
+
+	st, err := status.New(codes.InvalidArgument, message).WithDetails(&errdetails.BadRequest{
+		FieldViolations: violations,
+	})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, message)
+	}
+
+	return st.Err()
+}
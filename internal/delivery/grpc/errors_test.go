@@ -0,0 +1,15 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain/domaintest"
+)
+
+func TestEveryErrorCodeHasGRPCCode(t *testing.T) {
+	for name, code := range domaintest.ErrorCodes(t) {
+		if _, ok := errorCodes[code]; !ok {
+			t.Errorf("domain.%s (%s) has no gRPC code in errorCodes and would be answered with INTERNAL", name, code)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	reviewerv1 "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/api/reviewer/v1"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/handlers"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"google.golang.org/grpc"
+)
+
+type eventServer struct {
+	reviewerv1.UnimplementedEventServiceServer
+
+	s handlers.EventSubscriber
+}
+
+// StreamEvents sends events until the client goes away or the broker is
+// closed on shutdown. HTTP/2 keepalives replace the SSE heartbeat.
+func (e *eventServer) StreamEvents(
+	req *reviewerv1.StreamEventsRequest,
+	stream grpc.ServerStreamingServer[reviewerv1.Event],
+) error {
+	events, unsubscribe := e.s.Subscribe(domain.EventFilter{
+		UserID:   req.GetUserId(),
+		TeamName: req.GetTeamName(),
+	})
+	defer unsubscribe()
+
+	ctx := stream.Context()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
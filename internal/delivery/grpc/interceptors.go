@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"log/slog"
+	"strings"
+	"time"
+
+	reviewerv1 "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/api/reviewer/v1"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	adminTokenKey = "x-admin-token"
+	userTokenKey  = "x-user-token"
+	requestIDKey  = "x-request-id"
+
+	// apiPrefix selects the methods of the service API; health checks
+	// and reflection are not authorized.
+	apiPrefix = "/reviewer.v1."
+)
+
+// userMethods are open to the user token, like the GET routes of the
+// HTTP API. The other API methods require the admin token.
+var userMethods = map[string]bool{
+	reviewerv1.TeamService_GetTeam_FullMethodName:               true,
+	reviewerv1.UserService_GetReview_FullMethodName:             true,
+	reviewerv1.PullRequestService_GetPullRequest_FullMethodName: true,
+	reviewerv1.EventService_StreamEvents_FullMethodName:         true,
+}
+
+type authorizer struct {
+	adminToken string
+	userToken  string
+}
+
+// authorize checks the tokens in the metadata against the method.
+func (a authorizer) authorize(ctx context.Context, method string) error {
+	if !strings.HasPrefix(method, apiPrefix) {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	admin := tokenMatches(md, adminTokenKey, a.adminToken)
+	user := tokenMatches(md, userTokenKey, a.userToken)
+
+	switch {
+	case admin, user && userMethods[method]:
+		return nil
+	case user && len(md.Get(adminTokenKey)) == 0:
+		return status.Error(codes.PermissionDenied, "admin token required")
+	case userMethods[method]:
+		return status.Error(codes.Unauthenticated, "invalid token")
+	default:
+		return status.Error(codes.Unauthenticated, "invalid admin token")
+	}
+}
+
+func tokenMatches(md metadata.MD, key, token string) bool {
+	values := md.Get(key)
+
+	return token != "" && len(values) == 1 && subtle.ConstantTimeCompare([]byte(values[0]), []byte(token)) == 1
+}
+
+// unary authorizes the call and logs it when it completes, like the HTTP
+// logging middleware.
+func (a authorizer) unary(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	ctx, done := startCall(ctx, info.FullMethod)
+
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		done(err)
+		return nil, err
+	}
+
+	resp, err := handler(ctx, req)
+	done(err)
+
+	return resp, err
+}
+
+func (a authorizer) stream(
+	srv any,
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, done := startCall(ss.Context(), info.FullMethod)
+
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		done(err)
+		return err
+	}
+
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	done(err)
+
+	return err
+}
+
+// startCall puts a logger tagged with the request ID into the context and
+// returns the function that logs the outcome.
+func startCall(ctx context.Context, method string) (context.Context, func(error)) {
+	start := time.Now()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var requestID string
+	if ids := md.Get(requestIDKey); len(ids) == 1 && logging.ValidRequestID(ids[0]) {
+		requestID = ids[0]
+	} else {
+		requestID = rand.Text()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	logger := slog.Default().With(slog.String("request_id", requestID))
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		logger = logger.With(slog.String("trace_id", traceID))
+	}
+
+	ctx = logging.WithLogger(ctx, logger)
+
+	return ctx, func(err error) {
+		code := status.Code(err)
+
+		level := slog.LevelInfo
+		if code == codes.Internal || code == codes.Unknown {
+			level = slog.LevelError
+		}
+
+		logger.LogAttrs(ctx, level, "rpc completed",
+			slog.String("method", method),
+			slog.String("code", code.String()),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+		)
+	}
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
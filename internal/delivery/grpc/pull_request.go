@@ -0,0 +1,131 @@
+package grpc
+
+import (
+	"context"
+
+	reviewerv1 "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/api/reviewer/v1"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/handlers"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type pullRequestServer struct {
+	reviewerv1.UnimplementedPullRequestServiceServer
+
+	s handlers.PullRequestService
+}
+
+func (p *pullRequestServer) CreatePullRequest(
+	ctx context.Context,
+	req *reviewerv1.CreatePullRequestRequest,
+) (*reviewerv1.CreatePullRequestResponse, error) {
+	err := requireFields(
+		field{"pull_request_id", req.GetPullRequestId()},
+		field{"pull_request_name", req.GetPullRequestName()},
+		field{"author_id", req.GetAuthorId()},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := p.s.CreatePullRequest(ctx, domain.PullRequest{
+		ID:       req.GetPullRequestId(),
+		Name:     req.GetPullRequestName(),
+		AuthorID: req.GetAuthorId(),
+	})
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.CreatePullRequestResponse{PullRequest: pullRequestToProto(pr)}, nil
+}
+
+func (p *pullRequestServer) GetPullRequest(
+	ctx context.Context,
+	req *reviewerv1.GetPullRequestRequest,
+) (*reviewerv1.GetPullRequestResponse, error) {
+	if err := requireFields(field{"pull_request_id", req.GetPullRequestId()}); err != nil {
+		return nil, err
+	}
+
+	pr, err := p.s.GetPullRequest(ctx, req.GetPullRequestId(), req.GetIncludeArchived())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.GetPullRequestResponse{PullRequest: pullRequestToProto(pr)}, nil
+}
+
+func (p *pullRequestServer) MergePullRequest(
+	ctx context.Context,
+	req *reviewerv1.MergePullRequestRequest,
+) (*reviewerv1.MergePullRequestResponse, error) {
+	if err := requireFields(field{"pull_request_id", req.GetPullRequestId()}); err != nil {
+		return nil, err
+	}
+
+	pr, err := p.s.MergePullRequest(ctx, req.GetPullRequestId(), req.GetExpectedVersion())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.MergePullRequestResponse{PullRequest: pullRequestToProto(pr)}, nil
+}
+
+func (p *pullRequestServer) ReassignPullRequest(
+	ctx context.Context,
+	req *reviewerv1.ReassignPullRequestRequest,
+) (*reviewerv1.ReassignPullRequestResponse, error) {
+	err := requireFields(
+		field{"pull_request_id", req.GetPullRequestId()},
+		field{"old_user_id", req.GetOldUserId()},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, replacedBy, err := p.s.ReassignPullRequest(
+		ctx,
+		req.GetPullRequestId(),
+		req.GetOldUserId(),
+		req.GetExpectedVersion(),
+	)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.ReassignPullRequestResponse{
+		PullRequest: pullRequestToProto(pr),
+		ReplacedBy:  replacedBy,
+	}, nil
+}
+
+func (p *pullRequestServer) DeletePullRequest(
+	ctx context.Context,
+	req *reviewerv1.DeletePullRequestRequest,
+) (*reviewerv1.DeletePullRequestResponse, error) {
+	if err := requireFields(field{"pull_request_id", req.GetPullRequestId()}); err != nil {
+		return nil, err
+	}
+
+	if err := p.s.DeletePullRequest(ctx, req.GetPullRequestId()); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.DeletePullRequestResponse{}, nil
+}
+
+func (p *pullRequestServer) RestorePullRequest(
+	ctx context.Context,
+	req *reviewerv1.RestorePullRequestRequest,
+) (*reviewerv1.RestorePullRequestResponse, error) {
+	if err := requireFields(field{"pull_request_id", req.GetPullRequestId()}); err != nil {
+		return nil, err
+	}
+
+	pr, err := p.s.RestorePullRequest(ctx, req.GetPullRequestId())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.RestorePullRequestResponse{PullRequest: pullRequestToProto(pr)}, nil
+}
@@ -0,0 +1,94 @@
+// Package grpc serves the gRPC API of api/reviewer/v1 on top of the same
+// services as the HTTP API.
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	reviewerv1 "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/api/reviewer/v1"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/handlers"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+}
+
+func NewServer(
+	teamService handlers.TeamService,
+	userService handlers.UserService,
+	pullRequestService handlers.PullRequestService,
+	eventSubscriber handlers.EventSubscriber,
+	adminToken string,
+	userToken string,
+) *Server {
+	auth := authorizer{adminToken: adminToken, userToken: userToken}
+
+	s := grpc.NewServer(
+		// Continues the traces of the callers, like the HTTP tracing middleware.
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(auth.unary),
+		grpc.ChainStreamInterceptor(auth.stream),
+	)
+
+	reviewerv1.RegisterTeamServiceServer(s, &teamServer{s: teamService})
+	reviewerv1.RegisterUserServiceServer(s, &userServer{s: userService})
+	reviewerv1.RegisterPullRequestServiceServer(s, &pullRequestServer{s: pullRequestService})
+	reviewerv1.RegisterEventServiceServer(s, &eventServer{s: eventSubscriber})
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+
+	// Lets grpcurl and similar tools call the API without the proto file.
+	reflection.Register(s)
+
+	return &Server{
+		grpc:   s,
+		health: healthServer,
+	}
+}
+
+func (s *Server) Start(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", addr, err)
+	}
+
+	return s.Serve(lis)
+}
+
+// Serve serves on the listener, e.g. an in-process bufconn listener.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// StartDraining reports NOT_SERVING to health checks, like /readyz.
+func (s *Server) StartDraining() {
+	s.health.Shutdown()
+}
+
+// Stop waits for the running calls until ctx is done and then cancels them.
+func (s *Server) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		s.grpc.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+
+		return ctx.Err()
+	}
+}
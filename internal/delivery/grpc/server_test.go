@@ -0,0 +1,453 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	reviewerv1 "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/api/reviewer/v1"
+	deliverygrpc "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/grpc"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/events"
+	pullrequestservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/pull_request"
+	teamservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/team"
+	userservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/user"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory/memorytest"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	adminToken = "admin-secret"
+	userToken  = "user-secret"
+)
+
+// notifyingBroker reports subscriptions, so a test publishes only after
+// the stream is listening.
+type notifyingBroker struct {
+	*events.Broker
+
+	subscribed chan struct{}
+}
+
+func (b *notifyingBroker) Subscribe(filter domain.EventFilter) (<-chan domain.Event, func()) {
+	ch, unsubscribe := b.Broker.Subscribe(filter)
+	b.subscribed <- struct{}{}
+
+	return ch, unsubscribe
+}
+
+type testServer struct {
+	conn   *grpc.ClientConn
+	broker *notifyingBroker
+}
+
+// newTestServer serves the API over bufconn on the memory backend with
+// team backend of u1, u2 and u3.
+func newTestServer(t *testing.T) testServer {
+	t.Helper()
+
+	backend := memorytest.NewBackend()
+	broker := &notifyingBroker{Broker: events.NewBroker(16), subscribed: make(chan struct{}, 1)}
+
+	teamService := teamservice.NewTeamService[memory.Executor](
+		backend.TxManager, backend.Store, backend.RepoFactory, broker,
+	)
+	server := deliverygrpc.NewServer(
+		teamService,
+		userservice.NewUserService[memory.Executor](backend.TxManager, backend.Store, backend.RepoFactory, broker),
+		pullrequestservice.NewPullRequestService[memory.Executor](
+			backend.TxManager,
+			backend.Store,
+			backend.RepoFactory,
+			broker,
+			memorytest.Policy(domain.DefaultAssignmentPolicy()),
+		),
+		broker,
+		adminToken,
+		userToken,
+	)
+
+	lis := bufconn.Listen(1 << 20)
+
+	go func() { _ = server.Serve(lis) }()
+
+	t.Cleanup(func() {
+		broker.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_ = server.Stop(ctx)
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = teamService.CreateTeam(context.Background(), domain.TeamUpsert{
+		Name: "backend",
+		Members: []domain.TeamMember{
+			{UserID: "u1", Username: "alice", IsActive: true},
+			{UserID: "u2", Username: "bob", IsActive: true},
+			{UserID: "u3", Username: "carol", IsActive: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("seed team: %v", err)
+	}
+
+	return testServer{conn: conn, broker: broker}
+}
+
+func withTokens(pairs ...string) context.Context {
+	return metadata.NewOutgoingContext(context.Background(), metadata.Pairs(pairs...))
+}
+
+func asAdmin() context.Context {
+	return withTokens("x-admin-token", adminToken)
+}
+
+func wantStatus(t *testing.T, err error, code codes.Code) *status.Status {
+	t.Helper()
+
+	st, _ := status.FromError(err)
+	if st.Code() != code {
+		t.Fatalf("got %s: %s, want %s", st.Code(), st.Message(), code)
+	}
+
+	return st
+}
+
+// wantReason checks the domain error code in the ErrorInfo detail.
+func wantReason(t *testing.T, st *status.Status, reason domain.ErrorCode) {
+	t.Helper()
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			if info.GetReason() != string(reason) {
+				t.Errorf("got reason %s, want %s", info.GetReason(), reason)
+			}
+
+			return
+		}
+	}
+
+	t.Errorf("no ErrorInfo in status %s: %s", st.Code(), st.Message())
+}
+
+func TestAuth(t *testing.T) {
+	s := newTestServer(t)
+	teams := reviewerv1.NewTeamServiceClient(s.conn)
+	newTeam := &reviewerv1.AddTeamRequest{Team: &reviewerv1.Team{TeamName: "frontend"}}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		call func(ctx context.Context) error
+		want codes.Code
+	}{
+		{
+			name: "user method without token",
+			ctx:  context.Background(),
+			call: func(ctx context.Context) error {
+				_, err := teams.GetTeam(ctx, &reviewerv1.GetTeamRequest{TeamName: "backend"})
+				return err
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "user method with wrong token",
+			ctx:  withTokens("x-user-token", "wrong"),
+			call: func(ctx context.Context) error {
+				_, err := teams.GetTeam(ctx, &reviewerv1.GetTeamRequest{TeamName: "backend"})
+				return err
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "user method with user token",
+			ctx:  withTokens("x-user-token", userToken),
+			call: func(ctx context.Context) error {
+				_, err := teams.GetTeam(ctx, &reviewerv1.GetTeamRequest{TeamName: "backend"})
+				return err
+			},
+			want: codes.OK,
+		},
+		{
+			name: "user method with admin token",
+			ctx:  asAdmin(),
+			call: func(ctx context.Context) error {
+				_, err := teams.GetTeam(ctx, &reviewerv1.GetTeamRequest{TeamName: "backend"})
+				return err
+			},
+			want: codes.OK,
+		},
+		{
+			name: "admin method without token",
+			ctx:  context.Background(),
+			call: func(ctx context.Context) error {
+				_, err := teams.AddTeam(ctx, newTeam)
+				return err
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "admin method with user token",
+			ctx:  withTokens("x-user-token", userToken),
+			call: func(ctx context.Context) error {
+				_, err := teams.AddTeam(ctx, newTeam)
+				return err
+			},
+			want: codes.PermissionDenied,
+		},
+		{
+			name: "admin method with user token and wrong admin token",
+			ctx:  withTokens("x-user-token", userToken, "x-admin-token", "wrong"),
+			call: func(ctx context.Context) error {
+				_, err := teams.AddTeam(ctx, newTeam)
+				return err
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "stream without token",
+			ctx:  context.Background(),
+			call: func(ctx context.Context) error {
+				stream, err := reviewerv1.NewEventServiceClient(s.conn).
+					StreamEvents(ctx, &reviewerv1.StreamEventsRequest{})
+				if err != nil {
+					return err
+				}
+
+				_, err = stream.Recv()
+
+				return err
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "health check without token",
+			ctx:  context.Background(),
+			call: func(ctx context.Context) error {
+				_, err := healthpb.NewHealthClient(s.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+				return err
+			},
+			want: codes.OK,
+		},
+		{
+			name: "admin method with admin token",
+			ctx:  asAdmin(),
+			call: func(ctx context.Context) error {
+				_, err := teams.AddTeam(ctx, newTeam)
+				return err
+			},
+			want: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantStatus(t, tt.call(tt.ctx), tt.want)
+		})
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	s := newTestServer(t)
+	teams := reviewerv1.NewTeamServiceClient(s.conn)
+	pullRequests := reviewerv1.NewPullRequestServiceClient(s.conn)
+	ctx := asAdmin()
+
+	_, err := pullRequests.CreatePullRequest(ctx, &reviewerv1.CreatePullRequestRequest{
+		PullRequestId: "pr-1", PullRequestName: "first", AuthorId: "u1",
+	})
+	if err != nil {
+		t.Fatalf("create pull request: %v", err)
+	}
+
+	_, err = pullRequests.CreatePullRequest(ctx, &reviewerv1.CreatePullRequestRequest{
+		PullRequestId: "pr-2", PullRequestName: "second", AuthorId: "u1",
+	})
+	if err != nil {
+		t.Fatalf("create pull request: %v", err)
+	}
+
+	_, err = pullRequests.MergePullRequest(ctx, &reviewerv1.MergePullRequestRequest{PullRequestId: "pr-2"})
+	if err != nil {
+		t.Fatalf("merge pull request: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		call   func() error
+		want   codes.Code
+		reason domain.ErrorCode
+	}{
+		{
+			name: "not found",
+			call: func() error {
+				_, err := teams.GetTeam(ctx, &reviewerv1.GetTeamRequest{TeamName: "missing"})
+				return err
+			},
+			want:   codes.NotFound,
+			reason: domain.ErrCodeNotFound,
+		},
+		{
+			name: "team exists",
+			call: func() error {
+				_, err := teams.AddTeam(ctx, &reviewerv1.AddTeamRequest{Team: &reviewerv1.Team{TeamName: "backend"}})
+				return err
+			},
+			want:   codes.AlreadyExists,
+			reason: domain.ErrCodeTeamExists,
+		},
+		{
+			name: "pull request exists",
+			call: func() error {
+				_, err := pullRequests.CreatePullRequest(ctx, &reviewerv1.CreatePullRequestRequest{
+					PullRequestId: "pr-1", PullRequestName: "again", AuthorId: "u2",
+				})
+				return err
+			},
+			want:   codes.AlreadyExists,
+			reason: domain.ErrCodePRExists,
+		},
+		{
+			name: "no candidate",
+			call: func() error {
+				_, err := pullRequests.ReassignPullRequest(ctx, &reviewerv1.ReassignPullRequestRequest{
+					PullRequestId: "pr-1", OldUserId: "u2",
+				})
+				return err
+			},
+			want:   codes.FailedPrecondition,
+			reason: domain.ErrCodeNoCandidate,
+		},
+		{
+			name: "not assigned",
+			call: func() error {
+				_, err := pullRequests.ReassignPullRequest(ctx, &reviewerv1.ReassignPullRequestRequest{
+					PullRequestId: "pr-1", OldUserId: "u1",
+				})
+				return err
+			},
+			want:   codes.FailedPrecondition,
+			reason: domain.ErrCodeNotAssigned,
+		},
+		{
+			name: "merged",
+			call: func() error {
+				_, err := pullRequests.ReassignPullRequest(ctx, &reviewerv1.ReassignPullRequestRequest{
+					PullRequestId: "pr-2", OldUserId: "u2",
+				})
+				return err
+			},
+			want:   codes.FailedPrecondition,
+			reason: domain.ErrCodePRMerged,
+		},
+		{
+			name: "stale version",
+			call: func() error {
+				_, err := pullRequests.MergePullRequest(ctx, &reviewerv1.MergePullRequestRequest{
+					PullRequestId: "pr-1", ExpectedVersion: 42,
+				})
+				return err
+			},
+			want:   codes.Aborted,
+			reason: domain.ErrCodePreconditionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := wantStatus(t, tt.call(), tt.want)
+			wantReason(t, st, tt.reason)
+		})
+	}
+}
+
+func TestInvalidArgument(t *testing.T) {
+	s := newTestServer(t)
+
+	_, err := reviewerv1.NewPullRequestServiceClient(s.conn).CreatePullRequest(asAdmin(),
+		&reviewerv1.CreatePullRequestRequest{PullRequestId: "pr-1"})
+	st := wantStatus(t, err, codes.InvalidArgument)
+
+	var fields []string
+
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+
+	if len(fields) != 2 || fields[0] != "pull_request_name" || fields[1] != "author_id" {
+		t.Errorf("got field violations %v, want [pull_request_name author_id]", fields)
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	s := newTestServer(t)
+	users := reviewerv1.NewUserServiceClient(s.conn)
+
+	ctx, cancel := context.WithTimeout(withTokens("x-user-token", userToken), 5*time.Second)
+	defer cancel()
+
+	stream, err := reviewerv1.NewEventServiceClient(s.conn).
+		StreamEvents(ctx, &reviewerv1.StreamEventsRequest{UserId: "u2"})
+	if err != nil {
+		t.Fatalf("stream events: %v", err)
+	}
+
+	select {
+	case <-s.broker.subscribed:
+	case <-ctx.Done():
+		t.Fatal("stream did not subscribe")
+	}
+
+	// The event about u1 is filtered out, the one about u2 is delivered.
+	for _, userID := range []string{"u1", "u2"} {
+		_, err = users.SetIsActive(asAdmin(), &reviewerv1.SetIsActiveRequest{UserId: userID, IsActive: false})
+		if err != nil {
+			t.Fatalf("set is active %s: %v", userID, err)
+		}
+	}
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("recv: %v", err)
+	}
+
+	if event.GetType() != reviewerv1.EventType_EVENT_TYPE_USER_ACTIVITY_CHANGED || event.GetUserId() != "u2" ||
+		event.GetTeamName() != "backend" || event.GetIsActive() || event.GetOccurredAt() == nil {
+		t.Errorf("got event %v, want u2 of backend deactivated", event)
+	}
+
+	// Closing the broker on shutdown ends the stream cleanly.
+	s.broker.Close()
+
+	if _, err = stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("recv after broker close: got %v, want EOF", err)
+	}
+}
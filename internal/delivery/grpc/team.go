@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	reviewerv1 "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/api/reviewer/v1"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/handlers"
+)
+
+type teamServer struct {
+	reviewerv1.UnimplementedTeamServiceServer
+
+	s handlers.TeamService
+}
+
+func (t *teamServer) AddTeam(ctx context.Context, req *reviewerv1.AddTeamRequest) (*reviewerv1.AddTeamResponse, error) {
+	fields := []field{{"team.team_name", req.GetTeam().GetTeamName()}}
+	for i, member := range req.GetTeam().GetMembers() {
+		fields = append(fields,
+			field{fmt.Sprintf("team.members[%d].user_id", i), member.GetUserId()},
+			field{fmt.Sprintf("team.members[%d].username", i), member.GetUsername()},
+		)
+	}
+
+	if err := requireFields(fields...); err != nil {
+		return nil, err
+	}
+
+	team, err := t.s.CreateTeam(ctx, teamFromProto(req.GetTeam()))
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.AddTeamResponse{Team: teamToProto(team)}, nil
+}
+
+func (t *teamServer) GetTeam(ctx context.Context, req *reviewerv1.GetTeamRequest) (*reviewerv1.GetTeamResponse, error) {
+	if err := requireFields(field{"team_name", req.GetTeamName()}); err != nil {
+		return nil, err
+	}
+
+	team, err := t.s.GetTeamWithMembers(ctx, req.GetTeamName())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.GetTeamResponse{Team: teamToProto(team)}, nil
+}
+
+func (t *teamServer) DeleteTeam(
+	ctx context.Context,
+	req *reviewerv1.DeleteTeamRequest,
+) (*reviewerv1.DeleteTeamResponse, error) {
+	if err := requireFields(field{"team_name", req.GetTeamName()}); err != nil {
+		return nil, err
+	}
+
+	if err := t.s.DeleteTeam(ctx, req.GetTeamName()); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.DeleteTeamResponse{}, nil
+}
+
+func (t *teamServer) RestoreTeam(
+	ctx context.Context,
+	req *reviewerv1.RestoreTeamRequest,
+) (*reviewerv1.RestoreTeamResponse, error) {
+	if err := requireFields(field{"team_name", req.GetTeamName()}); err != nil {
+		return nil, err
+	}
+
+	team, err := t.s.RestoreTeam(ctx, req.GetTeamName())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.RestoreTeamResponse{Team: teamToProto(team)}, nil
+}
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+
+	reviewerv1 "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/api/reviewer/v1"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/handlers"
+)
+
+type userServer struct {
+	reviewerv1.UnimplementedUserServiceServer
+
+	s handlers.UserService
+}
+
+func (u *userServer) SetIsActive(
+	ctx context.Context,
+	req *reviewerv1.SetIsActiveRequest,
+) (*reviewerv1.SetIsActiveResponse, error) {
+	if err := requireFields(field{"user_id", req.GetUserId()}); err != nil {
+		return nil, err
+	}
+
+	user, err := u.s.SetIsActive(ctx, req.GetUserId(), req.GetIsActive())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.SetIsActiveResponse{User: userToProto(user)}, nil
+}
+
+func (u *userServer) GetReview(
+	ctx context.Context,
+	req *reviewerv1.GetReviewRequest,
+) (*reviewerv1.GetReviewResponse, error) {
+	if err := requireFields(field{"user_id", req.GetUserId()}); err != nil {
+		return nil, err
+	}
+
+	pullRequests, err := u.s.ListReviewPRs(ctx, req.GetUserId(), req.GetIncludeArchived())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	shorts := make([]*reviewerv1.PullRequestShort, 0, len(pullRequests))
+	for _, pullRequest := range pullRequests {
+		shorts = append(shorts, pullRequestShortToProto(pullRequest))
+	}
+
+	return &reviewerv1.GetReviewResponse{
+		UserId:       req.GetUserId(),
+		PullRequests: shorts,
+	}, nil
+}
+
+func (u *userServer) DeleteUser(
+	ctx context.Context,
+	req *reviewerv1.DeleteUserRequest,
+) (*reviewerv1.DeleteUserResponse, error) {
+	if err := requireFields(field{"user_id", req.GetUserId()}); err != nil {
+		return nil, err
+	}
+
+	if err := u.s.DeleteUser(ctx, req.GetUserId()); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.DeleteUserResponse{}, nil
+}
+
+func (u *userServer) RestoreUser(
+	ctx context.Context,
+	req *reviewerv1.RestoreUserRequest,
+) (*reviewerv1.RestoreUserResponse, error) {
+	if err := requireFields(field{"user_id", req.GetUserId()}); err != nil {
+		return nil, err
+	}
+
+	user, err := u.s.RestoreUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &reviewerv1.RestoreUserResponse{User: userToProto(user)}, nil
+}
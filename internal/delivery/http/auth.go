@@ -1,48 +1,102 @@
 package http
 
 import (
+	"crypto/subtle"
 	"net/http"
-	"os"
 
 	"github.com/labstack/echo/v4"
-	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
 )
 
 const (
 	adminHeader = "X-Admin-Token"
 	userHeader  = "X-User-Token"
+
+	// callerTokenKey is the echo context key of the token that
+	// authenticated the request.
+	callerTokenKey = "caller_token"
 )
 
-func AdminOnlyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	adminToken := os.Getenv("ADMIN_TOKEN")
+// Auth checks the tokens of the X-Admin-Token and X-User-Token headers.
+type Auth struct {
+	adminToken string
+	userToken  string
+	// authenticated wraps the handlers of authenticated requests, nil if unset.
+	authenticated echo.MiddlewareFunc
+}
+
+func NewAuth(adminToken, userToken string) Auth {
+	return Auth{
+		adminToken: adminToken,
+		userToken:  userToken,
+	}
+}
+
+// tokenMatches compares in constant time, so that response times do not
+// reveal how much of a token was guessed right.
+func tokenMatches(token, configured string) bool {
+	return configured != "" && subtle.ConstantTimeCompare([]byte(token), []byte(configured)) == 1
+}
+
+// callerToken returns the admin or user token of the request if it matches
+// the configured one.
+func (a Auth) callerToken(c echo.Context) (string, bool) {
+	if token := c.Request().Header.Get(adminHeader); tokenMatches(token, a.adminToken) {
+		return token, true
+	}
+
+	if token := c.Request().Header.Get(userHeader); tokenMatches(token, a.userToken) {
+		return token, true
+	}
+
+	return "", false
+}
+
+// Then returns a copy of a that passes authenticated requests through mw
+// before the handler. Requests with a wrong token never reach mw.
+func (a Auth) Then(mw echo.MiddlewareFunc) Auth {
+	a.authenticated = mw
+	return a
+}
+
+func (a Auth) wrap(next echo.HandlerFunc) echo.HandlerFunc {
+	if a.authenticated == nil {
+		return next
+	}
+
+	return a.authenticated(next)
+}
+
+// AdminOnlyMiddleware answers 403 FORBIDDEN to a valid user token, so that
+// the client can tell missing rights from a wrong token (401 UNAUTHORIZED).
+func (a Auth) AdminOnlyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	next = a.wrap(next)
 
 	return func(c echo.Context) error {
 		token := c.Request().Header.Get(adminHeader)
-		if token == "" || token != adminToken {
-			return c.JSON(http.StatusUnauthorized,
-				dto.NewErrorResponse("BAD_REQUEST", "invalid admin token"),
-			)
+		if tokenMatches(token, a.adminToken) {
+			c.Set(callerTokenKey, token)
+			return next(c)
 		}
 
-		return next(c)
+		if u := c.Request().Header.Get(userHeader); token == "" && tokenMatches(u, a.userToken) {
+			return WriteError(c, http.StatusForbidden, errCodeForbidden, "admin token required")
+		}
+
+		return WriteError(c, http.StatusUnauthorized, errCodeUnauthorized, "invalid admin token")
 	}
 }
 
-func AdminOrUserMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	adminToken := os.Getenv("ADMIN_TOKEN")
-	userToken := os.Getenv("USER_TOKEN")
+func (a Auth) AdminOrUserMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	next = a.wrap(next)
 
 	return func(c echo.Context) error {
-		a := c.Request().Header.Get(adminHeader)
-		u := c.Request().Header.Get(userHeader)
-
-		ok := (adminToken != "" && a == adminToken) || (userToken != "" && u == userToken)
+		token, ok := a.callerToken(c)
 		if !ok {
-			return c.JSON(http.StatusUnauthorized,
-				dto.NewErrorResponse("BAD_REQUEST", "invalid token"),
-			)
+			return WriteError(c, http.StatusUnauthorized, errCodeUnauthorized, "invalid token")
 		}
 
+		c.Set(callerTokenKey, token)
+
 		return next(c)
 	}
 }
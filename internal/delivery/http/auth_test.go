@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestAuthMiddlewares(t *testing.T) {
+	auth := NewAuth(testAdminToken, testUserToken)
+
+	e := echo.New()
+	ok := func(c echo.Context) error { return c.String(http.StatusOK, c.Get(callerTokenKey).(string)) }
+	e.GET("/admin", ok, auth.AdminOnlyMiddleware)
+	e.GET("/any", ok, auth.AdminOrUserMiddleware)
+
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		token    string
+		wantCode int
+	}{
+		{name: "admin on admin route", path: "/admin", header: adminHeader, token: testAdminToken, wantCode: 200},
+		{name: "user on admin route", path: "/admin", header: userHeader, token: testUserToken, wantCode: 403},
+		{name: "wrong user on admin route", path: "/admin", header: userHeader, token: "user", wantCode: 401},
+		{name: "user token as admin", path: "/admin", header: adminHeader, token: testUserToken, wantCode: 401},
+		{name: "token prefix", path: "/admin", header: adminHeader, token: "admin", wantCode: 401},
+		{name: "no token", path: "/any", wantCode: 401},
+		{name: "admin on shared route", path: "/any", header: adminHeader, token: testAdminToken, wantCode: 200},
+		{name: "user on shared route", path: "/any", header: userHeader, token: testUserToken, wantCode: 200},
+		{name: "wrong user token", path: "/any", header: userHeader, token: "user-secreT", wantCode: 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d", rec.Code, tt.wantCode)
+			}
+
+			if tt.wantCode == http.StatusOK && rec.Body.String() != tt.token {
+				t.Errorf("got caller token %q, want %q", rec.Body.String(), tt.token)
+			}
+		})
+	}
+
+	// An empty configured token never matches, not even an empty header.
+	empty := NewAuth("", "")
+	e.GET("/empty", ok, empty.AdminOrUserMiddleware)
+
+	req := httptest.NewRequest(http.MethodGet, "/empty", nil)
+	req.Header.Set(adminHeader, "")
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("got status %d with no tokens configured, want 401", rec.Code)
+	}
+}
@@ -1,19 +1,34 @@
 package dto
 
-type Error struct {
-	Code    string `json:"code"`
+// Violation is a request field that does not match the API, e.g.
+// body.members[0].user_id.
+type Violation struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
+type Error struct {
+	Code       string      `json:"code"`
+	Message    string      `json:"message"`
+	Violations []Violation `json:"violations,omitempty"`
+	RequestID  string      `json:"request_id,omitempty"`
+	TraceID    string      `json:"trace_id,omitempty"`
+}
+
 type ErrorDTO struct {
 	Error Error `json:"error"`
 }
 
-func NewErrorResponse(code, message string) ErrorDTO {
-	return ErrorDTO{
-		Error: Error{
-			Code:    code,
-			Message: message,
-		},
-	}
+// ProblemDTO is the error as application/problem+json (RFC 9457), with
+// the fields of Error as extension members.
+type ProblemDTO struct {
+	Type       string      `json:"type"`
+	Title      string      `json:"title"`
+	Status     int         `json:"status"`
+	Detail     string      `json:"detail"`
+	Instance   string      `json:"instance,omitempty"`
+	Code       string      `json:"code"`
+	Violations []Violation `json:"violations,omitempty"`
+	RequestID  string      `json:"request_id,omitempty"`
+	TraceID    string      `json:"trace_id,omitempty"`
 }
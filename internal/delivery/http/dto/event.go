@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type EventDTO struct {
+	Type          string   `json:"type"`
+	PullRequestID string   `json:"pull_request_id,omitempty"`
+	UserID        string   `json:"user_id"`
+	OldUserID     string   `json:"old_user_id,omitempty"`
+	Reviewers     []string `json:"assigned_reviewers,omitempty"`
+	TeamName      string   `json:"team_name"`
+	IsActive      *bool    `json:"is_active,omitempty"`
+	OccurredAt    string   `json:"occurred_at"`
+}
+
+func EventDomainToDTO(e domain.Event) EventDTO {
+	var isActivePtr *bool
+
+	if e.Type == domain.EventUserActivityChanged {
+		isActive := e.IsActive
+		isActivePtr = &isActive
+	}
+
+	return EventDTO{
+		Type:          string(e.Type),
+		PullRequestID: e.PullRequestID,
+		UserID:        e.UserID,
+		OldUserID:     e.OldUserID,
+		Reviewers:     e.Reviewers,
+		TeamName:      e.TeamName,
+		IsActive:      isActivePtr,
+		OccurredAt:    e.OccurredAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/health"
+
+const (
+	ReadinessStatusReady    = "ready"
+	ReadinessStatusNotReady = "not_ready"
+	ReadinessStatusDraining = "draining"
+
+	CheckStatusOK   = "ok"
+	CheckStatusFail = "fail"
+)
+
+// ReadinessDTO does not expose check errors: /readyz is open to anyone
+// who can reach the service, the errors are logged instead.
+type ReadinessDTO struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func ReadinessToDTO(report health.Report) ReadinessDTO {
+	out := ReadinessDTO{
+		Status: ReadinessStatusReady,
+		Checks: make(map[string]string, len(report.Checks)),
+	}
+
+	switch {
+	case report.Draining:
+		out.Status = ReadinessStatusDraining
+	case !report.Ready:
+		out.Status = ReadinessStatusNotReady
+	}
+
+	for _, check := range report.Checks {
+		status := CheckStatusOK
+		if check.Err != nil {
+			status = CheckStatusFail
+		}
+
+		out.Checks[check.Name] = status
+	}
+
+	return out
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/policy"
+)
+
+type PolicyDTO struct {
+	Version        string  `json:"version"`
+	Source         string  `json:"source"`
+	LoadedAt       string  `json:"loaded_at"`
+	ReviewersPerPR int     `json:"reviewers_per_pr"`
+	Strategy       string  `json:"strategy"`
+	MaxOpenReviews int     `json:"max_open_reviews"`
+	LastError      *string `json:"last_error,omitempty"`
+	LastErrorAt    *string `json:"last_error_at,omitempty"`
+}
+
+func PolicyStatusToDTO(status policy.Status) PolicyDTO {
+	out := PolicyDTO{
+		Version:        status.Version,
+		Source:         status.Source,
+		LoadedAt:       status.LoadedAt.Format(time.RFC3339),
+		ReviewersPerPR: status.ReviewersPerPR,
+		Strategy:       string(status.Strategy),
+		MaxOpenReviews: status.MaxOpenReviews,
+	}
+
+	if status.LastError != "" {
+		lastErrorAt := status.LastErrorAt.Format(time.RFC3339)
+		out.LastError = &status.LastError
+		out.LastErrorAt = &lastErrorAt
+	}
+
+	return out
+}
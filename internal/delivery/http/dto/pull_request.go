@@ -15,6 +15,8 @@ type PullRequestDTO struct {
 	AssignedReviewers []string `json:"assigned_reviewers"`
 	CreatedAt         *string  `json:"createdAt,omitempty"`
 	MergedAt          *string  `json:"mergedAt,omitempty"`
+	Version           int64    `json:"version,omitempty"`
+	ArchivedAt        *string  `json:"archivedAt,omitempty"`
 }
 
 type PullRequestShortDTO struct {
@@ -22,11 +24,13 @@ type PullRequestShortDTO struct {
 	Name     string `json:"pull_request_name"`
 	AuthorID string `json:"author_id"`
 	Status   string `json:"status"`
+	Archived bool   `json:"archived,omitempty"`
 }
 
 func PullRequestDomainToDTO(pr domain.PullRequest) PullRequestDTO {
 	var createdAtPtr *string
 	var mergedAtPtr *string
+	var archivedAtPtr *string
 
 	if !pr.CreatedAt.IsZero() {
 		s := pr.CreatedAt.Format(time.RFC3339)
@@ -38,14 +42,26 @@ func PullRequestDomainToDTO(pr domain.PullRequest) PullRequestDTO {
 		mergedAtPtr = &s
 	}
 
+	if pr.ArchivedAt != nil {
+		s := pr.ArchivedAt.Format(time.RFC3339)
+		archivedAtPtr = &s
+	}
+
+	reviewers := pr.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+
 	return PullRequestDTO{
 		ID:                pr.ID,
 		Name:              pr.Name,
 		AuthorID:          pr.AuthorID,
 		Status:            string(pr.Status),
-		AssignedReviewers: pr.AssignedReviewers,
+		AssignedReviewers: reviewers,
 		CreatedAt:         createdAtPtr,
 		MergedAt:          mergedAtPtr,
+		Version:           pr.Version,
+		ArchivedAt:        archivedAtPtr,
 	}
 }
 
@@ -88,6 +104,7 @@ func PullRequestShortDomainToDTO(pr domain.PullRequest) PullRequestShortDTO {
 		Name:     pr.Name,
 		AuthorID: pr.AuthorID,
 		Status:   string(pr.Status),
+		Archived: pr.ArchivedAt != nil,
 	}
 }
 
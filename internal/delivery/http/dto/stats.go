@@ -0,0 +1,74 @@
+package dto
+
+import "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+
+type TxStatsDTO struct {
+	Transactions          uint64 `json:"transactions"`
+	Commits               uint64 `json:"commits"`
+	Retries               uint64 `json:"retries"`
+	RetriesExhausted      uint64 `json:"retries_exhausted"`
+	SerializationFailures uint64 `json:"serialization_failures"`
+	Deadlocks             uint64 `json:"deadlocks"`
+}
+
+type PoolStatsDTO struct {
+	Name              string `json:"name"`
+	MaxConns          int32  `json:"max_conns"`
+	TotalConns        int32  `json:"total_conns"`
+	IdleConns         int32  `json:"idle_conns"`
+	AcquiredConns     int32  `json:"acquired_conns"`
+	AcquireCount      int64  `json:"acquire_count"`
+	EmptyAcquireCount int64  `json:"empty_acquire_count"`
+	AcquireDurationMs int64  `json:"acquire_duration_ms"`
+	Healthy           bool   `json:"healthy"`
+	LagMs             int64  `json:"lag_ms"`
+}
+
+type CacheStatsDTO struct {
+	Enabled    bool   `json:"enabled"`
+	TeamHits   uint64 `json:"team_hits"`
+	TeamMisses uint64 `json:"team_misses"`
+	UserHits   uint64 `json:"user_hits"`
+	UserMisses uint64 `json:"user_misses"`
+}
+
+func TxStatsToDTO(stats store.TxStats) TxStatsDTO {
+	return TxStatsDTO{
+		Transactions:          stats.Transactions,
+		Commits:               stats.Commits,
+		Retries:               stats.Retries,
+		RetriesExhausted:      stats.RetriesExhausted,
+		SerializationFailures: stats.SerializationFailures,
+		Deadlocks:             stats.Deadlocks,
+	}
+}
+
+func PoolStatsToDTOs(stats []store.PoolStats) []PoolStatsDTO {
+	pools := make([]PoolStatsDTO, 0, len(stats))
+	for _, stat := range stats {
+		pools = append(pools, PoolStatsDTO{
+			Name:              stat.Name,
+			MaxConns:          stat.MaxConns,
+			TotalConns:        stat.TotalConns,
+			IdleConns:         stat.IdleConns,
+			AcquiredConns:     stat.AcquiredConns,
+			AcquireCount:      stat.AcquireCount,
+			EmptyAcquireCount: stat.EmptyAcquireCount,
+			AcquireDurationMs: stat.AcquireDuration.Milliseconds(),
+			Healthy:           stat.Healthy,
+			LagMs:             stat.Lag.Milliseconds(),
+		})
+	}
+
+	return pools
+}
+
+func CacheStatsToDTO(stats store.CacheStats) CacheStatsDTO {
+	return CacheStatsDTO{
+		Enabled:    stats.Enabled,
+		TeamHits:   stats.TeamHits,
+		TeamMisses: stats.TeamMisses,
+		UserHits:   stats.UserHits,
+		UserMisses: stats.UserMisses,
+	}
+}
@@ -2,39 +2,134 @@ package http
 
 import (
 	"errors"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
 )
 
+const (
+	errCodeBadRequest     = "BAD_REQUEST"
+	errCodeUnauthorized   = "UNAUTHORIZED"
+	errCodeForbidden      = "FORBIDDEN"
+	errCodeInternalServer = "INTERNAL_SERVER_ERROR"
+
+	mimeApplicationProblemJSON = "application/problem+json"
+)
+
+// errorStatuses maps every domain error code to its HTTP status. A test
+// fails when a code declared in the domain package is missing here.
+var errorStatuses = map[domain.ErrorCode]int{
+	domain.ErrCodeTeamExists:           http.StatusBadRequest,
+	domain.ErrCodePRExists:             http.StatusConflict,
+	domain.ErrCodePRMerged:             http.StatusConflict,
+	domain.ErrCodeNotAssigned:          http.StatusConflict,
+	domain.ErrCodeNoCandidate:          http.StatusConflict,
+	domain.ErrCodeNotFound:             http.StatusNotFound,
+	domain.ErrCodeIdempotencyKeyReused: http.StatusConflict,
+	domain.ErrCodeRequestInProgress:    http.StatusConflict,
+	domain.ErrCodePreconditionFailed:   http.StatusPreconditionFailed,
+	domain.ErrCodeInvalidArgument:      http.StatusBadRequest,
+}
+
 func HandleError(c echo.Context, err error) error {
 	var domainError *domain.Error
 
 	if errors.As(err, &domainError) {
-		status := httpStatusCodeMapper(domainError.Code)
-		return c.JSON(status, dto.NewErrorResponse(string(domainError.Code), domainError.Message))
+		return WriteError(c, httpStatusCodeMapper(domainError.Code), string(domainError.Code), domainError.Message)
 	}
 
-	return c.JSON(http.StatusInternalServerError, dto.NewErrorResponse("INTERNAL_SERVER_ERROR", "something went wrong"))
+	c.Set(internalErrorKey, err)
+
+	return WriteError(c, http.StatusInternalServerError, errCodeInternalServer, "something went wrong")
+}
+
+// BadRequest answers 400 BAD_REQUEST with the message.
+func BadRequest(c echo.Context, message string, violations ...dto.Violation) error {
+	return WriteError(c, http.StatusBadRequest, errCodeBadRequest, message, violations...)
+}
+
+// WriteError answers with the error as JSON, or as application/problem+json
+// if the client asks for it in Accept. The request and trace IDs are added,
+// so that a failure reported by a client can be found in the logs and the
+// tracing backend.
+func WriteError(c echo.Context, status int, code, message string, violations ...dto.Violation) error {
+	c.Set(errorCodeKey, code)
+
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+	traceID := tracing.TraceID(c.Request().Context())
+
+	if !prefersProblem(c.Request().Header.Get(echo.HeaderAccept)) {
+		return c.JSON(status, dto.ErrorDTO{Error: dto.Error{
+			Code:       code,
+			Message:    message,
+			Violations: violations,
+			RequestID:  requestID,
+			TraceID:    traceID,
+		}})
+	}
+
+	problem := dto.ProblemDTO{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     message,
+		Instance:   c.Request().URL.Path,
+		Code:       code,
+		Violations: violations,
+		RequestID:  requestID,
+		TraceID:    traceID,
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, mimeApplicationProblemJSON)
+	c.Response().WriteHeader(status)
+
+	return c.Echo().JSONSerializer.Serialize(c, problem, "")
+}
+
+// prefersProblem reports whether Accept rates application/problem+json
+// at least as high as application/json. Wildcards do not count, so that
+// clients not asking for problems keep getting the usual format.
+func prefersProblem(accept string) bool {
+	if accept == "" {
+		return false
+	}
+
+	var problemQ, jsonQ float64
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+
+		switch mediaType {
+		case mimeApplicationProblemJSON:
+			problemQ = max(problemQ, q)
+		case echo.MIMEApplicationJSON:
+			jsonQ = max(jsonQ, q)
+		}
+	}
+
+	return problemQ > 0 && problemQ >= jsonQ
 }
 
 func httpStatusCodeMapper(code domain.ErrorCode) int {
-	switch code {
-	case domain.ErrCodeTeamExists:
-		return http.StatusBadRequest
-	case domain.ErrCodePRExists:
-		return http.StatusConflict
-	case domain.ErrCodePRMerged:
-		return http.StatusConflict
-	case domain.ErrCodeNotAssigned:
-		return http.StatusConflict
-	case domain.ErrCodeNoCandidate:
-		return http.StatusConflict
-	case domain.ErrCodeNotFound:
-		return http.StatusNotFound
-	default:
-		return http.StatusInternalServerError
+	if status, ok := errorStatuses[code]; ok {
+		return status
 	}
+
+	return http.StatusInternalServerError
 }
@@ -0,0 +1,15 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain/domaintest"
+)
+
+func TestEveryErrorCodeHasStatus(t *testing.T) {
+	for name, code := range domaintest.ErrorCodes(t) {
+		if _, ok := errorStatuses[code]; !ok {
+			t.Errorf("domain.%s (%s) has no HTTP status in errorStatuses and would be answered with 500", name, code)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// SetETag sets the ETag header for a resource with the given version.
+func SetETag(c echo.Context, version int64) {
+	c.Response().Header().Set(headerETag, formatETag(version))
+}
+
+// IfMatchVersion parses the If-Match header.
+// It returns zero when the header is absent or equals "*", and an
+// INVALID_ARGUMENT error when it is not a single strong entity tag.
+func IfMatchVersion(c echo.Context) (int64, error) {
+	value := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	// Weak tags never match under If-Match, see RFC 9110 section 13.1.1.
+	if strings.HasPrefix(value, "W/") {
+		return 0, domain.NewError(domain.ErrCodeInvalidArgument, "weak entity tags are not allowed in If-Match")
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(value, `"`), 10, 64)
+	if err != nil || version <= 0 {
+		return 0, domain.NewError(domain.ErrCodeInvalidArgument, "If-Match must be a single entity tag")
+	}
+
+	return version, nil
+}
+
+// NotModified reports whether If-None-Match matches the current version.
+func NotModified(c echo.Context, version int64) bool {
+	value := c.Request().Header.Get(headerIfNoneMatch)
+	if value == "" {
+		return false
+	}
+
+	current := formatETag(version)
+
+	for tag := range strings.SplitSeq(value, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+
+	return false
+}
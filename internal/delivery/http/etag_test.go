@@ -0,0 +1,59 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifMatch     string
+		wantVersion int64
+		wantCode    domain.ErrorCode
+	}{
+		{name: "absent"},
+		{name: "any", ifMatch: "*"},
+		{name: "strong tag", ifMatch: `"3"`, wantVersion: 3},
+		{name: "surrounding spaces", ifMatch: ` "3" `, wantVersion: 3},
+		{name: "weak tag", ifMatch: `W/"3"`, wantCode: domain.ErrCodeInvalidArgument},
+		{name: "list", ifMatch: `"3", "4"`, wantCode: domain.ErrCodeInvalidArgument},
+		{name: "not a number", ifMatch: `"abc"`, wantCode: domain.ErrCodeInvalidArgument},
+		{name: "zero", ifMatch: `"0"`, wantCode: domain.ErrCodeInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set(headerIfMatch, tt.ifMatch)
+			}
+
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			version, err := IfMatchVersion(c)
+
+			if tt.wantCode != "" {
+				var domainError *domain.Error
+				if !errors.As(err, &domainError) || domainError.Code != tt.wantCode {
+					t.Fatalf("error = %v, want %s", err, tt.wantCode)
+				}
+
+				if status := httpStatusCodeMapper(domainError.Code); status != http.StatusBadRequest {
+					t.Fatalf("status = %d, want %d", status, http.StatusBadRequest)
+				}
+
+				return
+			}
+
+			if err != nil || version != tt.wantVersion {
+				t.Fatalf("IfMatchVersion() = %d, %v, want %d", version, err, tt.wantVersion)
+			}
+		})
+	}
+}
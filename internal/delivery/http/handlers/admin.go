@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	deliveryhttp "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/policy"
+	snapshotservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/snapshot"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/snapshot"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+)
+
+type TxStatsProvider interface {
+	Stats() store.TxStats
+}
+
+type PoolStatsProvider interface {
+	PoolStats() []store.PoolStats
+}
+
+type CacheStatsProvider interface {
+	Stats() store.CacheStats
+}
+
+type SnapshotService interface {
+	Export(ctx context.Context, w snapshotservice.Writer) error
+	Import(
+		ctx context.Context,
+		snapshot domain.Snapshot,
+		mode domain.ImportMode,
+		dryRun bool,
+	) (domain.ImportReport, error)
+}
+
+type PolicyProvider interface {
+	Status() policy.Status
+}
+
+func RegisterAdminRoutes(
+	e *echo.Echo,
+	auth deliveryhttp.Auth,
+	txStats TxStatsProvider,
+	poolStats PoolStatsProvider,
+	cacheStats CacheStatsProvider,
+	snapshots SnapshotService,
+	idempotent echo.MiddlewareFunc,
+	policies PolicyProvider,
+) {
+	admin := e.Group("/admin", auth.AdminOnlyMiddleware)
+
+	admin.GET("/stats/transactions", txStatsHandler(txStats))
+	admin.GET("/stats/pools", poolStatsHandler(poolStats))
+	admin.GET("/stats/cache", cacheStatsHandler(cacheStats))
+	admin.GET("/export", exportHandler(snapshots))
+	admin.POST("/import", idempotent(importHandler(snapshots)))
+	admin.GET("/policy", policyHandler(policies))
+}
+
+// txStatsHandler handles GET /admin/stats/transactions.
+func txStatsHandler(p TxStatsProvider) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, dto.TxStatsToDTO(p.Stats()))
+	}
+}
+
+// poolStatsHandler handles GET /admin/stats/pools.
+func poolStatsHandler(p PoolStatsProvider) echo.HandlerFunc {
+	type responseBody struct {
+		Pools []dto.PoolStatsDTO `json:"pools"`
+	}
+
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, responseBody{Pools: dto.PoolStatsToDTOs(p.PoolStats())})
+	}
+}
+
+// policyHandler handles GET /admin/policy.
+func policyHandler(p PolicyProvider) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, dto.PolicyStatusToDTO(p.Status()))
+	}
+}
+
+// cacheStatsHandler handles GET /admin/stats/cache.
+func cacheStatsHandler(p CacheStatsProvider) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, dto.CacheStatsToDTO(p.Stats()))
+	}
+}
+
+// exportHandler handles GET /admin/export.
+func exportHandler(s SnapshotService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		now := time.Now()
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c.Response().Header().Set(
+			echo.HeaderContentDisposition,
+			fmt.Sprintf(`attachment; filename="snapshot-%s.json"`, now.UTC().Format("20060102T150405Z")),
+		)
+
+		w := snapshot.NewWriter(c.Response(), now)
+
+		err := s.Export(c.Request().Context(), w)
+		if err == nil {
+			err = w.Close()
+		}
+
+		if err != nil {
+			if !c.Response().Committed {
+				return deliveryhttp.HandleError(c, err)
+			}
+
+			// The status is already sent: the client sees a truncated document.
+			logging.FromContext(c.Request().Context()).Error("export aborted", logging.Error(err))
+		}
+
+		return nil
+	}
+}
+
+// importHandler handles POST /admin/import.
+func importHandler(s SnapshotService) echo.HandlerFunc {
+	type responseBody struct {
+		Report snapshot.ReportJSON `json:"report"`
+	}
+
+	return func(c echo.Context) error {
+		mode := domain.ImportMode(c.QueryParam("mode"))
+		switch mode {
+		case "":
+			mode = domain.ImportModeMerge
+		case domain.ImportModeMerge, domain.ImportModeReplace:
+		default:
+			return deliveryhttp.BadRequest(c, "mode must be merge or replace")
+		}
+
+		dryRun := false
+		if raw := c.QueryParam("dry_run"); raw != "" {
+			var err error
+			if dryRun, err = strconv.ParseBool(raw); err != nil {
+				return deliveryhttp.BadRequest(c, "dry_run must be a boolean")
+			}
+		}
+
+		snap, err := snapshot.Decode(c.Request().Body)
+		if deliveryhttp.IsBodyTooLarge(err) {
+			return deliveryhttp.BodyTooLarge(c)
+		}
+
+		if err != nil {
+			return deliveryhttp.BadRequest(c, err.Error())
+		}
+
+		report, err := s.Import(c.Request().Context(), snap, mode, dryRun)
+		if err != nil {
+			return deliveryhttp.HandleError(c, err)
+		}
+
+		status := http.StatusOK
+		if len(report.Errors) > 0 {
+			status = http.StatusUnprocessableEntity
+		}
+
+		return c.JSON(status, responseBody{Report: snapshot.NewReportJSON(report)})
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// docsPage renders /openapi.yml with Swagger UI. The UI is loaded from
+// a CDN to keep it out of the binary.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>PR Reviewer Assignment Service</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.yml", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+const mimeApplicationYAML = "application/yaml"
+
+// RegisterDocsRoutes serves the OpenAPI document and its UI without
+// authorization.
+func RegisterDocsRoutes(e *echo.Echo, spec []byte) {
+	e.GET("/openapi.yml", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, mimeApplicationYAML, spec)
+	})
+	e.GET("/docs", func(c echo.Context) error {
+		return c.HTML(http.StatusOK, docsPage)
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	deliveryhttp "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type EventSubscriber interface {
+	Subscribe(filter domain.EventFilter) (<-chan domain.Event, func())
+}
+
+func RegisterEventRoutes(e *echo.Echo, s EventSubscriber, auth deliveryhttp.Auth, heartbeatInterval time.Duration) {
+	e.GET("/events/stream", auth.AdminOrUserMiddleware(streamEventsHandler(s, heartbeatInterval)))
+}
+
+// streamEventsHandler handles GET /events/stream.
+func streamEventsHandler(s EventSubscriber, heartbeatInterval time.Duration) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		filter := domain.EventFilter{
+			UserID:   c.QueryParam("user_id"),
+			TeamName: c.QueryParam("team_name"),
+		}
+
+		events, unsubscribe := s.Subscribe(filter)
+		defer unsubscribe()
+
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, "text/event-stream")
+		res.Header().Set(echo.HeaderCacheControl, "no-cache")
+		res.Header().Set(echo.HeaderConnection, "keep-alive")
+		res.WriteHeader(http.StatusOK)
+		res.Flush()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		ctx := c.Request().Context()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+					return nil
+				}
+				res.Flush()
+			case event, ok := <-events:
+				if !ok {
+					return nil
+				}
+
+				data, err := json.Marshal(dto.EventDomainToDTO(event))
+				if err != nil {
+					return fmt.Errorf("error encoding event: %w", err)
+				}
+
+				if _, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+					return nil
+				}
+				res.Flush()
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/health"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+)
+
+type ReadinessChecker interface {
+	Check(ctx context.Context) health.Report
+}
+
+func RegisterHealthRoutes(e *echo.Echo, readiness ReadinessChecker) {
+	e.GET("/livez", livenessHandler)
+	// /health predates the probes and stays a liveness check.
+	e.GET("/health", livenessHandler)
+	e.GET("/readyz", readinessHandler(readiness))
+}
+
+// livenessHandler handles GET /livez. It checks no dependencies:
+// restarting the process would not bring the database back.
+func livenessHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readinessHandler handles GET /readyz.
+func readinessHandler(readiness ReadinessChecker) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		report := readiness.Check(ctx)
+
+		for _, check := range report.Checks {
+			if check.Err != nil {
+				logging.FromContext(ctx).Warn("readiness check failed",
+					slog.String("check", check.Name),
+					logging.Error(check.Err),
+				)
+			}
+		}
+
+		status := http.StatusOK
+		if !report.Ready {
+			status = http.StatusServiceUnavailable
+		}
+
+		return c.JSON(status, dto.ReadinessToDTO(report))
+	}
+}
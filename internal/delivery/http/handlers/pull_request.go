@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 	deliveryhttp "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http"
@@ -12,14 +14,32 @@ import (
 
 type PullRequestService interface {
 	CreatePullRequest(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error)
-	MergePullRequest(ctx context.Context, prID string) (domain.PullRequest, error)
-	ReassignPullRequest(ctx context.Context, prID string, oldReviewerID string) (domain.PullRequest, string, error)
+	GetPullRequest(ctx context.Context, prID string, includeArchived bool) (domain.PullRequest, error)
+	MergePullRequest(ctx context.Context, prID string, expectedVersion int64) (domain.PullRequest, error)
+	ReassignPullRequest(
+		ctx context.Context,
+		prID string,
+		oldReviewerID string,
+		expectedVersion int64,
+	) (domain.PullRequest, string, error)
+	GetStats(ctx context.Context) (domain.PullRequestStats, error)
+	DeletePullRequest(ctx context.Context, prID string) error
+	RestorePullRequest(ctx context.Context, prID string) (domain.PullRequest, error)
 }
 
-func RegisterPullRequestRoutes(e *echo.Echo, s PullRequestService) {
-	e.POST("/pullRequest/create", deliveryhttp.AdminOnlyMiddleware(createPullRequestHandler(s)))
-	e.POST("/pullRequest/merge", deliveryhttp.AdminOnlyMiddleware(mergePullRequestHandler(s)))
-	e.POST("/pullRequest/reassign", deliveryhttp.AdminOnlyMiddleware(reassignPullRequestHandler(s)))
+func RegisterPullRequestRoutes(
+	e *echo.Echo,
+	s PullRequestService,
+	auth deliveryhttp.Auth,
+	idempotent echo.MiddlewareFunc,
+) {
+	e.GET("/pullRequest/get", auth.AdminOrUserMiddleware(getPullRequestHandler(s)))
+	e.GET("/pullRequest/stats", auth.AdminOrUserMiddleware(pullRequestStatsHandler(s)))
+	e.POST("/pullRequest/create", auth.AdminOnlyMiddleware(idempotent(createPullRequestHandler(s))))
+	e.POST("/pullRequest/merge", auth.AdminOnlyMiddleware(idempotent(mergePullRequestHandler(s))))
+	e.POST("/pullRequest/reassign", auth.AdminOnlyMiddleware(idempotent(reassignPullRequestHandler(s))))
+	e.POST("/pullRequest/delete", auth.AdminOnlyMiddleware(idempotent(deletePullRequestHandler(s))))
+	e.POST("/pullRequest/restore", auth.AdminOnlyMiddleware(idempotent(restorePullRequestHandler(s))))
 }
 
 // createPullRequestHandler handles POST /pullRequest/create.
@@ -33,7 +53,7 @@ func createPullRequestHandler(s PullRequestService) echo.HandlerFunc {
 		var req requestBody
 
 		if err := c.Bind(&req); err != nil {
-			return c.JSON(http.StatusBadRequest, dto.NewErrorResponse("BAD_REQUEST", "invalid JSON body"))
+			return deliveryhttp.BindError(c, err)
 		}
 
 		domainPullRequest, err := dto.PullRequestDTOToDomain(req)
@@ -46,12 +66,60 @@ func createPullRequestHandler(s PullRequestService) echo.HandlerFunc {
 			return deliveryhttp.HandleError(c, err)
 		}
 
+		deliveryhttp.SetETag(c, pr.Version)
+
 		return c.JSON(http.StatusCreated, responseBody{
 			PullRequest: dto.PullRequestDomainToDTO(pr),
 		})
 	}
 }
 
+// getPullRequestHandler handles GET /pullRequest/get.
+func getPullRequestHandler(s PullRequestService) echo.HandlerFunc {
+	type responseBody struct {
+		PullRequest dto.PullRequestDTO `json:"pr"`
+	}
+
+	return func(c echo.Context) error {
+		prID := c.QueryParam("pull_request_id")
+
+		includeArchived, err := includeArchivedParam(c)
+		if err != nil {
+			return deliveryhttp.BadRequest(c, err.Error())
+		}
+
+		pr, err := s.GetPullRequest(c.Request().Context(), prID, includeArchived)
+		if err != nil {
+			return deliveryhttp.HandleError(c, err)
+		}
+
+		deliveryhttp.SetETag(c, pr.Version)
+
+		if deliveryhttp.NotModified(c, pr.Version) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		return c.JSON(http.StatusOK, responseBody{
+			PullRequest: dto.PullRequestDomainToDTO(pr),
+		})
+	}
+}
+
+// includeArchivedParam reads the optional include_archived query parameter.
+func includeArchivedParam(c echo.Context) (bool, error) {
+	value := c.QueryParam("include_archived")
+	if value == "" {
+		return false, nil
+	}
+
+	includeArchived, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("include_archived must be a boolean")
+	}
+
+	return includeArchived, nil
+}
+
 // mergePullRequestHandler handles POST /pullRequest/merge.
 func mergePullRequestHandler(s PullRequestService) echo.HandlerFunc {
 	type requestBody struct {
@@ -65,18 +133,21 @@ func mergePullRequestHandler(s PullRequestService) echo.HandlerFunc {
 		var req requestBody
 
 		if err := c.Bind(&req); err != nil {
-			return c.JSON(http.StatusBadRequest, dto.NewErrorResponse("BAD_REQUEST", "invalid JSON body"))
+			return deliveryhttp.BindError(c, err)
 		}
 
-		if req.PullRequestID == "" {
-			return c.JSON(http.StatusBadRequest, dto.NewErrorResponse("BAD_REQUEST", "pull_request_id is required"))
+		expectedVersion, err := deliveryhttp.IfMatchVersion(c)
+		if err != nil {
+			return deliveryhttp.HandleError(c, err)
 		}
 
-		pr, err := s.MergePullRequest(c.Request().Context(), req.PullRequestID)
+		pr, err := s.MergePullRequest(c.Request().Context(), req.PullRequestID, expectedVersion)
 		if err != nil {
 			return deliveryhttp.HandleError(c, err)
 		}
 
+		deliveryhttp.SetETag(c, pr.Version)
+
 		return c.JSON(http.StatusOK, responseBody{
 			PullRequest: dto.PullRequestDomainToDTO(pr),
 		})
@@ -98,24 +169,110 @@ func reassignPullRequestHandler(s PullRequestService) echo.HandlerFunc {
 		var req requestBody
 
 		if err := c.Bind(&req); err != nil {
-			return c.JSON(http.StatusBadRequest, dto.NewErrorResponse("BAD_REQUEST", "invalid JSON body"))
+			return deliveryhttp.BindError(c, err)
 		}
 
-		if req.PullRequestID == "" || req.OldUserID == "" {
-			return c.JSON(
-				http.StatusBadRequest,
-				dto.NewErrorResponse("BAD_REQUEST", "pull_request_id and old_user_id are required"),
-			)
+		expectedVersion, err := deliveryhttp.IfMatchVersion(c)
+		if err != nil {
+			return deliveryhttp.HandleError(c, err)
 		}
 
-		pr, replacedBy, err := s.ReassignPullRequest(c.Request().Context(), req.PullRequestID, req.OldUserID)
+		pr, replacedBy, err := s.ReassignPullRequest(
+			c.Request().Context(),
+			req.PullRequestID,
+			req.OldUserID,
+			expectedVersion,
+		)
 		if err != nil {
 			return deliveryhttp.HandleError(c, err)
 		}
 
+		deliveryhttp.SetETag(c, pr.Version)
+
 		return c.JSON(http.StatusOK, responseBody{
 			PullRequest: dto.PullRequestDomainToDTO(pr),
 			ReplacedBy:  replacedBy,
 		})
 	}
 }
+
+// pullRequestStatsHandler handles GET /pullRequest/stats.
+func pullRequestStatsHandler(s PullRequestService) echo.HandlerFunc {
+	type reviewerStats struct {
+		UserID      string `json:"user_id"`
+		Assignments int    `json:"assignments"`
+	}
+
+	type responseBody struct {
+		Open      int             `json:"open"`
+		Merged    int             `json:"merged"`
+		Archived  int             `json:"archived"`
+		Reviewers []reviewerStats `json:"reviewers"`
+	}
+
+	return func(c echo.Context) error {
+		stats, err := s.GetStats(c.Request().Context())
+		if err != nil {
+			return deliveryhttp.HandleError(c, err)
+		}
+
+		reviewers := make([]reviewerStats, 0, len(stats.Reviewers))
+		for _, r := range stats.Reviewers {
+			reviewers = append(reviewers, reviewerStats{UserID: r.UserID, Assignments: r.Assignments})
+		}
+
+		return c.JSON(http.StatusOK, responseBody{
+			Open:      stats.Open,
+			Merged:    stats.Merged,
+			Archived:  stats.Archived,
+			Reviewers: reviewers,
+		})
+	}
+}
+
+type pullRequestIDRequestBody struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+// deletePullRequestHandler handles POST /pullRequest/delete.
+func deletePullRequestHandler(s PullRequestService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req pullRequestIDRequestBody
+
+		if err := c.Bind(&req); err != nil {
+			return deliveryhttp.BindError(c, err)
+		}
+
+		if err := s.DeletePullRequest(c.Request().Context(), req.PullRequestID); err != nil {
+			return deliveryhttp.HandleError(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// restorePullRequestHandler handles POST /pullRequest/restore.
+func restorePullRequestHandler(s PullRequestService) echo.HandlerFunc {
+	type responseBody struct {
+		PullRequest dto.PullRequestDTO `json:"pr"`
+	}
+
+	return func(c echo.Context) error {
+		var req pullRequestIDRequestBody
+
+		if err := c.Bind(&req); err != nil {
+			return deliveryhttp.BindError(c, err)
+		}
+
+		pr, err := s.RestorePullRequest(c.Request().Context(), req.PullRequestID)
+		if err != nil {
+			return deliveryhttp.HandleError(c, err)
+		}
+
+		deliveryhttp.SetETag(c, pr.Version)
+
+		return c.JSON(http.StatusOK, responseBody{
+			PullRequest: dto.PullRequestDomainToDTO(pr),
+		})
+	}
+}
@@ -3,21 +3,29 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 	deliveryhttp "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/orgchart"
 )
 
 type TeamService interface {
 	CreateTeam(ctx context.Context, up domain.TeamUpsert) (domain.TeamUpsert, error)
 	GetTeamWithMembers(ctx context.Context, teamName string) (domain.TeamUpsert, error)
+	DeleteTeam(ctx context.Context, teamName string) error
+	RestoreTeam(ctx context.Context, teamName string) (domain.TeamUpsert, error)
+	ImportOrgChart(ctx context.Context, chart domain.OrgChart, dryRun bool) (domain.OrgChartDiff, error)
 }
 
-func RegisterTeamRoutes(e *echo.Group, s TeamService) {
-	e.POST("/team/add", deliveryhttp.AdminOnlyMiddleware(createTeamHandler(s)))
-	e.GET("/team/get", deliveryhttp.AdminOrUserMiddleware(getTeamHandler(s)))
+func RegisterTeamRoutes(e *echo.Group, s TeamService, auth deliveryhttp.Auth, idempotent echo.MiddlewareFunc) {
+	e.POST("/team/add", auth.AdminOnlyMiddleware(idempotent(createTeamHandler(s))))
+	e.GET("/team/get", auth.AdminOrUserMiddleware(getTeamHandler(s)))
+	e.POST("/team/delete", auth.AdminOnlyMiddleware(idempotent(deleteTeamHandler(s))))
+	e.POST("/team/restore", auth.AdminOnlyMiddleware(idempotent(restoreTeamHandler(s))))
+	e.POST("/team/import", auth.AdminOnlyMiddleware(idempotent(importTeamsHandler(s))))
 }
 
 // createTeamHandler handles POST /team/add.
@@ -31,7 +39,7 @@ func createTeamHandler(s TeamService) echo.HandlerFunc {
 		var req requestBody
 
 		if err := c.Bind(&req); err != nil {
-			return c.JSON(http.StatusBadRequest, dto.NewErrorResponse("BAD_REQUEST", "invalid JSON body"))
+			return deliveryhttp.BindError(c, err)
 		}
 
 		upsertTeam := dto.TeamDTOToDomain(req)
@@ -52,10 +60,6 @@ func getTeamHandler(s TeamService) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		teamName := c.QueryParam("team_name")
 
-		if teamName == "" {
-			return c.JSON(http.StatusBadRequest, dto.NewErrorResponse("BAD_REQUEST", "team_name is required"))
-		}
-
 		team, err := s.GetTeamWithMembers(c.Request().Context(), teamName)
 		if err != nil {
 			return deliveryhttp.HandleError(c, err)
@@ -64,3 +68,97 @@ func getTeamHandler(s TeamService) echo.HandlerFunc {
 		return c.JSON(http.StatusOK, dto.TeamDomainToDTO(team))
 	}
 }
+
+type teamNameRequestBody struct {
+	TeamName string `json:"team_name"`
+}
+
+// deleteTeamHandler handles POST /team/delete.
+func deleteTeamHandler(s TeamService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req teamNameRequestBody
+
+		if err := c.Bind(&req); err != nil {
+			return deliveryhttp.BindError(c, err)
+		}
+
+		if err := s.DeleteTeam(c.Request().Context(), req.TeamName); err != nil {
+			return deliveryhttp.HandleError(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// restoreTeamHandler handles POST /team/restore.
+func restoreTeamHandler(s TeamService) echo.HandlerFunc {
+	type responseBody struct {
+		Team dto.TeamDTO `json:"team"`
+	}
+
+	return func(c echo.Context) error {
+		var req teamNameRequestBody
+
+		if err := c.Bind(&req); err != nil {
+			return deliveryhttp.BindError(c, err)
+		}
+
+		team, err := s.RestoreTeam(c.Request().Context(), req.TeamName)
+		if err != nil {
+			return deliveryhttp.HandleError(c, err)
+		}
+
+		return c.JSON(http.StatusOK, responseBody{
+			Team: dto.TeamDomainToDTO(team),
+		})
+	}
+}
+
+// importTeamsHandler handles POST /team/import. The body is a CSV or YAML
+// org chart; the format comes from the format parameter or Content-Type.
+func importTeamsHandler(s TeamService) echo.HandlerFunc {
+	type responseBody struct {
+		Diff orgchart.DiffJSON `json:"diff"`
+	}
+
+	return func(c echo.Context) error {
+		format, ok := orgchart.Format(c.QueryParam("format")), true
+		switch format {
+		case "":
+			format, ok = orgchart.FormatFromContentType(c.Request().Header.Get(echo.HeaderContentType))
+		case orgchart.FormatCSV, orgchart.FormatYAML:
+		default:
+			ok = false
+		}
+
+		if !ok {
+			return deliveryhttp.BadRequest(c, "format must be csv or yaml")
+		}
+
+		dryRun := false
+		if raw := c.QueryParam("dry_run"); raw != "" {
+			var err error
+			if dryRun, err = strconv.ParseBool(raw); err != nil {
+				return deliveryhttp.BadRequest(c, "dry_run must be a boolean")
+			}
+		}
+
+		chart, err := orgchart.Parse(c.Request().Body, format)
+		if deliveryhttp.IsBodyTooLarge(err) {
+			return deliveryhttp.BodyTooLarge(c)
+		}
+
+		if err != nil {
+			return deliveryhttp.BadRequest(c, err.Error())
+		}
+
+		diff, err := s.ImportOrgChart(c.Request().Context(), chart, dryRun)
+		if err != nil {
+			return deliveryhttp.HandleError(c, err)
+		}
+
+		return c.JSON(http.StatusOK, responseBody{
+			Diff: orgchart.NewDiffJSON(diff),
+		})
+	}
+}
@@ -12,12 +12,16 @@ import (
 
 type UserService interface {
 	SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error)
-	ListReviewPRs(ctx context.Context, userID string) ([]domain.PullRequest, error)
+	ListReviewPRs(ctx context.Context, userID string, includeArchived bool) ([]domain.PullRequest, error)
+	DeleteUser(ctx context.Context, userID string) error
+	RestoreUser(ctx context.Context, userID string) (domain.User, error)
 }
 
-func RegisterUserRoutes(e *echo.Echo, s UserService) {
-	e.POST("/users/setIsActive", deliveryhttp.AdminOnlyMiddleware(setIsActiveHandler(s)))
-	e.GET("/users/getReview", deliveryhttp.AdminOrUserMiddleware(getReviewHandler(s)))
+func RegisterUserRoutes(e *echo.Echo, s UserService, auth deliveryhttp.Auth, idempotent echo.MiddlewareFunc) {
+	e.POST("/users/setIsActive", auth.AdminOnlyMiddleware(idempotent(setIsActiveHandler(s))))
+	e.GET("/users/getReview", auth.AdminOrUserMiddleware(getReviewHandler(s)))
+	e.POST("/users/delete", auth.AdminOnlyMiddleware(idempotent(deleteUserHandler(s))))
+	e.POST("/users/restore", auth.AdminOnlyMiddleware(idempotent(restoreUserHandler(s))))
 }
 
 // setIsActiveHandler handles POST /users/setIsActive.
@@ -34,11 +38,7 @@ func setIsActiveHandler(s UserService) echo.HandlerFunc {
 		var req requestBody
 
 		if err := c.Bind(&req); err != nil {
-			return c.JSON(http.StatusBadRequest, dto.NewErrorResponse("BAD_REQUEST", "invalid JSON body"))
-		}
-
-		if req.UserID == "" {
-			return c.JSON(http.StatusBadRequest, dto.NewErrorResponse("BAD_REQUEST", "user_id is required"))
+			return deliveryhttp.BindError(c, err)
 		}
 
 		user, err := s.SetIsActive(c.Request().Context(), req.UserID, req.IsActive)
@@ -62,17 +62,18 @@ func getReviewHandler(s UserService) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		userID := c.QueryParam("user_id")
 
-		if userID == "" {
-			return c.JSON(http.StatusBadRequest, dto.NewErrorResponse("BAD_REQUEST", "user_id is required"))
+		includeArchived, err := includeArchivedParam(c)
+		if err != nil {
+			return deliveryhttp.BadRequest(c, err.Error())
 		}
 
-		pullRequests, err := s.ListReviewPRs(c.Request().Context(), userID)
+		pullRequests, err := s.ListReviewPRs(c.Request().Context(), userID, includeArchived)
 
 		if err != nil {
 			return deliveryhttp.HandleError(c, err)
 		}
 
-		var pullRequestShorts []dto.PullRequestShortDTO
+		pullRequestShorts := make([]dto.PullRequestShortDTO, 0, len(pullRequests))
 		for _, pullRequest := range pullRequests {
 			pullRequestShorts = append(pullRequestShorts, dto.PullRequestShortDomainToDTO(pullRequest))
 		}
@@ -83,3 +84,48 @@ func getReviewHandler(s UserService) echo.HandlerFunc {
 		})
 	}
 }
+
+type userIDRequestBody struct {
+	UserID string `json:"user_id"`
+}
+
+// deleteUserHandler handles POST /users/delete.
+func deleteUserHandler(s UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req userIDRequestBody
+
+		if err := c.Bind(&req); err != nil {
+			return deliveryhttp.BindError(c, err)
+		}
+
+		if err := s.DeleteUser(c.Request().Context(), req.UserID); err != nil {
+			return deliveryhttp.HandleError(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// restoreUserHandler handles POST /users/restore.
+func restoreUserHandler(s UserService) echo.HandlerFunc {
+	type responseBody struct {
+		User dto.UserDTO `json:"user"`
+	}
+
+	return func(c echo.Context) error {
+		var req userIDRequestBody
+
+		if err := c.Bind(&req); err != nil {
+			return deliveryhttp.BindError(c, err)
+		}
+
+		user, err := s.RestoreUser(c.Request().Context(), req.UserID)
+		if err != nil {
+			return deliveryhttp.HandleError(c, err)
+		}
+
+		return c.JSON(http.StatusOK, responseBody{
+			User: dto.UserDomainToDTO(user),
+		})
+	}
+}
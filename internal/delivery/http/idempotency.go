@@ -0,0 +1,140 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyFingerprintSep = "\n"
+	idempotencyScopeSep       = ":"
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, key string, fingerprint string) (domain.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, record domain.IdempotencyRecord) error
+	Release(ctx context.Context, key string) error
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// IdempotencyMiddleware replays the stored response, with its ETag, for
+// requests carrying an already completed Idempotency-Key. Responses with 5xx
+// status are not stored, so such requests can be retried with the same key.
+func IdempotencyMiddleware(s IdempotencyService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(idempotencyKeyHeader)
+			if key == "" {
+				return next(c)
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				return BadRequest(c, "idempotency key is too long")
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if IsBodyTooLarge(err) {
+				return BodyTooLarge(c)
+			}
+
+			if err != nil {
+				return BadRequest(c, "invalid request body")
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := c.Request().Context()
+			key = scopedKey(c, key)
+
+			fingerprint := requestFingerprint(c.Request(), body)
+
+			record, replay, err := s.Begin(ctx, key, fingerprint)
+			if err != nil {
+				return HandleError(c, err)
+			}
+
+			if replay {
+				c.Response().Header().Set(idempotentReplayedHeader, "true")
+
+				if record.ETag != "" {
+					c.Response().Header().Set(headerETag, record.ETag)
+				}
+
+				return c.Blob(record.StatusCode, record.ContentType, record.ResponseBody)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			err = next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			// The response is already sent, the key is finalized even if the client went away.
+			ctx = context.WithoutCancel(ctx)
+
+			status := c.Response().Status
+			if status >= http.StatusInternalServerError {
+				if releaseErr := s.Release(ctx, key); releaseErr != nil {
+					logging.FromContext(ctx).Error("error releasing idempotency key", logging.Error(releaseErr))
+				}
+
+				return nil
+			}
+
+			err = s.Complete(ctx, domain.IdempotencyRecord{
+				Key:          key,
+				Fingerprint:  fingerprint,
+				StatusCode:   status,
+				ContentType:  c.Response().Header().Get(echo.HeaderContentType),
+				ResponseBody: recorder.body.Bytes(),
+				ETag:         c.Response().Header().Get(headerETag),
+			})
+			if err != nil {
+				logging.FromContext(ctx).Error("error storing idempotent response", logging.Error(err))
+			}
+
+			return nil
+		}
+	}
+}
+
+// scopedKey keeps the keys of different callers apart by prefixing the key
+// with a hash of the token that authenticated the request.
+func scopedKey(c echo.Context, key string) string {
+	token, _ := c.Get(callerTokenKey).(string)
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:8]) + idempotencyScopeSep + key
+}
+
+// requestFingerprint covers the query and If-Match as well: a retry with
+// other options or another precondition is another request.
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + idempotencyFingerprintSep + r.URL.RequestURI() + idempotencyFingerprintSep))
+	h.Write([]byte(r.Header.Get(headerIfMatch) + idempotencyFingerprintSep))
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,265 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+// fakeIdempotencyService keeps the records in a map, like the repository
+// without expiry.
+type fakeIdempotencyService struct {
+	mu       sync.Mutex
+	records  map[string]domain.IdempotencyRecord
+	released []string
+}
+
+func newFakeIdempotencyService() *fakeIdempotencyService {
+	return &fakeIdempotencyService{records: make(map[string]domain.IdempotencyRecord)}
+}
+
+func (s *fakeIdempotencyService) Begin(
+	_ context.Context,
+	key string,
+	fingerprint string,
+) (domain.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	switch {
+	case !ok:
+		s.records[key] = domain.IdempotencyRecord{Key: key, Fingerprint: fingerprint}
+		return domain.IdempotencyRecord{}, false, nil
+	case record.Fingerprint != fingerprint:
+		return domain.IdempotencyRecord{}, false, domain.NewError(domain.ErrCodeIdempotencyKeyReused, "reused")
+	case !record.Completed():
+		return domain.IdempotencyRecord{}, false, domain.NewError(domain.ErrCodeRequestInProgress, "in progress")
+	default:
+		return record, true, nil
+	}
+}
+
+func (s *fakeIdempotencyService) Complete(_ context.Context, record domain.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[record.Key] = record
+
+	return nil
+}
+
+func (s *fakeIdempotencyService) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	s.released = append(s.released, key)
+
+	return nil
+}
+
+const (
+	testAdminToken = "admin-secret"
+	testUserToken  = "user-secret"
+)
+
+// newIdempotentServer serves POST /ok, answering with the number of handler
+// calls so far, also as the ETag, and POST /fail, answering 500.
+func newIdempotentServer(t *testing.T, s IdempotencyService) (*echo.Echo, *int) {
+	t.Helper()
+
+	calls := 0
+	auth := NewAuth(testAdminToken, testUserToken)
+	idempotent := IdempotencyMiddleware(s)
+
+	e := echo.New()
+	e.POST("/ok", auth.AdminOrUserMiddleware(idempotent(func(c echo.Context) error {
+		calls++
+		SetETag(c, int64(calls))
+
+		return c.JSON(http.StatusCreated, map[string]int{"calls": calls})
+	})))
+	e.POST("/fail", auth.AdminOrUserMiddleware(idempotent(func(c echo.Context) error {
+		calls++
+		return c.JSON(http.StatusInternalServerError, map[string]int{"calls": calls})
+	})))
+
+	return e, &calls
+}
+
+type request struct {
+	path  string
+	token string
+	key   string
+	body  string
+	// ifMatch is sent as If-Match when set.
+	ifMatch string
+}
+
+func (r request) send(e *echo.Echo) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, r.path, strings.NewReader(r.body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	if r.token == testUserToken {
+		req.Header.Set(userHeader, r.token)
+	} else {
+		req.Header.Set(adminHeader, r.token)
+	}
+
+	if r.key != "" {
+		req.Header.Set(idempotencyKeyHeader, r.key)
+	}
+
+	if r.ifMatch != "" {
+		req.Header.Set(headerIfMatch, r.ifMatch)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestIdempotencyMiddlewareReplaysCompletedResponse(t *testing.T) {
+	e, calls := newIdempotentServer(t, newFakeIdempotencyService())
+	req := request{path: "/ok", token: testAdminToken, key: "key-1", body: `{"a":1}`}
+
+	first := req.send(e)
+	second := req.send(e)
+
+	if *calls != 1 {
+		t.Fatalf("handler calls = %d, want 1", *calls)
+	}
+
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Fatalf("replay = %d %q, want %d %q", second.Code, second.Body, first.Code, first.Body)
+	}
+
+	if etag := second.Header().Get(headerETag); etag != `"1"` {
+		t.Fatalf("replayed ETag = %q, want %q", etag, `"1"`)
+	}
+
+	if second.Header().Get(idempotentReplayedHeader) != "true" {
+		t.Fatalf("replay is missing the %s header", idempotentReplayedHeader)
+	}
+
+	if first.Header().Get(idempotentReplayedHeader) != "" {
+		t.Fatalf("first response has the %s header", idempotentReplayedHeader)
+	}
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	tests := []struct {
+		name string
+		// first is sent before req when set.
+		first      *request
+		req        request
+		wantStatus int
+		wantCalls  int
+	}{
+		{
+			name:       "no key",
+			first:      &request{path: "/ok", token: testAdminToken, body: `{}`},
+			req:        request{path: "/ok", token: testAdminToken, body: `{}`},
+			wantStatus: http.StatusCreated,
+			wantCalls:  2,
+		},
+		{
+			name: "key too long",
+			req: request{
+				path:  "/ok",
+				token: testAdminToken,
+				key:   strings.Repeat("k", maxIdempotencyKeyLength+1),
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "different body",
+			first:      &request{path: "/ok", token: testAdminToken, key: "k", body: `{"a":1}`},
+			req:        request{path: "/ok", token: testAdminToken, key: "k", body: `{"a":2}`},
+			wantStatus: http.StatusConflict,
+			wantCalls:  1,
+		},
+		{
+			name:       "different path",
+			first:      &request{path: "/ok", token: testAdminToken, key: "k", body: `{}`},
+			req:        request{path: "/fail", token: testAdminToken, key: "k", body: `{}`},
+			wantStatus: http.StatusConflict,
+			wantCalls:  1,
+		},
+		{
+			name:       "different query",
+			first:      &request{path: "/ok?dry_run=true", token: testAdminToken, key: "k", body: `{}`},
+			req:        request{path: "/ok?dry_run=false", token: testAdminToken, key: "k", body: `{}`},
+			wantStatus: http.StatusConflict,
+			wantCalls:  1,
+		},
+		{
+			name:       "different If-Match",
+			first:      &request{path: "/ok", token: testAdminToken, key: "k", body: `{}`, ifMatch: `"1"`},
+			req:        request{path: "/ok", token: testAdminToken, key: "k", body: `{}`, ifMatch: `"2"`},
+			wantStatus: http.StatusConflict,
+			wantCalls:  1,
+		},
+		{
+			name:       "same key of another token",
+			first:      &request{path: "/ok", token: testAdminToken, key: "k", body: `{}`},
+			req:        request{path: "/ok", token: testUserToken, key: "k", body: `{}`},
+			wantStatus: http.StatusCreated,
+			wantCalls:  2,
+		},
+		{
+			name:       "retry after server error",
+			first:      &request{path: "/fail", token: testAdminToken, key: "k", body: `{}`},
+			req:        request{path: "/fail", token: testAdminToken, key: "k", body: `{}`},
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  2,
+		},
+		{
+			name:       "invalid token",
+			req:        request{path: "/ok", token: "junk", key: "k", body: `{}`},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, calls := newIdempotentServer(t, newFakeIdempotencyService())
+
+			if tt.first != nil {
+				tt.first.send(e)
+			}
+
+			rec := tt.req.send(e)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+
+			if *calls != tt.wantCalls {
+				t.Fatalf("handler calls = %d, want %d", *calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesKeyOnServerError(t *testing.T) {
+	s := newFakeIdempotencyService()
+	e, _ := newIdempotentServer(t, s)
+
+	request{path: "/fail", token: testAdminToken, key: "k", body: `{}`}.send(e)
+
+	if len(s.released) != 1 || !strings.HasSuffix(s.released[0], idempotencyScopeSep+"k") {
+		t.Fatalf("released = %v, want the scoped key k", s.released)
+	}
+
+	if len(s.records) != 0 {
+		t.Fatalf("records = %v, want none", s.records)
+	}
+}
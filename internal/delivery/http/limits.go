@@ -0,0 +1,147 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	errCodeRateLimited     = "RATE_LIMITED"
+	errCodePayloadTooLarge = "PAYLOAD_TOO_LARGE"
+)
+
+type RateLimiter interface {
+	Allow(key string) (bool, time.Duration)
+}
+
+// RateLimitMiddleware answers 429 with Retry-After to callers that ran out
+// of tokens. Routes keyed like "POST /pullRequest/create" in routeLimiters
+// use their own buckets instead of the default ones. Probes are not limited.
+func RateLimitMiddleware(limiter RateLimiter, routeLimiters map[string]RateLimiter, auth Auth) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if probeRoutes[c.Path()] {
+				return next(c)
+			}
+
+			l := limiter
+			if routeLimiter, ok := routeLimiters[c.Request().Method+" "+c.Path()]; ok {
+				l = routeLimiter
+			}
+
+			allowed, wait := l.Allow(callerKey(c, auth))
+			if !allowed {
+				c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+
+				return WriteError(c, http.StatusTooManyRequests, errCodeRateLimited, "too many requests")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// callerKey identifies the caller by its token once the token matches a
+// configured one, and by the client address otherwise, so that made-up
+// tokens do not get fresh buckets. Tokens are hashed to keep them out of
+// memory dumps.
+func callerKey(c echo.Context, auth Auth) string {
+	if token, ok := auth.callerToken(c); ok {
+		sum := sha256.Sum256([]byte(token))
+		return "token:" + hex.EncodeToString(sum[:8])
+	}
+
+	return "ip:" + c.RealIP()
+}
+
+// BodyLimitMiddleware answers 413 to requests with bodies over limit bytes,
+// or over the limit of their route in routeLimits. Bodies sent without
+// Content-Length fail when the handler reads past the limit.
+func BodyLimitMiddleware(limit int64, routeLimits map[string]int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			maxBytes := limit
+			if routeLimit, ok := routeLimits[req.Method+" "+c.Path()]; ok {
+				maxBytes = routeLimit
+			}
+
+			if req.ContentLength > maxBytes {
+				return BodyTooLarge(c)
+			}
+
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, maxBytes)
+
+			return next(c)
+		}
+	}
+}
+
+// IsBodyTooLarge reports whether err comes from reading past the body limit.
+func IsBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+
+	return errors.As(err, &maxBytesErr)
+}
+
+func BodyTooLarge(c echo.Context) error {
+	return WriteError(c, http.StatusRequestEntityTooLarge, errCodePayloadTooLarge, "request body is too large")
+}
+
+// BindError answers a request whose JSON body could not be bound.
+func BindError(c echo.Context, err error) error {
+	if IsBodyTooLarge(err) {
+		return BodyTooLarge(c)
+	}
+
+	message := err.Error()
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		message = fmt.Sprint(httpErr.Message)
+	}
+
+	return BadRequest(c, "invalid JSON body: "+strings.TrimPrefix(message, "json: "))
+}
+
+// StrictJSONSerializer is the echo JSON serializer that rejects unknown
+// fields, so that typos in requests fail instead of being ignored.
+type StrictJSONSerializer struct {
+	echo.DefaultJSONSerializer
+}
+
+func (StrictJSONSerializer) Deserialize(c echo.Context, i any) error {
+	decoder := json.NewDecoder(c.Request().Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(i)
+
+	var (
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+	)
+
+	switch {
+	case errors.As(err, &typeErr):
+		return echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("field %s must be %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value),
+		).SetInternal(err)
+	case errors.As(err, &syntaxErr):
+		return echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("syntax error at offset %d: %v", syntaxErr.Offset, syntaxErr),
+		).SetInternal(err)
+	}
+
+	return err
+}
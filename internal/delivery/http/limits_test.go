@@ -0,0 +1,179 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// fakeLimiter allows the first n requests of every key and records the keys.
+type fakeLimiter struct {
+	n    int
+	seen map[string]int
+}
+
+func newFakeLimiter(n int) *fakeLimiter {
+	return &fakeLimiter{n: n, seen: make(map[string]int)}
+}
+
+func (l *fakeLimiter) Allow(key string) (bool, time.Duration) {
+	l.seen[key]++
+
+	return l.seen[key] <= l.n, 1500 * time.Millisecond
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := newFakeLimiter(1)
+	createLimiter := newFakeLimiter(0)
+
+	e := echo.New()
+	e.Use(RateLimitMiddleware(limiter, map[string]RateLimiter{"POST /create": createLimiter},
+		NewAuth(testAdminToken, testUserToken),
+	))
+
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/list", ok)
+	e.POST("/create", ok)
+	e.GET("/livez", ok)
+
+	send := func(method, path, header, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+
+		if token != "" {
+			req.Header.Set(header, token)
+		}
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	if rec := send(http.MethodGet, "/list", userHeader, testUserToken); rec.Code != http.StatusOK {
+		t.Fatalf("got status %d for the first request, want 200", rec.Code)
+	}
+
+	rec := send(http.MethodGet, "/list", userHeader, testUserToken)
+	if rec.Code != http.StatusTooManyRequests || !strings.Contains(rec.Body.String(), errCodeRateLimited) {
+		t.Fatalf("got %d %s, want 429 %s", rec.Code, rec.Body.String(), errCodeRateLimited)
+	}
+
+	if got := rec.Header().Get(echo.HeaderRetryAfter); got != "2" {
+		t.Errorf("got Retry-After %q, want the wait rounded up to 2 seconds", got)
+	}
+
+	// Valid tokens get their own buckets, made-up ones share the bucket of the address.
+	if rec = send(http.MethodGet, "/list", adminHeader, testAdminToken); rec.Code != http.StatusOK {
+		t.Errorf("got status %d for the admin token, want its own bucket", rec.Code)
+	}
+
+	send(http.MethodGet, "/list", adminHeader, "guess-1")
+
+	if rec = send(http.MethodGet, "/list", userHeader, "guess-2"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("got status %d for a second made-up token, want 429 by address", rec.Code)
+	}
+
+	if len(limiter.seen) != 3 || limiter.seen["ip:10.0.0.1"] != 2 {
+		t.Errorf("got keys %v, want two token keys and the address used twice", limiter.seen)
+	}
+
+	for key := range limiter.seen {
+		if strings.Contains(key, testUserToken) || strings.Contains(key, testAdminToken) {
+			t.Errorf("got key %q with a raw token, want it hashed", key)
+		}
+	}
+
+	if rec = send(http.MethodPost, "/create", userHeader, testUserToken); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("got status %d for the route with its own limiter, want 429", rec.Code)
+	}
+
+	for range 3 {
+		if rec = send(http.MethodGet, "/livez", "", ""); rec.Code != http.StatusOK {
+			t.Errorf("got status %d for a probe, want probes not limited", rec.Code)
+		}
+	}
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	e := echo.New()
+	e.JSONSerializer = StrictJSONSerializer{}
+	e.Use(BodyLimitMiddleware(16, map[string]int64{"POST /import": 64}))
+
+	bind := func(c echo.Context) error {
+		var body struct {
+			Name string `json:"name"`
+		}
+
+		if err := c.Bind(&body); err != nil {
+			return BindError(c, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+	e.POST("/create", bind)
+	e.POST("/import", bind)
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		chunked  bool
+		wantCode int
+		wantBody string
+	}{
+		{name: "within limit", path: "/create", body: `{"name":"a"}`, wantCode: http.StatusOK},
+		{
+			name:     "over limit",
+			path:     "/create",
+			body:     `{"name":"a long name"}`,
+			wantCode: http.StatusRequestEntityTooLarge,
+			wantBody: errCodePayloadTooLarge,
+		},
+		{
+			name:     "over limit without content length",
+			path:     "/create",
+			body:     `{"name":"a long name"}`,
+			chunked:  true,
+			wantCode: http.StatusRequestEntityTooLarge,
+			wantBody: errCodePayloadTooLarge,
+		},
+		{name: "route limit", path: "/import", body: `{"name":"a long name"}`, wantCode: http.StatusOK},
+		{
+			name:     "unknown field",
+			path:     "/import",
+			body:     `{"nmae":"a"}`,
+			wantCode: http.StatusBadRequest,
+			wantBody: `invalid JSON body: unknown field \"nmae\"`,
+		},
+		{
+			name:     "wrong type",
+			path:     "/import",
+			body:     `{"name":1}`,
+			wantCode: http.StatusBadRequest,
+			wantBody: "field name must be string, got number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode || !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("got %d %s, want %d with %q", rec.Code, rec.Body.String(), tt.wantCode, tt.wantBody)
+			}
+		})
+	}
+}
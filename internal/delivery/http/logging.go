@@ -0,0 +1,90 @@
+package http
+
+import (
+	"crypto/rand"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+// internalErrorKey is where HandleError leaves the error behind a 5xx response.
+const internalErrorKey = "internal_error"
+
+// probeRoutes are polled by orchestrators and scrapers, their successful
+// requests are logged at the debug level only.
+var probeRoutes = map[string]bool{
+	"/livez":   true,
+	"/readyz":  true,
+	"/health":  true,
+	"/metrics": true,
+}
+
+// LoggingMiddleware assigns the request ID, puts a logger tagged with it
+// into the request context and logs every request when it completes.
+// 5xx responses caused by an error are logged at the error level with it.
+func LoggingMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if !logging.ValidRequestID(requestID) {
+				requestID = rand.Text()
+			}
+
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			logger := slog.Default().With(slog.String("request_id", requestID))
+			if traceID := tracing.TraceID(req.Context()); traceID != "" {
+				logger = logger.With(slog.String("trace_id", traceID))
+			}
+
+			ctx := logging.WithLogger(req.Context(), logger)
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("route", c.Path()),
+				slog.String("path", req.URL.Path),
+				slog.Int("status", status),
+				slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			}
+
+			if status < http.StatusInternalServerError {
+				level := slog.LevelInfo
+				if probeRoutes[c.Path()] {
+					level = slog.LevelDebug
+				}
+
+				logger.LogAttrs(ctx, level, "request completed", attrs...)
+
+				return nil
+			}
+
+			if internalErr, ok := c.Get(internalErrorKey).(error); ok {
+				err = internalErr
+			}
+
+			// A 5xx without an error behind it is a deliberate answer, e.g. from /readyz.
+			if err == nil {
+				logger.LogAttrs(ctx, slog.LevelWarn, "request failed", attrs...)
+				return nil
+			}
+
+			logger.LogAttrs(ctx, slog.LevelError, "request failed", append(attrs, logging.Error(err))...)
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// errorCodeKey is where HandleError leaves the error code for MetricsMiddleware.
+const errorCodeKey = "error_code"
+
+// unmatchedRoute labels requests to unknown paths, so that arbitrary URLs
+// do not create new series.
+const unmatchedRoute = "unmatched"
+
+// untimedRoutes are counted but kept out of the latency histogram: event
+// streams last minutes and would skew the 300 ms SLI, and /metrics is
+// scraped by Prometheus rather than called by clients.
+var untimedRoutes = map[string]bool{
+	"/events/stream": true,
+	"/metrics":       true,
+}
+
+type HTTPMetrics interface {
+	ObserveRequest(method, route string, status int)
+	ObserveDuration(method, route string, duration time.Duration)
+	ObserveError(code string)
+}
+
+// MetricsMiddleware records the status of every request by the route
+// template, the latency of all but untimedRoutes, and the error code of
+// failed ones.
+func MetricsMiddleware(m HTTPMetrics) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = unmatchedRoute
+			}
+
+			m.ObserveRequest(c.Request().Method, route, c.Response().Status)
+
+			if !untimedRoutes[route] {
+				m.ObserveDuration(c.Request().Method, route, time.Since(start))
+			}
+
+			if code, ok := c.Get(errorCodeKey).(string); ok {
+				m.ObserveError(code)
+			}
+
+			return nil
+		}
+	}
+}
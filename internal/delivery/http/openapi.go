@@ -0,0 +1,124 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/dto"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/openapi"
+)
+
+// maxValidatedResponseBytes bounds the response body kept for validation.
+// Larger responses, such as big exports, are not checked.
+const maxValidatedResponseBytes = 1 << 20
+
+// OpenAPIMiddleware answers 400 to requests whose parameters or JSON body
+// do not match the operation in doc, listing every violation with its
+// field path. Routes the document does not describe are passed through.
+// With validateResponses, JSON responses that do not match the document
+// are logged at the error level; clients still get them.
+func OpenAPIMiddleware(doc *openapi.Document, validateResponses bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			op := doc.Operation(req.Method, c.Path())
+			if op == nil {
+				return next(c)
+			}
+
+			var body []byte
+
+			if req.Body != nil && req.Body != http.NoBody {
+				var err error
+
+				body, err = io.ReadAll(req.Body)
+				if err != nil {
+					if IsBodyTooLarge(err) {
+						return BodyTooLarge(c)
+					}
+
+					return err
+				}
+
+				req.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			if violations := op.ValidateRequest(req.URL.Query(), req.Header, body); len(violations) > 0 {
+				details := make([]dto.Violation, len(violations))
+				for i, v := range violations {
+					details[i] = dto.Violation{Field: v.Field, Message: v.Message}
+				}
+
+				return BadRequest(c, "invalid request: "+joinViolations(violations), details...)
+			}
+
+			if !validateResponses {
+				return next(c)
+			}
+
+			recorder := &boundedRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			err := next(c)
+
+			if !recorder.truncated {
+				res := c.Response()
+				contentType := res.Header().Get(echo.HeaderContentType)
+
+				violations := op.ValidateResponse(res.Status, contentType, recorder.body.Bytes())
+				if len(violations) > 0 {
+					logging.FromContext(req.Context()).Error("response does not match openapi document",
+						slog.String("method", req.Method),
+						slog.String("route", c.Path()),
+						slog.Int("status", res.Status),
+						slog.String("violations", joinViolations(violations)),
+					)
+				}
+			}
+
+			return err
+		}
+	}
+}
+
+func joinViolations(violations []openapi.Violation) string {
+	messages := make([]string, len(violations))
+	for i, v := range violations {
+		messages[i] = v.String()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// boundedRecorder keeps a copy of the response body up to
+// maxValidatedResponseBytes.
+type boundedRecorder struct {
+	http.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (r *boundedRecorder) Write(p []byte) (int, error) {
+	if !r.truncated {
+		if r.body.Len()+len(p) > maxValidatedResponseBytes {
+			r.truncated = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(p)
+		}
+	}
+
+	return r.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach Flush of the underlying
+// writer, which the event stream needs.
+func (r *boundedRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/openapi"
+)
+
+const testOpenAPIDocument = `
+paths:
+  /items/create:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [id]
+              properties:
+                id: {type: string, minLength: 1}
+      responses:
+        '201':
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+`
+
+func TestOpenAPIMiddlewareRunsAfterAuth(t *testing.T) {
+	doc, err := openapi.Load([]byte(testOpenAPIDocument))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	auth := NewAuth(testAdminToken, testUserToken).Then(OpenAPIMiddleware(doc, false))
+
+	e := echo.New()
+	e.POST("/items/create", auth.AdminOnlyMiddleware(func(c echo.Context) error {
+		return c.JSON(http.StatusCreated, map[string]string{"id": "i1"})
+	}))
+
+	tests := []struct {
+		name     string
+		token    string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "invalid without token", body: `{"id":""}`, wantCode: http.StatusUnauthorized},
+		{
+			name:     "invalid with token",
+			token:    testAdminToken,
+			body:     `{"id":"","extra":1}`,
+			wantCode: http.StatusBadRequest,
+			wantBody: "invalid request: body.extra is not allowed; body.id must not be empty",
+		},
+		{name: "valid", token: testAdminToken, body: `{"id":"i1"}`, wantCode: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/items/create", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(adminHeader, tt.token)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode || !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("got %d %s, want %d with %q", rec.Code, rec.Body.String(), tt.wantCode, tt.wantBody)
+			}
+		})
+	}
+}
@@ -3,11 +3,37 @@ package server
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	deliveryhttp "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/delivery/http/handlers"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/openapi"
 )
 
+// Metrics is served at /metrics and observes every request.
+type Metrics interface {
+	deliveryhttp.HTTPMetrics
+	Handler() http.Handler
+}
+
+// Limits throttles callers and bounds request bodies. Route keys look like
+// "POST /pullRequest/create". A nil RateLimiter disables rate limiting.
+type Limits struct {
+	RateLimiter       deliveryhttp.RateLimiter
+	RouteRateLimiters map[string]deliveryhttp.RateLimiter
+	MaxBodyBytes      int64
+	RouteMaxBodyBytes map[string]int64
+}
+
+// APISpec is the OpenAPI document served at /openapi.yml. Requests are
+// validated against Document; responses only with ValidateResponses.
+type APISpec struct {
+	Raw               []byte
+	Document          *openapi.Document
+	ValidateResponses bool
+}
+
 type Server struct {
 	echo *echo.Echo
 }
@@ -16,24 +42,71 @@ func NewServer(
 	teamService handlers.TeamService,
 	userService handlers.UserService,
 	pullRequestService handlers.PullRequestService,
+	eventSubscriber handlers.EventSubscriber,
+	auth deliveryhttp.Auth,
+	idempotencyService deliveryhttp.IdempotencyService,
+	txStats handlers.TxStatsProvider,
+	poolStats handlers.PoolStatsProvider,
+	cacheStats handlers.CacheStatsProvider,
+	snapshotService handlers.SnapshotService,
+	policy handlers.PolicyProvider,
+	readiness handlers.ReadinessChecker,
+	metrics Metrics,
+	limits Limits,
+	spec APISpec,
+	eventsHeartbeatInterval time.Duration,
 ) *Server {
 	e := echo.New()
+	// The app logs the start itself, as JSON.
+	e.HideBanner = true
+	e.HidePort = true
+	e.JSONSerializer = deliveryhttp.StrictJSONSerializer{}
+
+	e.Use(deliveryhttp.TracingMiddleware())
+
+	// metrics is nil when disabled.
+	if metrics != nil {
+		e.Use(deliveryhttp.MetricsMiddleware(metrics))
+		e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+	}
+
+	// Inside the others, so that it sees the errors returned by handlers.
+	e.Use(deliveryhttp.LoggingMiddleware())
+
+	// Inside metrics and logging, so that rejected requests are counted and logged.
+	if limits.RateLimiter != nil {
+		e.Use(deliveryhttp.RateLimitMiddleware(limits.RateLimiter, limits.RouteRateLimiters, auth))
+	}
+
+	e.Use(deliveryhttp.BodyLimitMiddleware(limits.MaxBodyBytes, limits.RouteMaxBodyBytes))
+
+	// Validation runs after auth, so that callers without a valid token get
+	// 401 and learn nothing about the schema, and inside the body limit, as
+	// it reads the whole body.
+	auth = auth.Then(deliveryhttp.OpenAPIMiddleware(spec.Document, spec.ValidateResponses))
 
 	api := e.Group("")
 
-	handlers.RegisterTeamRoutes(api, teamService)
-	handlers.RegisterUserRoutes(e, userService)
-	handlers.RegisterPullRequestRoutes(e, pullRequestService)
+	idempotent := deliveryhttp.IdempotencyMiddleware(idempotencyService)
 
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
-	})
+	handlers.RegisterTeamRoutes(api, teamService, auth, idempotent)
+	handlers.RegisterUserRoutes(e, userService, auth, idempotent)
+	handlers.RegisterPullRequestRoutes(e, pullRequestService, auth, idempotent)
+	handlers.RegisterEventRoutes(e, eventSubscriber, auth, eventsHeartbeatInterval)
+	handlers.RegisterAdminRoutes(e, auth, txStats, poolStats, cacheStats, snapshotService, idempotent, policy)
+	handlers.RegisterHealthRoutes(e, readiness)
+	handlers.RegisterDocsRoutes(e, spec.Raw)
 
 	return &Server{
 		echo: e,
 	}
 }
 
+// Handler serves the API without a listener, e.g. in process.
+func (s *Server) Handler() http.Handler {
+	return s.echo
+}
+
 func (s *Server) Start(addr string) error {
 	return s.echo.Start(addr)
 }
@@ -1,3 +1,5 @@
 package domain
 
-const MaxAssignedReviewers = 2
+// MaxAssignedReviewers bounds the reviewers per pull request that an
+// assignment policy may ask for.
+const MaxAssignedReviewers = 5
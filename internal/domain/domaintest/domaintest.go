@@ -0,0 +1,97 @@
+// Package domaintest provides helpers for tests that check how the
+// transports handle the domain types.
+package domaintest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+// ErrorCodes parses the domain package and returns its ErrorCode constants
+// by name, so that a new code is found without keeping a list by hand.
+func ErrorCodes(t testing.TB) map[string]domain.ErrorCode {
+	t.Helper()
+
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("locate the domain package")
+	}
+
+	dir := filepath.Join(filepath.Dir(file), "..")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read domain package: %v", err)
+	}
+
+	codes := make(map[string]domain.ErrorCode)
+	fset := token.NewFileSet()
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+
+		for _, spec := range errorCodeSpecs(f) {
+			for i, ident := range spec.Names {
+				lit, ok := spec.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					t.Fatalf("%s must be a string literal", ident.Name)
+				}
+
+				code, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					t.Fatalf("unquote %s: %v", ident.Name, err)
+				}
+
+				codes[ident.Name] = domain.ErrorCode(code)
+			}
+		}
+	}
+
+	if len(codes) == 0 {
+		t.Fatal("found no ErrorCode constants in the domain package")
+	}
+
+	return codes
+}
+
+// errorCodeSpecs returns the constant specs of type ErrorCode in f.
+func errorCodeSpecs(f *ast.File) []*ast.ValueSpec {
+	var specs []*ast.ValueSpec
+
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			value, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+
+			if typ, ok := value.Type.(*ast.Ident); ok && typ.Name == "ErrorCode" && len(value.Values) > 0 {
+				specs = append(specs, value)
+			}
+		}
+	}
+
+	return specs
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "errors"
+
 type ErrorCode string
 
 const (
@@ -9,6 +11,12 @@ const (
 	ErrCodeNotAssigned ErrorCode = "NOT_ASSIGNED"
 	ErrCodeNoCandidate ErrorCode = "NO_CANDIDATE"
 	ErrCodeNotFound    ErrorCode = "NOT_FOUND"
+
+	ErrCodeIdempotencyKeyReused ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	ErrCodeRequestInProgress    ErrorCode = "REQUEST_IN_PROGRESS"
+
+	ErrCodePreconditionFailed ErrorCode = "PRECONDITION_FAILED"
+	ErrCodeInvalidArgument    ErrorCode = "INVALID_ARGUMENT"
 )
 
 type Error struct {
@@ -26,3 +34,9 @@ func NewError(code ErrorCode, message string) *Error {
 func (e *Error) Error() string {
 	return string(e.Code) + ": " + e.Message
 }
+
+// IsErrorCode reports whether err wraps a domain error with the given code.
+func IsErrorCode(err error, code ErrorCode) bool {
+	var domainError *Error
+	return errors.As(err, &domainError) && domainError.Code == code
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventReviewerAssigned    EventType = "REVIEWER_ASSIGNED"
+	EventReviewerReassigned  EventType = "REVIEWER_REASSIGNED"
+	EventPullRequestMerged   EventType = "PR_MERGED"
+	EventUserActivityChanged EventType = "USER_ACTIVITY_CHANGED"
+)
+
+// Event describes a committed change of assignments or user activity.
+// UserID is the subject of the event: the assigned reviewer, the new reviewer
+// on reassignment, the PR author on merge or the user whose activity changed.
+type Event struct {
+	Type          EventType
+	PullRequestID string
+	UserID        string
+	OldUserID     string
+	Reviewers     []string
+	TeamName      string
+	IsActive      bool
+	OccurredAt    time.Time
+}
+
+type EventFilter struct {
+	UserID   string
+	TeamName string
+}
+
+// Match reports whether the event concerns the user and the team of the filter.
+// Empty filter fields match any event.
+func (f EventFilter) Match(e Event) bool {
+	if f.TeamName != "" && f.TeamName != e.TeamName {
+		return false
+	}
+
+	if f.UserID != "" &&
+		f.UserID != e.UserID &&
+		f.UserID != e.OldUserID &&
+		!slices.Contains(e.Reviewers, f.UserID) {
+		return false
+	}
+
+	return true
+}
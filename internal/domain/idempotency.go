@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+type IdempotencyRecord struct {
+	Key          string
+	Fingerprint  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	// ETag is the ETag header of the response, empty if it had none.
+	ETag string
+	// ExpiresAt ends the short lease of a pending record and the retention
+	// of a completed one.
+	ExpiresAt time.Time
+}
+
+// Completed reports whether the response for the key has been stored.
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
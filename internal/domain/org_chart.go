@@ -0,0 +1,32 @@
+package domain
+
+// OrgChart lists teams with their full membership. Teams not in the chart are left as they are.
+type OrgChart struct {
+	Teams []TeamUpsert
+}
+
+// OrgChartUserChange describes a user touched by an org chart import.
+// FromTeam is set for moved users only.
+type OrgChartUserChange struct {
+	UserID   string
+	Username string
+	TeamName string
+	FromTeam string
+	IsActive bool
+}
+
+// OrgChartDiff is the difference between an org chart and the current state.
+type OrgChartDiff struct {
+	DryRun       bool
+	TeamsCreated []string
+	UsersCreated []OrgChartUserChange
+	// UsersMoved lists users that were members of another team.
+	UsersMoved []OrgChartUserChange
+	// UsersRestored lists soft-deleted users brought back by the chart.
+	UsersRestored []OrgChartUserChange
+	// UsersUpdated lists users that stay in their team but change username or activity.
+	UsersUpdated []OrgChartUserChange
+	// UsersDeactivated lists active members of the chart teams missing from the chart.
+	UsersDeactivated []OrgChartUserChange
+	Unchanged        int
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "fmt"
+
+type AssignmentStrategy string
+
+const (
+	// AssignmentStrategyRandom picks reviewers at random among the candidates.
+	AssignmentStrategyRandom AssignmentStrategy = "random"
+	// AssignmentStrategyLeastLoaded picks the candidates with the fewest open
+	// reviews first, at random among equally loaded ones.
+	AssignmentStrategyLeastLoaded AssignmentStrategy = "least_loaded"
+)
+
+// AssignmentPolicy controls how reviewers are picked for pull requests.
+type AssignmentPolicy struct {
+	ReviewersPerPR int
+	Strategy       AssignmentStrategy
+	// MaxOpenReviews caps the open reviews of a reviewer, 0 means no cap.
+	MaxOpenReviews int
+}
+
+// DefaultAssignmentPolicy is the policy used when none is configured.
+func DefaultAssignmentPolicy() AssignmentPolicy {
+	return AssignmentPolicy{
+		ReviewersPerPR: 2,
+		Strategy:       AssignmentStrategyRandom,
+		MaxOpenReviews: 0,
+	}
+}
+
+// Problems lists everything wrong with the policy, nil if it is valid.
+func (p AssignmentPolicy) Problems() []string {
+	var problems []string
+
+	if p.ReviewersPerPR < 1 || p.ReviewersPerPR > MaxAssignedReviewers {
+		problems = append(problems, fmt.Sprintf(
+			"reviewers_per_pr must be between 1 and %d, got %d", MaxAssignedReviewers, p.ReviewersPerPR,
+		))
+	}
+
+	if p.Strategy != AssignmentStrategyRandom && p.Strategy != AssignmentStrategyLeastLoaded {
+		problems = append(problems, fmt.Sprintf(
+			"strategy must be one of %s, %s, got %q",
+			AssignmentStrategyRandom,
+			AssignmentStrategyLeastLoaded,
+			p.Strategy,
+		))
+	}
+
+	if p.MaxOpenReviews < 0 {
+		problems = append(problems, fmt.Sprintf("max_open_reviews must not be negative, got %d", p.MaxOpenReviews))
+	}
+
+	return problems
+}
@@ -17,6 +17,9 @@ type PullRequest struct {
 	AssignedReviewers []string
 	CreatedAt         time.Time
 	MergedAt          *time.Time
+	Version           int64
+	ArchivedAt        *time.Time
+	DeletedAt         *time.Time
 }
 
 type PullRequestShort struct {
@@ -25,3 +28,18 @@ type PullRequestShort struct {
 	AuthorID string
 	Status   PullRequestStatus
 }
+
+// PullRequestStats counts pull requests in the hot and archive tables.
+// Merged includes the archived pull requests.
+type PullRequestStats struct {
+	Open      int
+	Merged    int
+	Archived  int
+	Reviewers []ReviewerStats
+}
+
+// ReviewerStats is the number of pull requests a user was assigned to review.
+type ReviewerStats struct {
+	UserID      string
+	Assignments int
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// SnapshotVersion is the version of the snapshot format written by export.
+const SnapshotVersion = 1
+
+type SnapshotTeam struct {
+	Name      string
+	DeletedAt *time.Time
+}
+
+// Snapshot is the full state of teams, users and pull requests
+// with their reviewers, including soft-deleted ones. Archived
+// pull requests are not part of it.
+type Snapshot struct {
+	Version      int
+	ExportedAt   time.Time
+	Teams        []SnapshotTeam
+	Users        []User
+	PullRequests []PullRequest
+}
+
+type ImportMode string
+
+const (
+	// ImportModeMerge inserts new records and overwrites existing ones.
+	ImportModeMerge ImportMode = "merge"
+	// ImportModeReplace removes all teams, users and pull requests first.
+	ImportModeReplace ImportMode = "replace"
+)
+
+type ImportCounts struct {
+	Created int
+	Updated int
+}
+
+// ImportReport describes what an import did or, for a dry run, would do.
+// Nothing is written when Errors is not empty.
+type ImportReport struct {
+	Mode         ImportMode
+	DryRun       bool
+	Teams        ImportCounts
+	Users        ImportCounts
+	PullRequests ImportCounts
+	Errors       []string
+}
@@ -1,8 +1,11 @@
 package domain
 
+import "time"
+
 type User struct {
-	ID       string
-	Username string
-	TeamName string
-	IsActive bool
+	ID        string
+	Username  string
+	TeamName  string
+	IsActive  bool
+	DeletedAt *time.Time
 }
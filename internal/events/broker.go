@@ -0,0 +1,103 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type subscriber struct {
+	filter domain.EventFilter
+	ch     chan domain.Event
+}
+
+// Broker fans events out to in-process subscribers.
+// Slow subscribers never block publishers: events that do not fit
+// into the subscriber buffer are dropped for that subscriber.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+	bufferSize  int
+	closed      bool
+}
+
+func NewBroker(bufferSize int) *Broker {
+	return &Broker{
+		subscribers: make(map[*subscriber]struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+func (b *Broker) Publish(_ context.Context, events ...domain.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+
+	for _, e := range events {
+		for sub := range b.subscribers {
+			if !sub.filter.Match(e) {
+				continue
+			}
+
+			select {
+			case sub.ch <- e:
+			default:
+			}
+		}
+	}
+}
+
+// Subscribe returns a channel of events matching the filter and a function
+// that cancels the subscription. The channel is closed on cancel or on Close.
+func (b *Broker) Subscribe(filter domain.EventFilter) (<-chan domain.Event, func()) {
+	sub := &subscriber{
+		filter: filter,
+		ch:     make(chan domain.Event, b.bufferSize),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+
+	b.subscribers[sub] = struct{}{}
+
+	var once sync.Once
+
+	return sub.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			if _, ok := b.subscribers[sub]; ok {
+				delete(b.subscribers, sub)
+				close(sub.ch)
+			}
+		})
+	}
+}
+
+// Close closes all subscriber channels so long-lived streams can finish
+// before the HTTP server shuts down.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.closed = true
+
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	}
+}
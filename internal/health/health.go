@@ -0,0 +1,86 @@
+// Package health runs the readiness checks of the service dependencies.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Check is a named probe of a dependency, it fails by returning an error.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+}
+
+// Report is the outcome of a readiness probe. Checks is empty while draining.
+type Report struct {
+	Ready    bool
+	Draining bool
+	Checks   []CheckResult
+}
+
+// Checker runs all checks concurrently, each under its own timeout.
+// Once draining, it reports not ready without running them.
+type Checker struct {
+	checks   []Check
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// StartDraining makes every following probe report not ready.
+func (c *Checker) StartDraining() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Check(ctx context.Context) Report {
+	if c.draining.Load() {
+		return Report{Draining: true}
+	}
+
+	results := make([]CheckResult, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Go(func() {
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Run(checkCtx)
+
+			results[i] = CheckResult{
+				Name:     check.Name,
+				Err:      err,
+				Duration: time.Since(start),
+			}
+		})
+	}
+	wg.Wait()
+
+	ready := true
+	for _, result := range results {
+		if result.Err != nil {
+			ready = false
+		}
+	}
+
+	return Report{
+		Ready:  ready,
+		Checks: results,
+	}
+}
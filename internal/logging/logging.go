@@ -0,0 +1,91 @@
+// Package logging configures the JSON logger of the service and carries
+// the per-request logger in a context.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+)
+
+type loggerKey struct{}
+
+// maxRequestIDLength bounds request IDs taken from clients.
+const maxRequestIDLength = 128
+
+// Setup makes a JSON logger on stderr the default one. Lines written with
+// the standard log package go through it as well.
+func Setup(cfg *config.LogConfig) *slog.Logger {
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: cfg.Level}))
+	slog.SetDefault(logger)
+
+	return logger
+}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger of ctx, or the default one when there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
+
+// Error returns err as a group with the message of the whole wrapped chain
+// and the innermost error with its type, e.g. *pgconn.PgError.
+func Error(err error) slog.Attr {
+	cause := rootCause(err)
+
+	return slog.Group("error",
+		slog.String("message", err.Error()),
+		slog.String("cause", cause.Error()),
+		slog.String("cause_type", fmt.Sprintf("%T", cause)),
+	)
+}
+
+// rootCause unwraps err down to the error it was built from. Of joined
+// errors the first one is followed.
+func rootCause(err error) error {
+	for {
+		var next error
+
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() error }:
+			next = wrapped.Unwrap()
+		case interface{ Unwrap() []error }:
+			if inner := wrapped.Unwrap(); len(inner) > 0 {
+				next = inner[0]
+			}
+		}
+
+		if next == nil {
+			return err
+		}
+
+		err = next
+	}
+}
+
+// ValidRequestID accepts IDs of reasonable length made of printable ASCII,
+// so that a client cannot break the log lines.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
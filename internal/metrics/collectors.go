@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+)
+
+// collectTimeout bounds the queries made during a scrape.
+const collectTimeout = 5 * time.Second
+
+type OpenPullRequestCounter interface {
+	CountOpenByTeam(ctx context.Context) (map[string]int, error)
+}
+
+type PoolStatsProvider interface {
+	PoolStats() []store.PoolStats
+}
+
+// CollectOpenPullRequests adds the open pull request gauge, queried on every scrape.
+func (m *Metrics) CollectOpenPullRequests(counter OpenPullRequestCounter) {
+	m.registry.MustRegister(&openPullRequestsCollector{
+		counter: counter,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "open_pull_requests"),
+			"Open pull requests by the team of their author.",
+			[]string{"team"},
+			nil,
+		),
+	})
+}
+
+// CollectPools adds the connection pool stats; there are none without postgres.
+func (m *Metrics) CollectPools(provider PoolStatsProvider) {
+	m.registry.MustRegister(newPoolCollector(provider))
+}
+
+type openPullRequestsCollector struct {
+	counter OpenPullRequestCounter
+	desc    *prometheus.Desc
+}
+
+func (c *openPullRequestsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *openPullRequestsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	counts, err := c.counter.CountOpenByTeam(ctx)
+	if err != nil {
+		slog.Error("error collecting open pull requests", logging.Error(err))
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+
+		return
+	}
+
+	for teamName, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), teamName)
+	}
+}
+
+type poolCollector struct {
+	provider PoolStatsProvider
+
+	maxConns        *prometheus.Desc
+	totalConns      *prometheus.Desc
+	idleConns       *prometheus.Desc
+	acquiredConns   *prometheus.Desc
+	acquires        *prometheus.Desc
+	emptyAcquires   *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	healthy         *prometheus.Desc
+	replicationLag  *prometheus.Desc
+}
+
+func newPoolCollector(provider PoolStatsProvider) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, []string{"pool"}, nil)
+	}
+
+	return &poolCollector{
+		provider:        provider,
+		maxConns:        desc("max_conns", "Maximum size of the pool."),
+		totalConns:      desc("total_conns", "Open connections."),
+		idleConns:       desc("idle_conns", "Idle connections."),
+		acquiredConns:   desc("acquired_conns", "Connections in use."),
+		acquires:        desc("acquires_total", "Successful acquires."),
+		emptyAcquires:   desc("empty_acquires_total", "Acquires that waited for a connection."),
+		acquireDuration: desc("acquire_duration_seconds_total", "Time spent acquiring connections."),
+		healthy:         desc("healthy", "1 if the pool is used for reads."),
+		replicationLag:  desc("replication_lag_seconds", "Replication lag of the replica, 0 for the primary."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxConns
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.acquiredConns
+	ch <- c.acquires
+	ch <- c.emptyAcquires
+	ch <- c.acquireDuration
+	ch <- c.healthy
+	ch <- c.replicationLag
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stat := range c.provider.PoolStats() {
+		healthy := 0.0
+		if stat.Healthy {
+			healthy = 1
+		}
+
+		metrics := []struct {
+			desc      *prometheus.Desc
+			valueType prometheus.ValueType
+			value     float64
+		}{
+			{c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns)},
+			{c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns)},
+			{c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns)},
+			{c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns)},
+			{c.acquires, prometheus.CounterValue, float64(stat.AcquireCount)},
+			{c.emptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount)},
+			{c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration.Seconds()},
+			{c.healthy, prometheus.GaugeValue, healthy},
+			{c.replicationLag, prometheus.GaugeValue, stat.Lag.Seconds()},
+		}
+
+		for _, metric := range metrics {
+			ch <- prometheus.MustNewConstMetric(metric.desc, metric.valueType, metric.value, stat.Name)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...domain.Event)
+}
+
+type countingPublisher struct {
+	next    EventPublisher
+	metrics *Metrics
+}
+
+// CountingPublisher counts assignments, reassignments and merges from the
+// events published by the services of this instance.
+func (m *Metrics) CountingPublisher(next EventPublisher) EventPublisher {
+	return &countingPublisher{next: next, metrics: m}
+}
+
+func (p *countingPublisher) Publish(ctx context.Context, events ...domain.Event) {
+	for _, event := range events {
+		switch event.Type {
+		case domain.EventReviewerAssigned:
+			p.metrics.assignments.Inc()
+		case domain.EventReviewerReassigned:
+			p.metrics.reassignments.Inc()
+		case domain.EventPullRequestMerged:
+			p.metrics.merges.Inc()
+		}
+	}
+
+	p.next.Publish(ctx, events...)
+}
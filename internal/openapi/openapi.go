@@ -0,0 +1,206 @@
+// Package openapi checks requests and responses against the OpenAPI
+// document of the service. It covers the part of OpenAPI 3.0 the document
+// uses: local $ref, type, nullable, enum, required, properties,
+// additionalProperties, items, length, range and item count limits, and
+// the date-time format. Parameters are checked in the query and headers.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const mediaTypeJSON = "application/json"
+
+// Violation is a value that does not match the document. Field is a path
+// like body.members[0].user_id or query.team_name.
+type Violation struct {
+	Field   string
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Field + " " + v.Message
+}
+
+// Document holds the operations of the OpenAPI document keyed by method
+// and path, e.g. "POST /pullRequest/create".
+type Document struct {
+	operations map[string]*Operation
+}
+
+type Operation struct {
+	params []parameter
+	body   *requestBody
+	// responses holds the JSON schema of each documented status, nil for
+	// statuses without a JSON body.
+	responses map[int]*Schema
+}
+
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	schema   *Schema
+}
+
+type requestBody struct {
+	required bool
+	// json is nil when the body is not JSON, e.g. CSV.
+	json *Schema
+}
+
+// Load parses the OpenAPI document and compiles its schemas.
+func Load(data []byte) (*Document, error) {
+	var root map[string]any
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("error parsing openapi document: %w", err)
+	}
+
+	c := &compiler{root: root, refs: map[string]*Schema{}}
+	doc := &Document{operations: map[string]*Operation{}}
+
+	paths, _ := root["paths"].(map[string]any)
+	for path, rawItem := range paths {
+		item, _ := rawItem.(map[string]any)
+
+		for method, rawOp := range item {
+			op, err := c.operation(rawOp)
+			if err != nil {
+				return nil, fmt.Errorf("error compiling %s %s: %w", strings.ToUpper(method), path, err)
+			}
+
+			doc.operations[strings.ToUpper(method)+" "+path] = op
+		}
+	}
+
+	return doc, nil
+}
+
+// Operation returns the operation for the method and route path, nil if
+// the document does not describe it.
+func (d *Document) Operation(method, path string) *Operation {
+	return d.operations[method+" "+path]
+}
+
+// ValidateRequest checks the parameters and, if it is JSON, the body.
+func (o *Operation) ValidateRequest(query url.Values, header http.Header, body []byte) []Violation {
+	var violations []Violation
+
+	for _, p := range o.params {
+		var value string
+
+		switch p.in {
+		case "query":
+			value = query.Get(p.name)
+		case "header":
+			value = header.Get(p.name)
+		default:
+			continue
+		}
+
+		field := p.in + "." + p.name
+
+		// Like the handlers, an empty value counts as a missing one.
+		if value == "" {
+			if p.required {
+				violations = append(violations, Violation{Field: field, Message: "is required"})
+			}
+
+			continue
+		}
+
+		if p.schema != nil {
+			violations = p.schema.validate(field, parameterValue(p.schema, value), violations)
+		}
+	}
+
+	if o.body == nil || o.body.json == nil {
+		return violations
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if o.body.required {
+			violations = append(violations, Violation{Field: "body", Message: "is required"})
+		}
+
+		return violations
+	}
+
+	if !isJSON(header.Get("Content-Type")) {
+		// The handler rejects media types it does not bind.
+		return violations
+	}
+
+	value, err := decode(body)
+	if err != nil {
+		return append(violations, Violation{Field: "body", Message: "must be valid JSON"})
+	}
+
+	return o.body.json.validate("body", value, violations)
+}
+
+// ValidateResponse checks a JSON response body against the schema of its
+// status. Statuses the document does not describe are not checked.
+func (o *Operation) ValidateResponse(status int, contentType string, body []byte) []Violation {
+	schema := o.responses[status]
+	if schema == nil || !isJSON(contentType) {
+		return nil
+	}
+
+	value, err := decode(body)
+	if err != nil {
+		return []Violation{{Field: "response", Message: "must be valid JSON"}}
+	}
+
+	return schema.validateResponse("response", value, nil)
+}
+
+// parameterValue converts a raw parameter to the JSON type of its schema,
+// leaving it a string if it does not parse, so that the type check fails.
+func parameterValue(s *Schema, raw string) any {
+	switch s.Type {
+	case "boolean":
+		if v, err := strconv.ParseBool(raw); err == nil {
+			return v
+		}
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err == nil {
+			return json.Number(raw)
+		}
+	}
+
+	return raw
+}
+
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+
+	return value, nil
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	return err == nil && mediaType == mediaTypeJSON
+}
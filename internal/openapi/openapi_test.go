@@ -0,0 +1,377 @@
+package openapi_test
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/docs"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/openapi"
+)
+
+const testDocument = `
+openapi: 3.0.3
+paths:
+  /items/create:
+    post:
+      parameters:
+        - $ref: '#/components/parameters/RequestID'
+      requestBody:
+        $ref: '#/components/requestBodies/Item'
+      responses:
+        '201':
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+        '204':
+          description: no body
+  /items/list:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, minimum: 1, maximum: 100}}
+        - {name: active, in: query, schema: {type: boolean}}
+        - {name: owner, in: query, required: true, schema: {type: string}}
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Item'}
+components:
+  parameters:
+    RequestID: {name: x-request-id, in: header, required: true, schema: {type: string, maxLength: 8}}
+  requestBodies:
+    Item:
+      required: true
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Item'}
+  schemas:
+    Item:
+      type: object
+      required: [id, kind]
+      properties:
+        id: {type: string, minLength: 1, maxLength: 5}
+        kind: {type: string, enum: [bug, feature]}
+        priority: {type: integer, minimum: 1, maximum: 3}
+        weight: {type: number, maximum: 1.5}
+        due_at: {type: string, format: date-time, nullable: true}
+        tags: {type: array, minItems: 1, maxItems: 2, items: {type: string}}
+        labels:
+          type: object
+          additionalProperties: {type: string}
+        extra:
+          type: object
+          additionalProperties: true
+        closed:
+          type: object
+          additionalProperties: false
+          properties:
+            note: {type: string}
+        parent: {$ref: '#/components/schemas/Item'}
+`
+
+func loadTestDocument(t *testing.T) *openapi.Document {
+	t.Helper()
+
+	doc, err := openapi.Load([]byte(testDocument))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	return doc
+}
+
+func messages(violations []openapi.Violation) []string {
+	res := make([]string, len(violations))
+	for i, v := range violations {
+		res[i] = v.String()
+	}
+
+	return res
+}
+
+func TestValidateRequestBody(t *testing.T) {
+	op := loadTestDocument(t).Operation(http.MethodPost, "/items/create")
+	if op == nil {
+		t.Fatal("got no operation for POST /items/create")
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	header.Set("X-Request-Id", "r1")
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "valid",
+			body: `{"id":"i1","kind":"bug","priority":2,"weight":1.5,"due_at":"2025-01-02T03:04:05Z",` +
+				`"tags":["a"],"labels":{"team":"x"},"extra":{"any":[1]},"closed":{"note":"n"}}`,
+		},
+		{
+			name: "missing required",
+			body: `{}`,
+			want: []string{"body.id is required", "body.kind is required"},
+		},
+		{
+			name: "wrong types",
+			body: `{"id":1,"kind":"bug","priority":"2","tags":"a"}`,
+			want: []string{
+				"body.id must be a string",
+				"body.priority must be an integer",
+				"body.tags must be an array",
+			},
+		},
+		{
+			name: "integer with fraction",
+			body: `{"id":"i1","kind":"bug","priority":1.5}`,
+			want: []string{"body.priority must be an integer"},
+		},
+		{
+			name: "enum",
+			body: `{"id":"i1","kind":"task"}`,
+			want: []string{"body.kind must be one of bug, feature"},
+		},
+		{
+			name: "length limits",
+			body: `{"id":"","kind":"bug","parent":{"id":"toolong","kind":"bug"}}`,
+			want: []string{"body.id must not be empty", "body.parent.id must be at most 5 characters long"},
+		},
+		{
+			name: "range limits",
+			body: `{"id":"i1","kind":"bug","priority":0,"weight":2}`,
+			want: []string{"body.priority must be at least 1", "body.weight must be at most 1.5"},
+		},
+		{
+			name: "item count limits",
+			body: `{"id":"i1","kind":"bug","tags":[]}`,
+			want: []string{"body.tags must have at least 1 items"},
+		},
+		{
+			name: "array items",
+			body: `{"id":"i1","kind":"bug","tags":["a",2,"c"]}`,
+			want: []string{"body.tags must have at most 2 items"},
+		},
+		{
+			name: "array item types",
+			body: `{"id":"i1","kind":"bug","tags":["a",2]}`,
+			want: []string{"body.tags[1] must be a string"},
+		},
+		{
+			name: "nullable",
+			body: `{"id":"i1","kind":"bug","due_at":null,"priority":null}`,
+			want: []string{"body.priority must not be null"},
+		},
+		{
+			name: "date-time",
+			body: `{"id":"i1","kind":"bug","due_at":"2025-01-02 03:04"}`,
+			want: []string{"body.due_at must be an RFC 3339 date-time"},
+		},
+		{
+			name: "additional properties",
+			body: `{"id":"i1","kind":"bug","color":"red","labels":{"team":1},"closed":{"note":"n","by":"u1"}}`,
+			want: []string{
+				"body.closed.by is not allowed",
+				"body.color is not allowed",
+				"body.labels.team must be a string",
+			},
+		},
+		{
+			name: "recursive reference",
+			body: `{"id":"i1","kind":"bug","parent":{"id":"i0","kind":"bug","parent":{"kind":"bug"}}}`,
+			want: []string{"body.parent.parent.id is required"},
+		},
+		{
+			name: "missing body",
+			body: "  ",
+			want: []string{"body is required"},
+		},
+		{
+			name: "invalid json",
+			body: `{"id":`,
+			want: []string{"body must be valid JSON"},
+		},
+		{
+			name: "trailing data",
+			body: `{"id":"i1","kind":"bug"} {}`,
+			want: []string{"body must be valid JSON"},
+		},
+		{
+			name: "not an object",
+			body: `[]`,
+			want: []string{"body must be an object"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := messages(op.ValidateRequest(url.Values{}, header, []byte(tt.body)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got violations %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRequestParameters(t *testing.T) {
+	doc := loadTestDocument(t)
+	list := doc.Operation(http.MethodGet, "/items/list")
+	create := doc.Operation(http.MethodPost, "/items/create")
+
+	tests := []struct {
+		name   string
+		op     *openapi.Operation
+		query  string
+		header map[string]string
+		want   []string
+	}{
+		{name: "valid", op: list, query: "owner=u1&limit=10&active=true"},
+		{
+			name:  "coerced out of range",
+			op:    list,
+			query: "owner=u1&limit=101",
+			want:  []string{"query.limit must be at most 100"},
+		},
+		{
+			name:  "not coercible",
+			op:    list,
+			query: "owner=u1&limit=ten&active=yes",
+			want:  []string{"query.limit must be an integer", "query.active must be a boolean"},
+		},
+		{name: "empty counts as missing", op: list, query: "owner=", want: []string{"query.owner is required"}},
+		{name: "empty optional", op: list, query: "owner=u1&limit="},
+		{
+			name:   "header by reference",
+			op:     create,
+			header: map[string]string{"Content-Type": "text/csv", "X-Request-ID": "too-long-id"},
+			want:   []string{"header.X-Request-Id must be at most 8 characters long"},
+		},
+		{
+			name:   "missing header",
+			op:     create,
+			header: map[string]string{"Content-Type": "text/csv"},
+			want:   []string{"header.X-Request-Id is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parse query: %v", err)
+			}
+
+			header := http.Header{}
+			for key, value := range tt.header {
+				header.Set(key, value)
+			}
+
+			// Bodies of other media types are left to the handlers.
+			got := messages(tt.op.ValidateRequest(query, header, []byte("id,kind")))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got violations %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	doc := loadTestDocument(t)
+	create := doc.Operation(http.MethodPost, "/items/create")
+	list := doc.Operation(http.MethodGet, "/items/list")
+
+	tests := []struct {
+		name        string
+		op          *openapi.Operation
+		status      int
+		contentType string
+		body        string
+		want        []string
+	}{
+		{
+			name:        "new fields are allowed",
+			op:          create,
+			status:      201,
+			contentType: "application/json",
+			body:        `{"id":"i1","kind":"bug","added_later":true}`,
+		},
+		{
+			name:        "array of items",
+			op:          list,
+			status:      200,
+			contentType: "application/json",
+			body:        `[{"id":"i1","kind":"bug"},{"id":"i2"}]`,
+			want:        []string{"response[1].kind is required"},
+		},
+		{name: "status without schema", op: create, status: 204, contentType: "application/json", body: "oops"},
+		{name: "undocumented status", op: create, status: 500, contentType: "application/json", body: "oops"},
+		{name: "not json", op: create, status: 201, contentType: "text/plain", body: "oops"},
+		{
+			name:        "invalid json",
+			op:          create,
+			status:      201,
+			contentType: "application/json",
+			body:        "oops",
+			want:        []string{"response must be valid JSON"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := messages(tt.op.ValidateResponse(tt.status, tt.contentType, []byte(tt.body)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got violations %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if _, err := openapi.Load(docs.OpenAPI); err != nil {
+		t.Errorf("load the service document: %v", err)
+	}
+
+	if op := loadTestDocument(t).Operation(http.MethodGet, "/items/create"); op != nil {
+		t.Error("got an operation for an undocumented method, want nil")
+	}
+
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{
+			name:    "unresolved reference",
+			doc:     "paths:\n  /a:\n    post:\n      requestBody: {$ref: '#/components/requestBodies/Missing'}\n",
+			wantErr: `unresolved reference "#/components/requestBodies/Missing"`,
+		},
+		{
+			name:    "remote reference",
+			doc:     "paths:\n  /a:\n    get:\n      parameters: [{$ref: 'other.yml#/p'}]\n",
+			wantErr: `unsupported reference "other.yml#/p"`,
+		},
+		{
+			name: "bad limit",
+			doc: "paths:\n  /a:\n    get:\n      parameters:\n" +
+				"        - {name: q, in: query, schema: {type: string, maxLength: long}}\n",
+			wantErr: "maxLength must be an integer",
+		},
+		{name: "not yaml", doc: "paths: [", wantErr: "error parsing openapi document"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := openapi.Load([]byte(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,507 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Schema is a compiled JSON schema. Nil limits are not checked.
+type Schema struct {
+	Type     string
+	Format   string
+	Nullable bool
+	Enum     []any
+
+	Properties map[string]*Schema
+	Required   []string
+	// AdditionalProperties is the schema of properties not listed in
+	// Properties. additionalSet tells whether the document declares it.
+	AdditionalProperties *Schema
+	additionalSet        bool
+	additionalForbidden  bool
+
+	Items    *Schema
+	MinItems *int
+	MaxItems *int
+
+	MinLength *int
+	MaxLength *int
+	Minimum   *big.Float
+	Maximum   *big.Float
+}
+
+// validate appends the violations of a request value to violations.
+// Request objects are closed like the strict JSON binding: properties the
+// document does not list are rejected unless additionalProperties allows
+// them.
+func (s *Schema) validate(field string, value any, violations []Violation) []Violation {
+	return s.check(field, value, true, violations)
+}
+
+// validateResponse is validate with open objects, so that adding a field
+// to a response does not break older documents.
+func (s *Schema) validateResponse(field string, value any, violations []Violation) []Violation {
+	return s.check(field, value, false, violations)
+}
+
+func (s *Schema) check(field string, value any, strict bool, violations []Violation) []Violation {
+	fail := func(format string, args ...any) []Violation {
+		return append(violations, Violation{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if value == nil {
+		if s.Nullable || s.Type == "" {
+			return violations
+		}
+
+		return fail("must not be null")
+	}
+
+	if !s.hasType(value) {
+		return fail("must be %s", article(s.Type))
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return equal(e, value) }) {
+		return fail("must be one of %s", enumList(s.Enum))
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			if *s.MinLength == 1 {
+				return fail("must not be empty")
+			}
+
+			return fail("must be at least %d characters long", *s.MinLength)
+		}
+
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fail("must be at most %d characters long", *s.MaxLength)
+		}
+
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				return fail("must be an RFC 3339 date-time")
+			}
+		}
+	case json.Number:
+		n, _, err := big.ParseFloat(v.String(), 10, 64, big.ToNearestEven)
+		if err != nil {
+			return fail("must be a number")
+		}
+
+		if s.Minimum != nil && n.Cmp(s.Minimum) < 0 {
+			return fail("must be at least %s", s.Minimum.String())
+		}
+
+		if s.Maximum != nil && n.Cmp(s.Maximum) > 0 {
+			return fail("must be at most %s", s.Maximum.String())
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			return fail("must have at least %d items", *s.MinItems)
+		}
+
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			return fail("must have at most %d items", *s.MaxItems)
+		}
+
+		if s.Items != nil {
+			for i, item := range v {
+				violations = s.Items.check(fmt.Sprintf("%s[%d]", field, i), item, strict, violations)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				violations = append(violations, Violation{Field: field + "." + name, Message: "is required"})
+			}
+		}
+
+		for _, name := range sortedKeys(v) {
+			property, ok := s.Properties[name]
+
+			switch {
+			case ok:
+			case s.AdditionalProperties != nil:
+				property = s.AdditionalProperties
+			case s.additionalForbidden || (strict && !s.additionalSet):
+				violations = append(violations, Violation{Field: field + "." + name, Message: "is not allowed"})
+				continue
+			default:
+				continue
+			}
+
+			violations = property.check(field+"."+name, v[name], strict, violations)
+		}
+	}
+
+	return violations
+}
+
+func (s *Schema) hasType(value any) bool {
+	switch s.Type {
+	case "":
+		return true
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+
+		_, err := strconv.ParseInt(n.String(), 10, 64)
+
+		return err == nil
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	}
+
+	return false
+}
+
+// compiler turns the YAML of the document into schemas. Referenced schemas
+// are compiled once, which also makes recursive references work.
+type compiler struct {
+	root map[string]any
+	refs map[string]*Schema
+}
+
+func (c *compiler) operation(raw any) (*Operation, error) {
+	node, ok := raw.(map[string]any)
+	if !ok {
+		return nil, errors.New("operation must be an object")
+	}
+
+	op := &Operation{responses: map[int]*Schema{}}
+
+	params, _ := node["parameters"].([]any)
+	for _, rawParam := range params {
+		param, err := c.resolve(rawParam)
+		if err != nil {
+			return nil, err
+		}
+
+		p := parameter{}
+		p.name, _ = param["name"].(string)
+		p.in, _ = param["in"].(string)
+		p.required, _ = param["required"].(bool)
+
+		if p.in == "header" {
+			p.name = http.CanonicalHeaderKey(p.name)
+		}
+
+		if rawSchema, ok := param["schema"]; ok {
+			if p.schema, err = c.schema(rawSchema); err != nil {
+				return nil, fmt.Errorf("parameter %s: %w", p.name, err)
+			}
+		}
+
+		op.params = append(op.params, p)
+	}
+
+	if rawBody, ok := node["requestBody"]; ok {
+		body, err := c.resolve(rawBody)
+		if err != nil {
+			return nil, err
+		}
+
+		op.body = &requestBody{}
+		op.body.required, _ = body["required"].(bool)
+
+		if op.body.json, err = c.jsonContent(body); err != nil {
+			return nil, fmt.Errorf("request body: %w", err)
+		}
+	}
+
+	responses, _ := node["responses"].(map[string]any)
+	for code, rawResponse := range responses {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			// "default" and ranges like "5XX" are not checked.
+			continue
+		}
+
+		response, err := c.resolve(rawResponse)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.responses[status], err = c.jsonContent(response); err != nil {
+			return nil, fmt.Errorf("response %d: %w", status, err)
+		}
+	}
+
+	return op, nil
+}
+
+// jsonContent compiles the application/json schema of a request body or
+// response, nil if there is none.
+func (c *compiler) jsonContent(node map[string]any) (*Schema, error) {
+	content, _ := node["content"].(map[string]any)
+
+	media, ok := content[mediaTypeJSON].(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+
+	rawSchema, ok := media["schema"]
+	if !ok {
+		return nil, nil
+	}
+
+	return c.schema(rawSchema)
+}
+
+func (c *compiler) schema(raw any) (*Schema, error) {
+	node, ok := raw.(map[string]any)
+	if !ok {
+		return nil, errors.New("schema must be an object")
+	}
+
+	if ref, ok := node["$ref"].(string); ok {
+		if s, ok := c.refs[ref]; ok {
+			return s, nil
+		}
+
+		target, err := c.lookup(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		s := &Schema{}
+		c.refs[ref] = s
+
+		compiled, err := c.schema(target)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+
+		*s = *compiled
+
+		return s, nil
+	}
+
+	s := &Schema{}
+	s.Type, _ = node["type"].(string)
+	s.Format, _ = node["format"].(string)
+	s.Nullable, _ = node["nullable"].(bool)
+
+	if enum, ok := node["enum"].([]any); ok {
+		s.Enum = enum
+	}
+
+	if required, ok := node["required"].([]any); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				s.Required = append(s.Required, name)
+			}
+		}
+	}
+
+	if properties, ok := node["properties"].(map[string]any); ok {
+		s.Properties = make(map[string]*Schema, len(properties))
+
+		for name, rawProperty := range properties {
+			property, err := c.schema(rawProperty)
+			if err != nil {
+				return nil, fmt.Errorf("property %s: %w", name, err)
+			}
+
+			s.Properties[name] = property
+		}
+	}
+
+	switch additional := node["additionalProperties"].(type) {
+	case nil:
+	case bool:
+		s.additionalSet = true
+		s.additionalForbidden = !additional
+	default:
+		property, err := c.schema(additional)
+		if err != nil {
+			return nil, fmt.Errorf("additionalProperties: %w", err)
+		}
+
+		s.additionalSet = true
+		s.AdditionalProperties = property
+	}
+
+	if rawItems, ok := node["items"]; ok {
+		items, err := c.schema(rawItems)
+		if err != nil {
+			return nil, fmt.Errorf("items: %w", err)
+		}
+
+		s.Items = items
+	}
+
+	var err error
+
+	for key, target := range map[string]**int{
+		"minItems":  &s.MinItems,
+		"maxItems":  &s.MaxItems,
+		"minLength": &s.MinLength,
+		"maxLength": &s.MaxLength,
+	} {
+		if *target, err = intKeyword(node, key); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.Minimum, err = numberKeyword(node, "minimum"); err != nil {
+		return nil, err
+	}
+
+	if s.Maximum, err = numberKeyword(node, "maximum"); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// resolve follows the $ref of a parameter, request body or response.
+func (c *compiler) resolve(raw any) (map[string]any, error) {
+	node, ok := raw.(map[string]any)
+	if !ok {
+		return nil, errors.New("expected an object")
+	}
+
+	ref, ok := node["$ref"].(string)
+	if !ok {
+		return node, nil
+	}
+
+	target, err := c.lookup(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, ok := target.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an object", ref)
+	}
+
+	return resolved, nil
+}
+
+// lookup finds a local reference like #/components/schemas/Team.
+func (c *compiler) lookup(ref string) (any, error) {
+	path, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %q", ref)
+	}
+
+	var node any = c.root
+
+	for _, part := range strings.Split(path, "/") {
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolved reference %q", ref)
+		}
+
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+
+		if node, ok = object[part]; !ok {
+			return nil, fmt.Errorf("unresolved reference %q", ref)
+		}
+	}
+
+	return node, nil
+}
+
+func intKeyword(node map[string]any, key string) (*int, error) {
+	raw, ok := node[key]
+	if !ok {
+		return nil, nil
+	}
+
+	n, ok := raw.(int)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an integer", key)
+	}
+
+	return &n, nil
+}
+
+func numberKeyword(node map[string]any, key string) (*big.Float, error) {
+	switch n := node[key].(type) {
+	case nil:
+		return nil, nil
+	case int:
+		return new(big.Float).SetInt64(int64(n)), nil
+	case float64:
+		return big.NewFloat(n), nil
+	default:
+		return nil, fmt.Errorf("%s must be a number", key)
+	}
+}
+
+// equal compares an enum value from the YAML document with a decoded
+// JSON value.
+func equal(enum, value any) bool {
+	if n, ok := value.(json.Number); ok {
+		switch e := enum.(type) {
+		case int:
+			return n.String() == strconv.Itoa(e)
+		case float64:
+			f, err := n.Float64()
+			return err == nil && f == e
+		}
+
+		return false
+	}
+
+	return enum == value
+}
+
+func enumList(enum []any) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprint(e)
+	}
+
+	return strings.Join(values, ", ")
+}
+
+func article(typ string) string {
+	switch typ {
+	case "array", "integer", "object":
+		return "an " + typ
+	}
+
+	return "a " + typ
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
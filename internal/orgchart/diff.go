@@ -0,0 +1,114 @@
+package orgchart
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+// UserChangeJSON is one user in a DiffJSON.
+type UserChangeJSON struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	FromTeam string `json:"from_team,omitempty"`
+	IsActive bool   `json:"is_active"`
+}
+
+// DiffJSON is the JSON form of a diff.
+type DiffJSON struct {
+	DryRun           bool             `json:"dry_run"`
+	TeamsCreated     []string         `json:"teams_created"`
+	UsersCreated     []UserChangeJSON `json:"users_created"`
+	UsersMoved       []UserChangeJSON `json:"users_moved"`
+	UsersRestored    []UserChangeJSON `json:"users_restored"`
+	UsersUpdated     []UserChangeJSON `json:"users_updated"`
+	UsersDeactivated []UserChangeJSON `json:"users_deactivated"`
+	Unchanged        int              `json:"unchanged"`
+}
+
+// NewDiffJSON converts a diff for the HTTP response, with empty lists instead of nulls.
+func NewDiffJSON(diff domain.OrgChartDiff) DiffJSON {
+	teamsCreated := diff.TeamsCreated
+	if teamsCreated == nil {
+		teamsCreated = []string{}
+	}
+
+	return DiffJSON{
+		DryRun:           diff.DryRun,
+		TeamsCreated:     teamsCreated,
+		UsersCreated:     userChangesJSON(diff.UsersCreated),
+		UsersMoved:       userChangesJSON(diff.UsersMoved),
+		UsersRestored:    userChangesJSON(diff.UsersRestored),
+		UsersUpdated:     userChangesJSON(diff.UsersUpdated),
+		UsersDeactivated: userChangesJSON(diff.UsersDeactivated),
+		Unchanged:        diff.Unchanged,
+	}
+}
+
+func userChangesJSON(changes []domain.OrgChartUserChange) []UserChangeJSON {
+	res := make([]UserChangeJSON, 0, len(changes))
+	for _, change := range changes {
+		res = append(res, UserChangeJSON(change))
+	}
+
+	return res
+}
+
+// WriteDiff prints the diff one change per line, in the style of a unified diff.
+func WriteDiff(w io.Writer, diff domain.OrgChartDiff) error {
+	var lines []string
+
+	for _, teamName := range diff.TeamsCreated {
+		lines = append(lines, fmt.Sprintf("+ team %s", teamName))
+	}
+
+	for _, change := range diff.UsersCreated {
+		lines = append(lines, fmt.Sprintf("+ user %s (%s) in %s%s", change.UserID, change.Username,
+			change.TeamName, inactiveSuffix(change)))
+	}
+
+	for _, change := range diff.UsersRestored {
+		lines = append(lines, fmt.Sprintf("+ user %s (%s) restored in %s%s", change.UserID, change.Username,
+			change.TeamName, inactiveSuffix(change)))
+	}
+
+	for _, change := range diff.UsersMoved {
+		lines = append(lines, fmt.Sprintf("~ user %s (%s) moved %s -> %s%s", change.UserID, change.Username,
+			change.FromTeam, change.TeamName, inactiveSuffix(change)))
+	}
+
+	for _, change := range diff.UsersUpdated {
+		lines = append(lines, fmt.Sprintf("~ user %s (%s) in %s updated, active: %t", change.UserID,
+			change.Username, change.TeamName, change.IsActive))
+	}
+
+	for _, change := range diff.UsersDeactivated {
+		lines = append(lines, fmt.Sprintf("- user %s (%s) deactivated in %s", change.UserID, change.Username,
+			change.TeamName))
+	}
+
+	status := "applied"
+	if diff.DryRun {
+		status = "dry run, nothing applied"
+	}
+
+	lines = append(lines, fmt.Sprintf("%d changes, %d users unchanged (%s)", len(lines), diff.Unchanged, status))
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func inactiveSuffix(change domain.OrgChartUserChange) string {
+	if change.IsActive {
+		return ""
+	}
+
+	return ", inactive"
+}
@@ -0,0 +1,235 @@
+// Package orgchart reads org charts for bulk team imports and prints their diffs.
+//
+// CSV charts have one row per user and a header naming the columns
+// team_name, user_id, username and optionally is_active:
+//
+//	team_name,user_id,username,is_active
+//	backend,u1,Alice,true
+//
+// YAML charts follow the body of POST /team/add:
+//
+//	teams:
+//	  - team_name: backend
+//	    members:
+//	      - user_id: u1
+//	        username: Alice
+//	        is_active: true
+//
+// is_active defaults to true in both formats.
+package orgchart
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the file format of a chart.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatYAML Format = "yaml"
+)
+
+// FormatFromContentType maps text/csv and the YAML media types to a format.
+func FormatFromContentType(contentType string) (Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return FormatCSV, true
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FormatYAML, true
+	default:
+		return "", false
+	}
+}
+
+// FormatFromPath detects the format by the file extension.
+func FormatFromPath(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, true
+	case ".yaml", ".yml":
+		return FormatYAML, true
+	default:
+		return "", false
+	}
+}
+
+// Parse reads and validates a chart.
+func Parse(r io.Reader, format Format) (domain.OrgChart, error) {
+	var (
+		chart domain.OrgChart
+		err   error
+	)
+
+	switch format {
+	case FormatCSV:
+		chart, err = parseCSV(r)
+	case FormatYAML:
+		chart, err = parseYAML(r)
+	default:
+		return domain.OrgChart{}, fmt.Errorf("unsupported org chart format %q", format)
+	}
+
+	if err != nil {
+		return domain.OrgChart{}, err
+	}
+
+	if err = validate(chart); err != nil {
+		return domain.OrgChart{}, err
+	}
+
+	return chart, nil
+}
+
+func parseCSV(r io.Reader) (domain.OrgChart, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return domain.OrgChart{}, fmt.Errorf("error reading csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	for _, name := range []string{"team_name", "user_id", "username"} {
+		if _, ok := columns[name]; !ok {
+			return domain.OrgChart{}, fmt.Errorf("csv header has no %s column", name)
+		}
+	}
+
+	var chart domain.OrgChart
+
+	teams := make(map[string]int)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return domain.OrgChart{}, fmt.Errorf("error reading csv: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		isActive := true
+		if i, ok := columns["is_active"]; ok && strings.TrimSpace(record[i]) != "" {
+			isActive, err = strconv.ParseBool(strings.TrimSpace(record[i]))
+			if err != nil {
+				return domain.OrgChart{}, fmt.Errorf("line %d: is_active must be a boolean", line)
+			}
+		}
+
+		teamName := strings.TrimSpace(record[columns["team_name"]])
+
+		index, ok := teams[teamName]
+		if !ok {
+			index = len(chart.Teams)
+			teams[teamName] = index
+			chart.Teams = append(chart.Teams, domain.TeamUpsert{Name: teamName})
+		}
+
+		chart.Teams[index].Members = append(chart.Teams[index].Members, domain.TeamMember{
+			UserID:   strings.TrimSpace(record[columns["user_id"]]),
+			Username: strings.TrimSpace(record[columns["username"]]),
+			IsActive: isActive,
+		})
+	}
+
+	return chart, nil
+}
+
+type yamlChart struct {
+	Teams []struct {
+		TeamName string `yaml:"team_name"`
+		Members  []struct {
+			UserID   string `yaml:"user_id"`
+			Username string `yaml:"username"`
+			IsActive *bool  `yaml:"is_active"`
+		} `yaml:"members"`
+	} `yaml:"teams"`
+}
+
+func parseYAML(r io.Reader) (domain.OrgChart, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var doc yamlChart
+	if err := decoder.Decode(&doc); err != nil {
+		return domain.OrgChart{}, fmt.Errorf("error decoding yaml: %w", err)
+	}
+
+	chart := domain.OrgChart{Teams: make([]domain.TeamUpsert, 0, len(doc.Teams))}
+
+	for _, team := range doc.Teams {
+		members := make([]domain.TeamMember, 0, len(team.Members))
+
+		for _, member := range team.Members {
+			members = append(members, domain.TeamMember{
+				UserID:   member.UserID,
+				Username: member.Username,
+				IsActive: member.IsActive == nil || *member.IsActive,
+			})
+		}
+
+		chart.Teams = append(chart.Teams, domain.TeamUpsert{Name: team.TeamName, Members: members})
+	}
+
+	return chart, nil
+}
+
+// validate rejects charts that cannot be applied unambiguously.
+func validate(chart domain.OrgChart) error {
+	if len(chart.Teams) == 0 {
+		return errors.New("org chart has no teams")
+	}
+
+	teams := make(map[string]struct{}, len(chart.Teams))
+	users := make(map[string]string)
+
+	for _, team := range chart.Teams {
+		if team.Name == "" {
+			return errors.New("team_name is required")
+		}
+
+		if _, ok := teams[team.Name]; ok {
+			return fmt.Errorf("team %s is listed twice", team.Name)
+		}
+
+		teams[team.Name] = struct{}{}
+
+		for _, member := range team.Members {
+			if member.UserID == "" {
+				return fmt.Errorf("team %s: user_id is required", team.Name)
+			}
+
+			if other, ok := users[member.UserID]; ok {
+				return fmt.Errorf("user %s is listed in teams %s and %s", member.UserID, other, team.Name)
+			}
+
+			users[member.UserID] = team.Name
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package orgchart_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/orgchart"
+)
+
+func TestParse(t *testing.T) {
+	backend := domain.TeamUpsert{Name: "backend", Members: []domain.TeamMember{
+		{UserID: "u1", Username: "Alice", IsActive: true},
+		{UserID: "u2", Username: "Bob", IsActive: false},
+	}}
+
+	tests := []struct {
+		name    string
+		format  orgchart.Format
+		input   string
+		want    domain.OrgChart
+		wantErr string
+	}{
+		{
+			name:   "csv",
+			format: orgchart.FormatCSV,
+			input:  "team_name, user_id, username, is_active\nbackend,u1,Alice,\nbackend,u2,Bob,false\n",
+			want:   domain.OrgChart{Teams: []domain.TeamUpsert{backend}},
+		},
+		{
+			name:   "csv without is_active",
+			format: orgchart.FormatCSV,
+			input:  "username,user_id,team_name\nAlice,u1,backend\n",
+			want: domain.OrgChart{Teams: []domain.TeamUpsert{
+				{Name: "backend", Members: backend.Members[:1]},
+			}},
+		},
+		{
+			name:    "csv missing column",
+			format:  orgchart.FormatCSV,
+			input:   "team_name,user_id\nbackend,u1\n",
+			wantErr: "no username column",
+		},
+		{
+			name:    "csv bad is_active",
+			format:  orgchart.FormatCSV,
+			input:   "team_name,user_id,username,is_active\nbackend,u1,Alice,maybe\n",
+			wantErr: "line 2: is_active must be a boolean",
+		},
+		{
+			name:   "yaml",
+			format: orgchart.FormatYAML,
+			input: "teams:\n  - team_name: backend\n    members:\n" +
+				"      - {user_id: u1, username: Alice}\n      - {user_id: u2, username: Bob, is_active: false}\n",
+			want: domain.OrgChart{Teams: []domain.TeamUpsert{backend}},
+		},
+		{
+			name:    "yaml unknown field",
+			format:  orgchart.FormatYAML,
+			input:   "teams:\n  - team: backend\n",
+			wantErr: "error decoding yaml",
+		},
+		{
+			name:    "no teams",
+			format:  orgchart.FormatYAML,
+			input:   "teams: []\n",
+			wantErr: "org chart has no teams",
+		},
+		{
+			name:    "team listed twice",
+			format:  orgchart.FormatYAML,
+			input:   "teams:\n  - team_name: backend\n  - team_name: backend\n",
+			wantErr: "team backend is listed twice",
+		},
+		{
+			name:    "empty team_name",
+			format:  orgchart.FormatCSV,
+			input:   "team_name,user_id,username\nbackend,u1,Alice\n,u2,Bob\n",
+			wantErr: "team_name is required",
+		},
+		{
+			name:    "user in two teams",
+			format:  orgchart.FormatCSV,
+			input:   "team_name,user_id,username\nbackend,u1,Alice\nfrontend,u1,Alice\n",
+			wantErr: "user u1 is listed in teams backend and frontend",
+		},
+		{
+			name:    "unknown format",
+			format:  "json",
+			input:   "{}",
+			wantErr: "unsupported org chart format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := orgchart.Parse(strings.NewReader(tt.input), tt.format)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDetection(t *testing.T) {
+	for path, want := range map[string]orgchart.Format{
+		"teams.csv":  orgchart.FormatCSV,
+		"teams.YML":  orgchart.FormatYAML,
+		"teams.yaml": orgchart.FormatYAML,
+		"teams":      "",
+	} {
+		if got, _ := orgchart.FormatFromPath(path); got != want {
+			t.Errorf("FormatFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+
+	for contentType, want := range map[string]orgchart.Format{
+		"text/csv; charset=utf-8": orgchart.FormatCSV,
+		"application/yaml":        orgchart.FormatYAML,
+		"application/json":        "",
+	} {
+		if got, _ := orgchart.FormatFromContentType(contentType); got != want {
+			t.Errorf("FormatFromContentType(%q) = %q, want %q", contentType, got, want)
+		}
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	diff := domain.OrgChartDiff{
+		DryRun:       true,
+		TeamsCreated: []string{"mobile"},
+		UsersCreated: []domain.OrgChartUserChange{
+			{UserID: "u6", Username: "Frank", TeamName: "mobile", IsActive: false},
+		},
+		UsersMoved: []domain.OrgChartUserChange{
+			{UserID: "u4", Username: "Dan", TeamName: "backend", FromTeam: "frontend", IsActive: true},
+		},
+		UsersRestored: []domain.OrgChartUserChange{
+			{UserID: "u5", Username: "Eve", TeamName: "mobile", IsActive: true},
+		},
+		UsersUpdated: []domain.OrgChartUserChange{
+			{UserID: "u3", Username: "Carol", TeamName: "backend", IsActive: false},
+		},
+		UsersDeactivated: []domain.OrgChartUserChange{
+			{UserID: "u2", Username: "Bob", TeamName: "backend"},
+		},
+		Unchanged: 1,
+	}
+
+	want := `+ team mobile
++ user u6 (Frank) in mobile, inactive
++ user u5 (Eve) restored in mobile
+~ user u4 (Dan) moved frontend -> backend
+~ user u3 (Carol) in backend updated, active: false
+- user u2 (Bob) deactivated in backend
+6 changes, 1 users unchanged (dry run, nothing applied)
+`
+
+	var out strings.Builder
+	if err := orgchart.WriteDiff(&out, diff); err != nil {
+		t.Fatalf("write diff: %v", err)
+	}
+
+	if out.String() != want {
+		t.Errorf("got diff\n%s\nwant\n%s", out.String(), want)
+	}
+
+	diffJSON := orgchart.NewDiffJSON(domain.OrgChartDiff{})
+	if diffJSON.TeamsCreated == nil || diffJSON.UsersCreated == nil || diffJSON.UsersDeactivated == nil {
+		t.Errorf("got nil lists in %+v, want empty ones", diffJSON)
+	}
+}
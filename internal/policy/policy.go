@@ -0,0 +1,240 @@
+// Package policy loads the reviewer assignment policy and swaps it at
+// runtime when its file changes, without restarting the server.
+package policy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultVersion is the version of the built-in policy used without a file.
+const DefaultVersion = "default"
+
+// Policy is an assignment policy with the file content it was read from.
+type Policy struct {
+	domain.AssignmentPolicy
+	// Version is a hash of the file content, DefaultVersion without a file.
+	Version  string
+	Source   string
+	LoadedAt time.Time
+}
+
+// Status is the active policy and the outcome of the last failed reload,
+// if it happened after the policy was loaded.
+type Status struct {
+	Policy
+	LastError   string
+	LastErrorAt time.Time
+}
+
+// Store holds the active policy. Readers get it without locking, reloads
+// validate the new policy first and keep the old one if it is invalid.
+type Store struct {
+	path   string
+	active atomic.Pointer[Policy]
+
+	// mu serializes reloads and guards the failure fields.
+	mu            sync.Mutex
+	lastError     string
+	lastErrorAt   time.Time
+	failedVersion string
+}
+
+// NewStore loads the policy file at path, or the default policy if path is
+// empty. An invalid file fails, so that the service does not start with it.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path}
+
+	if path == "" {
+		s.active.Store(&Policy{
+			AssignmentPolicy: domain.DefaultAssignmentPolicy(),
+			Version:          DefaultVersion,
+			Source:           "default",
+			LoadedAt:         time.Now(),
+		})
+
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading policy file: %w", err)
+	}
+
+	p, err := parse(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.active.Store(p)
+
+	return s, nil
+}
+
+// Policy returns the active assignment policy.
+func (s *Store) Policy() domain.AssignmentPolicy {
+	return s.active.Load().AssignmentPolicy
+}
+
+// Status returns the active policy and the last failed reload.
+func (s *Store) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Status{
+		Policy:      *s.active.Load(),
+		LastError:   s.lastError,
+		LastErrorAt: s.lastErrorAt,
+	}
+}
+
+// Reload reads the policy file again and swaps the active policy if the
+// content changed. On error the active policy stays in place.
+func (s *Store) Reload() (_ Policy, changed bool, err error) {
+	return s.reload(true)
+}
+
+// Watch reloads the policy when its file changes, checking every interval.
+// A file that failed to load is not retried until it changes again.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	if s.path == "" || interval <= 0 {
+		return
+	}
+
+	logger := logging.FromContext(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p, changed, err := s.reload(false)
+			if err != nil {
+				logger.Error("error reloading policy", slog.String("active_version", p.Version), logging.Error(err))
+				continue
+			}
+
+			if changed {
+				logger.Info("policy reloaded", slog.String("version", p.Version), slog.String("trigger", "watch"))
+			}
+		}
+	}
+}
+
+func (s *Store) reload(retryFailed bool) (Policy, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := *s.active.Load()
+
+	if s.path == "" {
+		return current, false, nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return current, false, s.fail(fmt.Errorf("error reading policy file: %w", err), "")
+	}
+
+	version := versionOf(data)
+	if version == current.Version || (!retryFailed && version == s.failedVersion) {
+		return current, false, nil
+	}
+
+	p, err := parse(s.path, data)
+	if err != nil {
+		return current, false, s.fail(err, version)
+	}
+
+	s.active.Store(p)
+	s.lastError, s.lastErrorAt, s.failedVersion = "", time.Time{}, ""
+
+	return *p, true, nil
+}
+
+func (s *Store) fail(err error, version string) error {
+	s.lastError, s.lastErrorAt, s.failedVersion = err.Error(), time.Now(), version
+
+	return err
+}
+
+// file is the policy file format. Omitted keys keep their default values.
+type file struct {
+	ReviewersPerPR int    `yaml:"reviewers_per_pr" toml:"reviewers_per_pr"`
+	Strategy       string `yaml:"strategy"         toml:"strategy"`
+	MaxOpenReviews int    `yaml:"max_open_reviews" toml:"max_open_reviews"`
+}
+
+func parse(path string, data []byte) (*Policy, error) {
+	defaults := domain.DefaultAssignmentPolicy()
+	f := file{
+		ReviewersPerPR: defaults.ReviewersPerPR,
+		Strategy:       string(defaults.Strategy),
+		MaxOpenReviews: defaults.MaxOpenReviews,
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+
+		if err := decoder.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error parsing policy file %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), &f)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing policy file %s: %w", path, err)
+		}
+
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("error parsing policy file %s: unknown key %s", path, undecoded[0])
+		}
+	default:
+		return nil, fmt.Errorf("policy file %s must be .yaml, .yml or .toml, got %q", path, ext)
+	}
+
+	p := domain.AssignmentPolicy{
+		ReviewersPerPR: f.ReviewersPerPR,
+		Strategy:       domain.AssignmentStrategy(f.Strategy),
+		MaxOpenReviews: f.MaxOpenReviews,
+	}
+
+	if problems := p.Problems(); len(problems) > 0 {
+		return nil, fmt.Errorf("invalid policy in %s:\n  - %s", path, strings.Join(problems, "\n  - "))
+	}
+
+	return &Policy{
+		AssignmentPolicy: p,
+		Version:          versionOf(data),
+		Source:           path,
+		LoadedAt:         time.Now(),
+	}, nil
+}
+
+// versionOf returns a short hash of the policy file content.
+func versionOf(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:6])
+}
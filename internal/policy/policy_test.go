@@ -0,0 +1,151 @@
+package policy_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/policy"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write policy file: %v", err)
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    domain.AssignmentPolicy
+		wantErr string
+	}{
+		{
+			name: "default without file",
+			want: domain.DefaultAssignmentPolicy(),
+		},
+		{
+			name:    "yaml with omitted keys",
+			file:    "policy.yaml",
+			content: "strategy: least_loaded\n",
+			want:    domain.AssignmentPolicy{ReviewersPerPR: 2, Strategy: domain.AssignmentStrategyLeastLoaded},
+		},
+		{
+			name:    "toml",
+			file:    "policy.toml",
+			content: "reviewers_per_pr = 1\nmax_open_reviews = 3\n",
+			want: domain.AssignmentPolicy{
+				ReviewersPerPR: 1,
+				Strategy:       domain.AssignmentStrategyRandom,
+				MaxOpenReviews: 3,
+			},
+		},
+		{
+			name:    "unknown yaml key",
+			file:    "unknown.yaml",
+			content: "reviewers: 2\n",
+			wantErr: "field reviewers not found",
+		},
+		{
+			name:    "unknown toml key",
+			file:    "unknown.toml",
+			content: "reviewers = 2\n",
+			wantErr: "unknown key reviewers",
+		},
+		{
+			name:    "invalid values",
+			file:    "invalid.yaml",
+			content: "reviewers_per_pr: 6\nstrategy: round_robin\n",
+			wantErr: "reviewers_per_pr must be between 1 and 5, got 6\n" +
+				`  - strategy must be one of random, least_loaded, got "round_robin"`,
+		},
+		{
+			name:    "unsupported extension",
+			file:    "policy.json",
+			content: "{}",
+			wantErr: "must be .yaml, .yml or .toml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.file != "" {
+				path = filepath.Join(dir, tt.file)
+				writeFile(t, path, tt.content)
+			}
+
+			store, err := policy.NewStore(path)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("new store: %v", err)
+			}
+
+			if got := store.Policy(); got != tt.want {
+				t.Errorf("got policy %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	writeFile(t, path, "reviewers_per_pr: 1\n")
+
+	store, err := policy.NewStore(path)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	loaded := store.Status().Version
+
+	if _, changed, err := store.Reload(); err != nil || changed {
+		t.Errorf("got changed %v, error %v for the same file, want no change", changed, err)
+	}
+
+	writeFile(t, path, "reviewers_per_pr: 0\n")
+
+	if _, _, err = store.Reload(); err == nil {
+		t.Fatal("got no error for an invalid policy")
+	}
+
+	status := store.Status()
+	if status.Version != loaded || status.ReviewersPerPR != 1 {
+		t.Errorf("got active policy %+v after a failed reload, want the previous one", status.Policy)
+	}
+
+	if !strings.Contains(status.LastError, "reviewers_per_pr must be between 1 and 5, got 0") {
+		t.Errorf("got last error %q, want the validation problem", status.LastError)
+	}
+
+	writeFile(t, path, "reviewers_per_pr: 3\n")
+
+	p, changed, err := store.Reload()
+	if err != nil || !changed {
+		t.Fatalf("got changed %v, error %v for a new valid policy", changed, err)
+	}
+
+	if p.Version == loaded || store.Policy().ReviewersPerPR != 3 {
+		t.Errorf("got policy %+v, want the reloaded one", p)
+	}
+
+	if status = store.Status(); status.LastError != "" {
+		t.Errorf("got last error %q after a successful reload, want it cleared", status.LastError)
+	}
+}
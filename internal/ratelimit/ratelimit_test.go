@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAllowTakesTokensPerKey(t *testing.T) {
+	l := New(1, 2)
+
+	for i := range 2 {
+		if allowed, _ := l.Allow("a"); !allowed {
+			t.Fatalf("request %d of a was limited within the burst", i+1)
+		}
+	}
+
+	allowed, wait := l.Allow("a")
+	if allowed {
+		t.Fatal("got third request of a allowed, want it limited after the burst")
+	}
+
+	if wait <= 0 || wait > time.Second {
+		t.Errorf("got wait %v, want up to 1s for one token at 1 rps", wait)
+	}
+
+	if allowed, _ = l.Allow("b"); !allowed {
+		t.Error("got b limited, want its own bucket")
+	}
+}
+
+func TestAllowRefills(t *testing.T) {
+	l := New(2, 1)
+
+	if allowed, _ := l.Allow("a"); !allowed {
+		t.Fatal("got first request limited")
+	}
+
+	// Half a second at 2 rps gives the token back.
+	l.buckets["a"].updated = l.buckets["a"].updated.Add(-500 * time.Millisecond)
+
+	if allowed, _ := l.Allow("a"); !allowed {
+		t.Error("got request limited after the bucket refilled")
+	}
+
+	// A long pause does not grow the bucket past the burst.
+	l.buckets["a"].updated = l.buckets["a"].updated.Add(-time.Hour)
+
+	if allowed, _ := l.Allow("a"); !allowed {
+		t.Fatal("got request limited after a long pause")
+	}
+
+	if allowed, _ := l.Allow("a"); allowed {
+		t.Error("got second request allowed, want the bucket capped at the burst")
+	}
+}
+
+func TestSweepDropsFullBuckets(t *testing.T) {
+	l := New(1, 5)
+
+	l.Allow("idle")
+	l.Allow("busy")
+
+	l.buckets["idle"].updated = l.buckets["idle"].updated.Add(-time.Minute)
+	l.lastSweep = l.lastSweep.Add(-sweepInterval)
+
+	l.Allow("busy")
+
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("got the refilled bucket kept, want it swept")
+	}
+
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Error("got the used bucket swept, want it kept")
+	}
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type ArchiveRepository interface {
+	ListMergedBefore(ctx context.Context, before time.Time, limit int) ([]domain.PullRequest, error)
+	ArchivePullRequests(ctx context.Context, pullRequestIDs []string) error
+	// TombstonePullRequests archives pull requests without their reviewers,
+	// once they are exported elsewhere.
+	TombstonePullRequests(ctx context.Context, pullRequestIDs []string) error
+	GetByID(ctx context.Context, pullRequestID string) (domain.PullRequest, error)
+	ListReviewPRs(ctx context.Context, userID string) ([]domain.PullRequest, error)
+	Stats(ctx context.Context) (domain.PullRequestStats, error)
+}
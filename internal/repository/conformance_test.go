@@ -0,0 +1,1033 @@
+package repository_test
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+	postgresrepo "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres/repo"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite"
+	sqliterepo "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite/repo"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/migrations"
+)
+
+type txManager[E any] interface {
+	TxWrapper(ctx context.Context, fn func(ctx context.Context, tx E) error) error
+}
+
+type repoFactory[E any] interface {
+	TeamRepository(exec E) repository.TeamRepository
+	UserRepository(exec E) repository.UserRepository
+	PullRequestRepository(exec E) repository.PullRequestRepository
+	IdempotencyRepository(exec E) repository.IdempotencyRepository
+	ArchiveRepository(exec E) repository.ArchiveRepository
+	SnapshotRepository(exec E) repository.SnapshotRepository
+}
+
+// backend is an empty storage the suite runs against.
+type backend struct {
+	// tryTx runs fn in a transaction with the repositories bound to it.
+	tryTx func(fn func(ctx context.Context, r repos) error) error
+}
+
+func newBackend[E any](txManager txManager[E], repoFact repoFactory[E]) backend {
+	return backend{tryTx: func(fn func(ctx context.Context, r repos) error) error {
+		return txManager.TxWrapper(context.Background(), func(ctx context.Context, tx E) error {
+			return fn(ctx, repos{
+				teams:        repoFact.TeamRepository(tx),
+				users:        repoFact.UserRepository(tx),
+				pullRequests: repoFact.PullRequestRepository(tx),
+				idempotency:  repoFact.IdempotencyRepository(tx),
+				archive:      repoFact.ArchiveRepository(tx),
+				snapshot:     repoFact.SnapshotRepository(tx),
+			})
+		})
+	}}
+}
+
+// repos are the repositories of one transaction.
+type repos struct {
+	teams        repository.TeamRepository
+	users        repository.UserRepository
+	pullRequests repository.PullRequestRepository
+	idempotency  repository.IdempotencyRepository
+	archive      repository.ArchiveRepository
+	snapshot     repository.SnapshotRepository
+}
+
+// tx runs fn in a transaction and fails the test if it returns an error.
+func (b backend) tx(t *testing.T, fn func(ctx context.Context, r repos) error) {
+	t.Helper()
+
+	if err := b.tryTx(fn); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func openMemory(t *testing.T) backend {
+	t.Helper()
+
+	return newBackend[memory.Executor](memory.NewTxManager(memory.NewStore()), memory.NewRepoFactory())
+}
+
+func openSQLite(t *testing.T) backend {
+	t.Helper()
+
+	cfg := &config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "test.db"), BusyTimeout: 5 * time.Second}
+
+	db, err := sqlite.Open(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	return newBackend[sqlite.Execer](sqlite.NewTxManager(db), sqliterepo.NewRepoFactory(sqlite.NewStatementBuilder()))
+}
+
+// openPostgres migrates the database at TEST_DATABASE_URL and empties it.
+func openPostgres(t *testing.T) backend {
+	t.Helper()
+
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+
+	pool, err := postgres.NewPool(ctx, &config.DBConfig{DatabaseURL: databaseURL, MaxConns: 4})
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+
+	t.Cleanup(pool.Close)
+
+	migrator, err := postgres.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+
+	if _, err = migrator.Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	_, err = pool.Exec(ctx, `TRUNCATE assigned_reviewers, pull_requests, users, teams, idempotency_keys,
+		assigned_reviewers_archive, pull_requests_archive`)
+	if err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	return newBackend[postgres.Execer](
+		postgres.NewTxManager(pool, &config.TxConfig{MaxRetries: 0}),
+		postgresrepo.NewRepoFactory(postgres.NewStatementBuilder()),
+	)
+}
+
+var backends = []struct {
+	name string
+	open func(t *testing.T) backend
+}{
+	{name: "memory", open: openMemory},
+	{name: "sqlite", open: openSQLite},
+	{name: "postgres", open: openPostgres},
+}
+
+var conformanceCases = []struct {
+	name string
+	run  func(t *testing.T, b backend)
+}{
+	{name: "team insert and get", run: testTeamInsertAndGet},
+	{name: "team soft delete and restore", run: testTeamSoftDelete},
+	{name: "user upsert and activity", run: testUserUpsert},
+	{name: "user soft delete and restore", run: testUserSoftDelete},
+	{name: "pull request insert and get", run: testPullRequestInsertAndGet},
+	{name: "pull request reviewers", run: testPullRequestReviewers},
+	{name: "pull request merge and version", run: testPullRequestMerge},
+	{name: "pull request soft delete and restore", run: testPullRequestSoftDelete},
+	{name: "pull request count open by team", run: testPullRequestCountOpen},
+	{name: "pull request count open reviews", run: testPullRequestCountOpenReviews},
+	{name: "idempotency keys", run: testIdempotencyKeys},
+	{name: "rollback", run: testRollback},
+	{name: "archive", run: testArchive},
+	{name: "snapshot", run: testSnapshot},
+}
+
+// TestRepositoryConformance runs the same cases against every backend, so
+// they keep behaving alike.
+func TestRepositoryConformance(t *testing.T) {
+	for _, bk := range backends {
+		t.Run(bk.name, func(t *testing.T) {
+			for _, tc := range conformanceCases {
+				t.Run(tc.name, func(t *testing.T) {
+					tc.run(t, bk.open(t))
+				})
+			}
+		})
+	}
+}
+
+func wantCode(t *testing.T, what string, err error, code domain.ErrorCode) {
+	t.Helper()
+
+	if !domain.IsErrorCode(err, code) {
+		t.Errorf("%s: got error %v, want %s", what, err, code)
+	}
+}
+
+// seed creates team backend with active users u1, u2, u3 and inactive u4.
+func seed(t *testing.T, b backend) {
+	t.Helper()
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		if err := r.teams.InsertTeam(ctx, "backend"); err != nil {
+			return err
+		}
+
+		for _, userID := range []string{"u1", "u2", "u3", "u4"} {
+			user := domain.User{ID: userID, Username: "name-" + userID, TeamName: "backend", IsActive: userID != "u4"}
+			if err := r.users.UpsertUser(ctx, user); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// seedPR creates an open pull request of author reviewed by reviewers.
+func seedPR(t *testing.T, b backend, prID, authorID string, reviewers ...string) {
+	t.Helper()
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		pr := domain.PullRequest{ID: prID, Name: "name-" + prID, AuthorID: authorID}
+		if err := r.pullRequests.InsertPullRequest(ctx, pr); err != nil {
+			return err
+		}
+
+		for _, reviewerID := range reviewers {
+			if err := r.pullRequests.AddReviewer(ctx, prID, reviewerID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func getPR(t *testing.T, b backend, prID string) domain.PullRequest {
+	t.Helper()
+
+	var pr domain.PullRequest
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		var err error
+		pr, err = r.pullRequests.GetByID(ctx, prID)
+
+		return err
+	})
+
+	return pr
+}
+
+func memberIDs(team domain.TeamUpsert) []string {
+	ids := make([]string, 0, len(team.Members))
+	for _, m := range team.Members {
+		ids = append(ids, m.UserID)
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
+
+func prIDs(pullRequests []domain.PullRequest) []string {
+	ids := make([]string, 0, len(pullRequests))
+	for _, pr := range pullRequests {
+		ids = append(ids, pr.ID)
+	}
+
+	return ids
+}
+
+func testTeamInsertAndGet(t *testing.T, b backend) {
+	seed(t, b)
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		team, err := r.teams.GetTeamWithMembers(ctx, "backend")
+		if err != nil {
+			return err
+		}
+
+		if ids := memberIDs(team); team.Name != "backend" || !slices.Equal(ids, []string{"u1", "u2", "u3", "u4"}) {
+			t.Errorf("got team %s with members %v, want backend with u1..u4", team.Name, ids)
+		}
+
+		wantCode(t, "insert duplicate team", r.teams.InsertTeam(ctx, "backend"), domain.ErrCodeTeamExists)
+
+		_, err = r.teams.GetTeamWithMembers(ctx, "missing")
+		wantCode(t, "get missing team", err, domain.ErrCodeNotFound)
+
+		return nil
+	})
+
+	// A team without members exists too.
+	b.tx(t, func(ctx context.Context, r repos) error {
+		if err := r.teams.InsertTeam(ctx, "empty"); err != nil {
+			return err
+		}
+
+		team, err := r.teams.GetTeamWithMembers(ctx, "empty")
+		if err != nil {
+			return err
+		}
+
+		if len(team.Members) != 0 {
+			t.Errorf("got members %v of empty team", memberIDs(team))
+		}
+
+		return nil
+	})
+}
+
+func testTeamSoftDelete(t *testing.T, b backend) {
+	seed(t, b)
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		return r.teams.SoftDeleteTeam(ctx, "backend", time.Now())
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		_, err := r.teams.GetTeamWithMembers(ctx, "backend")
+		wantCode(t, "get deleted team", err, domain.ErrCodeNotFound)
+
+		_, err = r.users.GetByID(ctx, "u1")
+		wantCode(t, "get member of deleted team", err, domain.ErrCodeNotFound)
+
+		wantCode(t, "delete deleted team", r.teams.SoftDeleteTeam(ctx, "backend", time.Now()), domain.ErrCodeNotFound)
+		wantCode(t, "insert deleted team", r.teams.InsertTeam(ctx, "backend"), domain.ErrCodeTeamExists)
+
+		return r.teams.RestoreTeam(ctx, "backend")
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		team, err := r.teams.GetTeamWithMembers(ctx, "backend")
+		if err != nil {
+			return err
+		}
+
+		if ids := memberIDs(team); !slices.Equal(ids, []string{"u1", "u2", "u3", "u4"}) {
+			t.Errorf("got restored members %v, want u1..u4", ids)
+		}
+
+		wantCode(t, "restore live team", r.teams.RestoreTeam(ctx, "backend"), domain.ErrCodeNotFound)
+
+		return nil
+	})
+}
+
+func testUserUpsert(t *testing.T, b backend) {
+	seed(t, b)
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		user, err := r.users.GetByID(ctx, "u1")
+		if err != nil {
+			return err
+		}
+
+		want := domain.User{ID: "u1", Username: "name-u1", TeamName: "backend", IsActive: true}
+		if user != want {
+			t.Errorf("got user %+v, want %+v", user, want)
+		}
+
+		if err = r.users.SetIsActive(ctx, "u1", false); err != nil {
+			return err
+		}
+
+		if err = r.teams.InsertTeam(ctx, "frontend"); err != nil {
+			return err
+		}
+
+		// Upsert renames and moves existing users.
+		return r.users.UpsertUser(ctx, domain.User{ID: "u2", Username: "renamed", TeamName: "frontend", IsActive: true})
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		u1, err := r.users.GetByID(ctx, "u1")
+		if err != nil {
+			return err
+		}
+
+		if u1.IsActive {
+			t.Errorf("u1 is active after SetIsActive(false)")
+		}
+
+		u2, err := r.users.GetByID(ctx, "u2")
+		if err != nil {
+			return err
+		}
+
+		if u2.Username != "renamed" || u2.TeamName != "frontend" {
+			t.Errorf("got u2 %+v after upsert, want renamed in frontend", u2)
+		}
+
+		_, err = r.users.GetByID(ctx, "missing")
+		wantCode(t, "get missing user", err, domain.ErrCodeNotFound)
+		wantCode(t, "activate missing user", r.users.SetIsActive(ctx, "missing", true), domain.ErrCodeNotFound)
+
+		return nil
+	})
+}
+
+func testUserSoftDelete(t *testing.T, b backend) {
+	seed(t, b)
+	seedPR(t, b, "pr-1", "u1", "u2")
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		return r.users.SoftDeleteUser(ctx, "u2", time.Now())
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		_, err := r.users.GetByID(ctx, "u2")
+		wantCode(t, "get deleted user", err, domain.ErrCodeNotFound)
+		wantCode(t, "delete deleted user", r.users.SoftDeleteUser(ctx, "u2", time.Now()), domain.ErrCodeNotFound)
+
+		user, err := r.users.GetByIDWithDeleted(ctx, "u2")
+		if err != nil {
+			return err
+		}
+
+		if user.DeletedAt == nil {
+			t.Errorf("deleted user has no DeletedAt")
+		}
+
+		team, err := r.teams.GetTeamWithMembers(ctx, "backend")
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(memberIDs(team), "u2") {
+			t.Errorf("deleted user is listed in team")
+		}
+
+		// Review assignments of deleted users are kept.
+		reviews, err := r.users.ListReviewPRs(ctx, "u2")
+		if err != nil {
+			return err
+		}
+
+		if ids := prIDs(reviews); !slices.Equal(ids, []string{"pr-1"}) {
+			t.Errorf("got reviews %v of deleted user, want [pr-1]", ids)
+		}
+
+		return r.users.RestoreUser(ctx, "u2")
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		user, err := r.users.GetByID(ctx, "u2")
+		if err != nil {
+			return err
+		}
+
+		if user.DeletedAt != nil {
+			t.Errorf("restored user has DeletedAt %v", user.DeletedAt)
+		}
+
+		wantCode(t, "restore live user", r.users.RestoreUser(ctx, "u2"), domain.ErrCodeNotFound)
+
+		return nil
+	})
+}
+
+func testPullRequestInsertAndGet(t *testing.T, b backend) {
+	seed(t, b)
+	seedPR(t, b, "pr-1", "u1", "u2", "u3")
+
+	pr := getPR(t, b, "pr-1")
+
+	if pr.ID != "pr-1" || pr.Name != "name-pr-1" || pr.AuthorID != "u1" {
+		t.Errorf("got pull request %+v, want pr-1 by u1", pr)
+	}
+
+	if pr.Status != domain.PRStatusOpen || pr.Version != 1 || pr.MergedAt != nil || pr.CreatedAt.IsZero() {
+		t.Errorf("got status %s version %d merged at %v created at %v, want new open pull request",
+			pr.Status, pr.Version, pr.MergedAt, pr.CreatedAt)
+	}
+
+	if reviewers := slices.Sorted(slices.Values(pr.AssignedReviewers)); !slices.Equal(reviewers, []string{"u2", "u3"}) {
+		t.Errorf("got reviewers %v, want [u2 u3]", reviewers)
+	}
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		err := r.pullRequests.InsertPullRequest(ctx, domain.PullRequest{ID: "pr-1", Name: "again", AuthorID: "u2"})
+		wantCode(t, "insert duplicate pull request", err, domain.ErrCodePRExists)
+
+		return nil
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		_, err := r.pullRequests.GetByID(ctx, "missing")
+		wantCode(t, "get missing pull request", err, domain.ErrCodeNotFound)
+
+		_, err = r.pullRequests.GetByIDForUpdate(ctx, "missing")
+		wantCode(t, "get missing pull request for update", err, domain.ErrCodeNotFound)
+
+		return nil
+	})
+}
+
+func testPullRequestReviewers(t *testing.T, b backend) {
+	seed(t, b)
+	seedPR(t, b, "pr-1", "u1", "u2")
+	seedPR(t, b, "pr-2", "u1", "u2")
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		if err := r.pullRequests.RemoveReviewer(ctx, "pr-1", "u2"); err != nil {
+			return err
+		}
+
+		wantCode(t, "remove unassigned reviewer", r.pullRequests.RemoveReviewer(ctx, "pr-1", "u3"),
+			domain.ErrCodeNotAssigned)
+
+		return r.pullRequests.AddReviewer(ctx, "pr-1", "u3")
+	})
+
+	if pr := getPR(t, b, "pr-1"); !slices.Equal(pr.AssignedReviewers, []string{"u3"}) {
+		t.Errorf("got reviewers %v, want [u3]", pr.AssignedReviewers)
+	}
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		reviews, err := r.users.ListReviewPRs(ctx, "u2")
+		if err != nil {
+			return err
+		}
+
+		if ids := prIDs(reviews); !slices.Equal(ids, []string{"pr-2"}) {
+			t.Errorf("got reviews %v of u2, want [pr-2]", ids)
+		}
+
+		pullRequests, err := r.pullRequests.GetByIDs(ctx, []string{"pr-1", "pr-2", "missing"})
+		if err != nil {
+			return err
+		}
+
+		if ids := slices.Sorted(slices.Values(prIDs(pullRequests))); !slices.Equal(ids, []string{"pr-1", "pr-2"}) {
+			t.Errorf("got pull requests %v, want [pr-1 pr-2]", ids)
+		}
+
+		return nil
+	})
+}
+
+func testPullRequestMerge(t *testing.T, b backend) {
+	seed(t, b)
+	seedPR(t, b, "pr-1", "u1", "u2")
+
+	mergedAt := time.Now().UTC().Truncate(time.Second)
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		if err := r.pullRequests.IncrementVersion(ctx, "pr-1"); err != nil {
+			return err
+		}
+
+		pr, err := r.pullRequests.GetByIDForUpdate(ctx, "pr-1")
+		if err != nil {
+			return err
+		}
+
+		pr.Status = domain.PRStatusMerged
+		pr.MergedAt = &mergedAt
+
+		return r.pullRequests.MergePullRequest(ctx, pr)
+	})
+
+	pr := getPR(t, b, "pr-1")
+
+	if pr.Status != domain.PRStatusMerged || pr.MergedAt == nil || !pr.MergedAt.Equal(mergedAt) {
+		t.Errorf("got status %s merged at %v, want MERGED at %v", pr.Status, pr.MergedAt, mergedAt)
+	}
+
+	if pr.Version != 3 {
+		t.Errorf("got version %d, want 3 after increment and merge", pr.Version)
+	}
+
+	if !slices.Equal(pr.AssignedReviewers, []string{"u2"}) {
+		t.Errorf("got reviewers %v after merge, want [u2]", pr.AssignedReviewers)
+	}
+}
+
+func testPullRequestSoftDelete(t *testing.T, b backend) {
+	seed(t, b)
+	seedPR(t, b, "pr-1", "u1", "u2")
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		return r.pullRequests.SoftDeletePullRequest(ctx, "pr-1", time.Now())
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		_, err := r.pullRequests.GetByID(ctx, "pr-1")
+		wantCode(t, "get deleted pull request", err, domain.ErrCodeNotFound)
+
+		err = r.pullRequests.SoftDeletePullRequest(ctx, "pr-1", time.Now())
+		wantCode(t, "delete deleted pull request", err, domain.ErrCodeNotFound)
+
+		reviews, err := r.users.ListReviewPRs(ctx, "u2")
+		if err != nil {
+			return err
+		}
+
+		if len(reviews) != 0 {
+			t.Errorf("got reviews %v, want deleted pull request hidden", prIDs(reviews))
+		}
+
+		err = r.pullRequests.InsertPullRequest(ctx, domain.PullRequest{ID: "pr-1", Name: "again", AuthorID: "u1"})
+		wantCode(t, "insert over deleted pull request", err, domain.ErrCodePRExists)
+
+		return nil
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		return r.pullRequests.RestorePullRequest(ctx, "pr-1")
+	})
+
+	pr := getPR(t, b, "pr-1")
+
+	if pr.DeletedAt != nil || pr.Version != 3 || !slices.Equal(pr.AssignedReviewers, []string{"u2"}) {
+		t.Errorf("got deleted at %v version %d reviewers %v, want restored version 3 reviewed by u2",
+			pr.DeletedAt, pr.Version, pr.AssignedReviewers)
+	}
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		wantCode(t, "restore live pull request", r.pullRequests.RestorePullRequest(ctx, "pr-1"), domain.ErrCodeNotFound)
+
+		return nil
+	})
+}
+
+func testPullRequestCountOpen(t *testing.T, b backend) {
+	seed(t, b)
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		if err := r.teams.InsertTeam(ctx, "frontend"); err != nil {
+			return err
+		}
+
+		return r.users.UpsertUser(ctx, domain.User{ID: "u5", Username: "name-u5", TeamName: "frontend", IsActive: true})
+	})
+
+	seedPR(t, b, "pr-1", "u1", "u2")
+	seedPR(t, b, "pr-2", "u2")
+	seedPR(t, b, "pr-3", "u5")
+	seedPR(t, b, "pr-4", "u1")
+	seedPR(t, b, "pr-5", "u3")
+	mergePR(t, b, "pr-4", time.Now())
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		return r.pullRequests.SoftDeletePullRequest(ctx, "pr-5", time.Now())
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		counts, err := r.pullRequests.CountOpenByTeam(ctx)
+		if err != nil {
+			return err
+		}
+
+		if want := map[string]int{"backend": 2, "frontend": 1}; !maps.Equal(counts, want) {
+			t.Errorf("got open pull requests %v, want %v without merged and deleted ones", counts, want)
+		}
+
+		return nil
+	})
+}
+
+func testPullRequestCountOpenReviews(t *testing.T, b backend) {
+	seed(t, b)
+
+	seedPR(t, b, "pr-1", "u1", "u2", "u3")
+	seedPR(t, b, "pr-2", "u1", "u2")
+	seedPR(t, b, "pr-3", "u2", "u3")
+	seedPR(t, b, "pr-4", "u1", "u3")
+	mergePR(t, b, "pr-3", time.Now())
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		return r.pullRequests.SoftDeletePullRequest(ctx, "pr-4", time.Now())
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		counts, err := r.pullRequests.CountOpenReviews(ctx, []string{"u2", "u3", "u4"})
+		if err != nil {
+			return err
+		}
+
+		if want := map[string]int{"u2": 2, "u3": 1}; !maps.Equal(counts, want) {
+			t.Errorf("got open reviews %v, want %v without merged and deleted pull requests", counts, want)
+		}
+
+		counts, err = r.pullRequests.CountOpenReviews(ctx, nil)
+		if err != nil || len(counts) != 0 {
+			t.Errorf("got %v, %v for no users, want an empty map", counts, err)
+		}
+
+		return nil
+	})
+}
+
+func testIdempotencyKeys(t *testing.T, b backend) {
+	now := time.Now()
+	pending := domain.IdempotencyRecord{Key: "k1", Fingerprint: "f1", ExpiresAt: now.Add(time.Minute)}
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		reserved, err := r.idempotency.Reserve(ctx, pending)
+		if err != nil || !reserved {
+			t.Errorf("first reserve: got %v, %v, want reserved", reserved, err)
+		}
+
+		reserved, err = r.idempotency.Reserve(ctx, domain.IdempotencyRecord{Key: "k1", ExpiresAt: now.Add(time.Hour)})
+		if err != nil || reserved {
+			t.Errorf("second reserve: got %v, %v, want taken", reserved, err)
+		}
+
+		record, err := r.idempotency.GetByKey(ctx, "k1")
+		if err != nil {
+			return err
+		}
+
+		if record.Completed() || record.Fingerprint != "f1" {
+			t.Errorf("got record %+v, want pending f1", record)
+		}
+
+		err = r.idempotency.Complete(ctx, domain.IdempotencyRecord{Key: "k1", Fingerprint: "other", StatusCode: 200})
+		wantCode(t, "complete with other fingerprint", err, domain.ErrCodeNotFound)
+
+		return r.idempotency.Complete(ctx, domain.IdempotencyRecord{
+			Key:          "k1",
+			Fingerprint:  "f1",
+			StatusCode:   201,
+			ContentType:  "application/json",
+			ResponseBody: []byte(`{"ok":true}`),
+			ETag:         `"2"`,
+			ExpiresAt:    now.Add(time.Hour),
+		})
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		record, err := r.idempotency.GetByKey(ctx, "k1")
+		if err != nil {
+			return err
+		}
+
+		if record.StatusCode != 201 || record.ContentType != "application/json" ||
+			string(record.ResponseBody) != `{"ok":true}` || record.ETag != `"2"` {
+			t.Errorf("got record %+v, want the completed response", record)
+		}
+
+		if record.ExpiresAt.Before(now.Add(time.Hour - time.Second)) {
+			t.Errorf("got expires at %v, want it extended by Complete", record.ExpiresAt)
+		}
+
+		err = r.idempotency.Complete(ctx, domain.IdempotencyRecord{Key: "k1", Fingerprint: "f1", StatusCode: 500})
+		wantCode(t, "complete twice", err, domain.ErrCodeNotFound)
+
+		// An expired key is gone and may be reserved again.
+		expired := domain.IdempotencyRecord{Key: "k2", Fingerprint: "f2", ExpiresAt: now.Add(-time.Minute)}
+		if _, err = r.idempotency.Reserve(ctx, expired); err != nil {
+			return err
+		}
+
+		_, err = r.idempotency.GetByKey(ctx, "k2")
+		wantCode(t, "get expired key", err, domain.ErrCodeNotFound)
+
+		retry := domain.IdempotencyRecord{Key: "k2", ExpiresAt: now.Add(time.Minute)}
+
+		reserved, err := r.idempotency.Reserve(ctx, retry)
+		if err != nil || !reserved {
+			t.Errorf("reserve over expired key: got %v, %v, want reserved", reserved, err)
+		}
+
+		return nil
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		expired := domain.IdempotencyRecord{Key: "k3", Fingerprint: "f3", ExpiresAt: now.Add(-time.Minute)}
+		if _, err := r.idempotency.Reserve(ctx, expired); err != nil {
+			return err
+		}
+
+		deleted, err := r.idempotency.DeleteExpired(ctx)
+		if err != nil {
+			return err
+		}
+
+		if deleted != 1 {
+			t.Errorf("got %d expired keys deleted, want 1", deleted)
+		}
+
+		if err = r.idempotency.Delete(ctx, "k1"); err != nil {
+			return err
+		}
+
+		_, err = r.idempotency.GetByKey(ctx, "k1")
+		wantCode(t, "get deleted key", err, domain.ErrCodeNotFound)
+
+		return nil
+	})
+}
+
+func testSnapshot(t *testing.T, b backend) {
+	deletedAt := time.Now().UTC().Truncate(time.Second)
+	createdAt := deletedAt.Add(-time.Hour)
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		created, err := r.snapshot.UpsertTeam(ctx, domain.SnapshotTeam{Name: "backend"})
+		if err != nil || !created {
+			t.Errorf("upsert new team: got %v, %v, want created", created, err)
+		}
+
+		created, err = r.snapshot.UpsertTeam(ctx, domain.SnapshotTeam{Name: "backend"})
+		if err != nil || created {
+			t.Errorf("upsert existing team: got %v, %v, want updated", created, err)
+		}
+
+		for _, user := range []domain.User{
+			{ID: "u1", Username: "name-u1", TeamName: "backend", IsActive: true},
+			{ID: "u2", Username: "name-u2", TeamName: "backend", DeletedAt: &deletedAt},
+		} {
+			if created, err = r.snapshot.UpsertUser(ctx, user); err != nil || !created {
+				t.Errorf("upsert new user %s: got %v, %v, want created", user.ID, created, err)
+			}
+		}
+
+		created, err = r.snapshot.UpsertPullRequest(ctx, domain.PullRequest{
+			ID:                "pr-1",
+			Name:              "name-pr-1",
+			AuthorID:          "u1",
+			Status:            domain.PRStatusOpen,
+			AssignedReviewers: []string{"u2"},
+			CreatedAt:         createdAt,
+			Version:           5,
+		})
+		if err != nil || !created {
+			t.Errorf("upsert new pull request: got %v, %v, want created", created, err)
+		}
+
+		return nil
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		var teams, users []string
+		var pullRequests []domain.PullRequest
+
+		if err := r.snapshot.ForEachTeam(ctx, func(team domain.SnapshotTeam) error {
+			teams = append(teams, team.Name)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if err := r.snapshot.ForEachUser(ctx, func(user domain.User) error {
+			users = append(users, user.ID)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if err := r.snapshot.ForEachPullRequest(ctx, func(pr domain.PullRequest) error {
+			pullRequests = append(pullRequests, pr)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		slices.Sort(users)
+
+		if !slices.Equal(teams, []string{"backend"}) || !slices.Equal(users, []string{"u1", "u2"}) {
+			t.Errorf("got teams %v users %v, want [backend] and soft-deleted users too", teams, users)
+		}
+
+		if len(pullRequests) != 1 || pullRequests[0].Version != 5 || !pullRequests[0].CreatedAt.Equal(createdAt) ||
+			!slices.Equal(pullRequests[0].AssignedReviewers, []string{"u2"}) {
+			t.Errorf("got pull requests %+v, want pr-1 as written", pullRequests)
+		}
+
+		for _, record := range []domain.IdempotencyRecord{
+			{Key: "pending", Fingerprint: "f1", ExpiresAt: deletedAt.Add(time.Hour)},
+			{Key: "completed", Fingerprint: "f2", ExpiresAt: deletedAt.Add(time.Hour)},
+		} {
+			if _, err := r.idempotency.Reserve(ctx, record); err != nil {
+				return err
+			}
+		}
+
+		err := r.idempotency.Complete(ctx, domain.IdempotencyRecord{
+			Key:         "completed",
+			Fingerprint: "f2",
+			StatusCode:  200,
+			ExpiresAt:   deletedAt.Add(time.Hour),
+		})
+		if err != nil {
+			return err
+		}
+
+		return r.snapshot.DeleteAll(ctx)
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		_, err := r.teams.GetTeamWithMembers(ctx, "backend")
+		wantCode(t, "get team after DeleteAll", err, domain.ErrCodeNotFound)
+
+		_, err = r.users.GetByIDWithDeleted(ctx, "u2")
+		wantCode(t, "get user after DeleteAll", err, domain.ErrCodeNotFound)
+
+		// The keys of running requests, such as the import itself, survive.
+		if _, err = r.idempotency.GetByKey(ctx, "pending"); err != nil {
+			t.Errorf("get pending key after DeleteAll: %v", err)
+		}
+
+		_, err = r.idempotency.GetByKey(ctx, "completed")
+		wantCode(t, "get completed key after DeleteAll", err, domain.ErrCodeNotFound)
+
+		return nil
+	})
+}
+
+func testRollback(t *testing.T, b backend) {
+	seed(t, b)
+
+	err := b.tryTx(func(ctx context.Context, r repos) error {
+		if err := r.teams.InsertTeam(ctx, "frontend"); err != nil {
+			return err
+		}
+
+		return r.teams.InsertTeam(ctx, "backend")
+	})
+	wantCode(t, "failed transaction", err, domain.ErrCodeTeamExists)
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		_, err := r.teams.GetTeamWithMembers(ctx, "frontend")
+		wantCode(t, "get team of rolled back transaction", err, domain.ErrCodeNotFound)
+
+		return nil
+	})
+}
+
+// mergePR merges an open pull request at the given time.
+func mergePR(t *testing.T, b backend, prID string, mergedAt time.Time) {
+	t.Helper()
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		pr, err := r.pullRequests.GetByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		pr.Status = domain.PRStatusMerged
+		pr.MergedAt = &mergedAt
+
+		return r.pullRequests.MergePullRequest(ctx, pr)
+	})
+}
+
+func testArchive(t *testing.T, b backend) {
+	seed(t, b)
+	seedPR(t, b, "pr-1", "u1", "u2", "u3")
+	seedPR(t, b, "pr-2", "u1", "u2")
+	seedPR(t, b, "pr-3", "u1", "u3")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	mergePR(t, b, "pr-1", now.Add(-3*time.Hour))
+	mergePR(t, b, "pr-2", now.Add(-2*time.Hour))
+	seedPR(t, b, "pr-4", "u1", "u2")
+
+	// Deleted pull requests are archived like the others but stay out of the hot counts.
+	b.tx(t, func(ctx context.Context, r repos) error {
+		if err := r.pullRequests.SoftDeletePullRequest(ctx, "pr-2", now); err != nil {
+			return err
+		}
+
+		return r.pullRequests.SoftDeletePullRequest(ctx, "pr-4", now)
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		merged, err := r.archive.ListMergedBefore(ctx, now.Add(-time.Hour), 10)
+		if err != nil {
+			return err
+		}
+
+		if ids := prIDs(merged); !slices.Equal(ids, []string{"pr-1", "pr-2"}) {
+			t.Errorf("got merged %v, want [pr-1 pr-2] oldest first", ids)
+		}
+
+		merged, err = r.archive.ListMergedBefore(ctx, now.Add(-time.Hour), 1)
+		if err != nil {
+			return err
+		}
+
+		if ids := prIDs(merged); !slices.Equal(ids, []string{"pr-1"}) {
+			t.Errorf("got merged %v with limit 1, want [pr-1]", ids)
+		}
+
+		if err = r.archive.ArchivePullRequests(ctx, []string{"pr-1"}); err != nil {
+			return err
+		}
+
+		return r.archive.TombstonePullRequests(ctx, []string{"pr-2"})
+	})
+
+	b.tx(t, func(ctx context.Context, r repos) error {
+		_, err := r.pullRequests.GetByID(ctx, "pr-1")
+		wantCode(t, "get archived pull request from hot tables", err, domain.ErrCodeNotFound)
+
+		archived, err := r.archive.GetByID(ctx, "pr-1")
+		if err != nil {
+			return err
+		}
+
+		reviewers := slices.Sorted(slices.Values(archived.AssignedReviewers))
+		if archived.Status != domain.PRStatusMerged || archived.ArchivedAt == nil ||
+			!slices.Equal(reviewers, []string{"u2", "u3"}) {
+			t.Errorf("got archived %+v, want MERGED pr-1 with reviewers [u2 u3]", archived)
+		}
+
+		tombstone, err := r.archive.GetByID(ctx, "pr-2")
+		if err != nil {
+			return err
+		}
+
+		if tombstone.AuthorID != "u1" || len(tombstone.AssignedReviewers) != 0 {
+			t.Errorf("got tombstone %+v, want pr-2 by u1 without reviewers", tombstone)
+		}
+
+		_, err = r.archive.GetByID(ctx, "pr-3")
+		wantCode(t, "get open pull request from archive", err, domain.ErrCodeNotFound)
+
+		reviews, err := r.archive.ListReviewPRs(ctx, "u2")
+		if err != nil {
+			return err
+		}
+
+		if ids := prIDs(reviews); !slices.Equal(ids, []string{"pr-1"}) {
+			t.Errorf("got archived reviews %v of u2, want [pr-1]", ids)
+		}
+
+		stats, err := r.archive.Stats(ctx)
+		if err != nil {
+			return err
+		}
+
+		want := []domain.ReviewerStats{{UserID: "u3", Assignments: 2}, {UserID: "u2", Assignments: 1}}
+		if stats.Open != 1 || stats.Merged != 2 || stats.Archived != 2 || !slices.Equal(stats.Reviewers, want) {
+			t.Errorf("got stats %+v, want 1 open, 2 merged and archived, reviewers %v", stats, want)
+		}
+
+		return nil
+	})
+}
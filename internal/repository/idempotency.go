@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record domain.IdempotencyRecord) (bool, error)
+	GetByKey(ctx context.Context, key string) (domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, record domain.IdempotencyRecord) error
+	Delete(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
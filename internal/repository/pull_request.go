@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
 )
@@ -9,7 +10,16 @@ import (
 type PullRequestRepository interface {
 	InsertPullRequest(ctx context.Context, pullRequest domain.PullRequest) error
 	GetByID(ctx context.Context, pullRequestID string) (domain.PullRequest, error)
+	GetByIDForUpdate(ctx context.Context, pullRequestID string) (domain.PullRequest, error)
+	GetByIDs(ctx context.Context, pullRequestIDs []string) ([]domain.PullRequest, error)
+	IncrementVersion(ctx context.Context, pullRequestID string) error
 	AddReviewer(ctx context.Context, pullRequestID string, reviewerID string) error
 	RemoveReviewer(ctx context.Context, pullRequestID string, reviewerID string) error
 	MergePullRequest(ctx context.Context, pullRequest domain.PullRequest) error
+	SoftDeletePullRequest(ctx context.Context, pullRequestID string, deletedAt time.Time) error
+	RestorePullRequest(ctx context.Context, pullRequestID string) error
+	// CountOpenByTeam counts open pull requests by the team of their author.
+	CountOpenByTeam(ctx context.Context) (map[string]int, error)
+	// CountOpenReviews counts open pull requests assigned to each of the users.
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
 }
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+// SnapshotRepository reads and writes whole records, soft-deleted ones included.
+type SnapshotRepository interface {
+	ForEachTeam(ctx context.Context, fn func(team domain.SnapshotTeam) error) error
+	ForEachUser(ctx context.Context, fn func(user domain.User) error) error
+	ForEachPullRequest(ctx context.Context, fn func(pr domain.PullRequest) error) error
+	// DeleteAll removes teams, users and pull requests with their reviewers, and
+	// the completed idempotency keys, whose saved responses refer to the removed
+	// records. Pending keys are kept for the requests still running.
+	DeleteAll(ctx context.Context) error
+	// Upsert methods report whether the record was created.
+	UpsertTeam(ctx context.Context, team domain.SnapshotTeam) (bool, error)
+	UpsertUser(ctx context.Context, user domain.User) (bool, error)
+	UpsertPullRequest(ctx context.Context, pr domain.PullRequest) (bool, error)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
 )
@@ -9,4 +10,6 @@ import (
 type TeamRepository interface {
 	InsertTeam(ctx context.Context, teamName string) error
 	GetTeamWithMembers(ctx context.Context, teamName string) (domain.TeamUpsert, error)
+	SoftDeleteTeam(ctx context.Context, teamName string, deletedAt time.Time) error
+	RestoreTeam(ctx context.Context, teamName string) error
 }
@@ -0,0 +1,145 @@
+package archiveservice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+type TxManager[E any] interface {
+	TxWrapper(ctx context.Context, fn func(ctx context.Context, tx E) error) error
+}
+
+type RepoFactory[E any] interface {
+	ArchiveRepository(exec E) repository.ArchiveRepository
+}
+
+// Exporter takes pull requests out of the database instead of the archive tables.
+type Exporter interface {
+	Export(ctx context.Context, pullRequests []domain.PullRequest) error
+}
+
+type ArchiveService[E any] struct {
+	txManager TxManager[E]
+	repoFact  RepoFactory[E]
+	retention time.Duration
+	batchSize int
+	exporter  Exporter
+}
+
+// NewArchiveService creates a service moving merged pull requests to the archive
+// tables, or to exporter when it is not nil. Exported pull requests leave
+// a tombstone without reviewers in the archive tables.
+func NewArchiveService[E any](
+	txManager TxManager[E],
+	repoFact RepoFactory[E],
+	retention time.Duration,
+	batchSize int,
+	exporter Exporter,
+) *ArchiveService[E] {
+	return &ArchiveService[E]{
+		txManager: txManager,
+		repoFact:  repoFact,
+		retention: retention,
+		batchSize: batchSize,
+		exporter:  exporter,
+	}
+}
+
+// ArchiveMerged archives pull requests merged more than the retention period ago,
+// one transaction per batch, and returns how many were archived.
+func (s *ArchiveService[E]) ArchiveMerged(ctx context.Context) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "ArchiveService.ArchiveMerged")
+	defer func() { tracing.End(span, err) }()
+
+	before := time.Now().Add(-s.retention)
+	total := 0
+
+	for {
+		archived, err := s.archiveBatch(ctx, before)
+		if err != nil {
+			return total, fmt.Errorf("archive merged pull requests: %w", err)
+		}
+
+		total += archived
+
+		if archived < s.batchSize {
+			return total, nil
+		}
+	}
+}
+
+func (s *ArchiveService[E]) archiveBatch(ctx context.Context, before time.Time) (int, error) {
+	var archived int
+
+	err := s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		localArchiveRepo := s.repoFact.ArchiveRepository(tx)
+
+		pullRequests, err := localArchiveRepo.ListMergedBefore(ctx, before, s.batchSize)
+		if err != nil {
+			return fmt.Errorf("list merged pull requests: %w", err)
+		}
+
+		if len(pullRequests) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(pullRequests))
+		for _, pr := range pullRequests {
+			ids = append(ids, pr.ID)
+		}
+
+		if s.exporter == nil {
+			err = localArchiveRepo.ArchivePullRequests(ctx, ids)
+			if err != nil {
+				return fmt.Errorf("move pull requests to archive: %w", err)
+			}
+		} else {
+			// Records are written before the tombstones commit, so a failed
+			// commit leaves duplicates in the export rather than losing data.
+			err = s.exporter.Export(ctx, pullRequests)
+			if err != nil {
+				return fmt.Errorf("export pull requests: %w", err)
+			}
+
+			err = localArchiveRepo.TombstonePullRequests(ctx, ids)
+			if err != nil {
+				return fmt.Errorf("tombstone exported pull requests: %w", err)
+			}
+		}
+
+		archived = len(ids)
+
+		return nil
+	})
+
+	return archived, err
+}
+
+// RunArchival archives merged pull requests every interval until ctx is done.
+func (s *ArchiveService[E]) RunArchival(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			archived, err := s.ArchiveMerged(ctx)
+			if err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).Error("error archiving pull requests", logging.Error(err))
+			}
+
+			if archived > 0 {
+				logging.FromContext(ctx).Info("archived pull requests", slog.Int("count", archived))
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package archiveservice_test
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	archiveservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/archive"
+	pullrequestservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/pull_request"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/jsonl"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory/memorytest"
+)
+
+type fixture struct {
+	*memorytest.Backend
+
+	pullRequests *pullrequestservice.PullRequestService[memory.Executor]
+}
+
+// newFixture creates pr-1 and pr-2 reviewed by u2 and merges pr-1.
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+
+	f := fixture{Backend: memorytest.NewBackend()}
+	f.pullRequests = pullrequestservice.NewPullRequestService[memory.Executor](
+		f.TxManager,
+		f.Store,
+		f.RepoFactory,
+		&memorytest.Publisher{},
+		memorytest.Policy(domain.DefaultAssignmentPolicy()),
+	)
+
+	f.SeedTeams(t, memorytest.Team("backend", memorytest.Member("u1", true), memorytest.Member("u2", true)))
+	f.SeedPullRequest(t, "pr-1", "u1", "u2")
+	f.SeedPullRequest(t, "pr-2", "u1", "u2")
+
+	if _, err := f.pullRequests.MergePullRequest(context.Background(), "pr-1", 0); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	return f
+}
+
+func (f fixture) archive(t *testing.T, exporter archiveservice.Exporter) {
+	t.Helper()
+
+	service := archiveservice.NewArchiveService[memory.Executor](f.TxManager, f.RepoFactory, 0, 10, exporter)
+
+	archived, err := service.ArchiveMerged(context.Background())
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	if archived != 1 {
+		t.Fatalf("archived %d pull requests, want 1", archived)
+	}
+}
+
+// checkArchived checks that pr-1 is only readable from the archive and
+// that its ID stays reserved.
+func (f fixture) checkArchived(t *testing.T, wantReviewers int) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	_, err := f.pullRequests.GetPullRequest(ctx, "pr-1", false)
+	if !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Errorf("get archived pull request: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+
+	pr, err := f.pullRequests.GetPullRequest(ctx, "pr-1", true)
+	if err != nil {
+		t.Fatalf("get archived pull request with include_archived: %v", err)
+	}
+
+	if pr.ArchivedAt == nil || len(pr.AssignedReviewers) != wantReviewers {
+		t.Errorf("got %+v, want archived pull request with %d reviewers", pr, wantReviewers)
+	}
+
+	_, err = f.pullRequests.CreatePullRequest(ctx, domain.PullRequest{ID: "pr-1", Name: "again", AuthorID: "u1"})
+	if !domain.IsErrorCode(err, domain.ErrCodePRExists) {
+		t.Errorf("reuse archived ID: got error %v, want %s", err, domain.ErrCodePRExists)
+	}
+
+	stats, err := f.pullRequests.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+
+	if stats.Open != 1 || stats.Merged != 1 || stats.Archived != 1 {
+		t.Errorf("got stats %+v, want 1 open and 1 merged and archived", stats)
+	}
+}
+
+func TestArchiveMergedToTables(t *testing.T) {
+	f := newFixture(t)
+	f.archive(t, nil)
+	f.checkArchived(t, 1)
+}
+
+func TestArchiveMergedToJSONL(t *testing.T) {
+	f := newFixture(t)
+	dir := t.TempDir()
+
+	f.archive(t, jsonl.NewExporter(dir))
+	f.checkArchived(t, 0)
+
+	files, err := filepath.Glob(filepath.Join(dir, "pull_requests-*.jsonl"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("got export files %v, %v, want one", files, err)
+	}
+
+	file, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	lines := 0
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+		lines++
+	}
+
+	if lines != 1 {
+		t.Errorf("got %d exported records, want 1", lines)
+	}
+}
@@ -0,0 +1,172 @@
+package idempotencyservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+type TxManager[E any] interface {
+	TxWrapper(ctx context.Context, fn func(ctx context.Context, tx E) error) error
+}
+
+type RepoFactory[E any] interface {
+	IdempotencyRepository(exec E) repository.IdempotencyRepository
+}
+
+type IdempotencyService[E any] struct {
+	txManager TxManager[E]
+	repoFact  RepoFactory[E]
+	readExec  E
+	ttl       time.Duration
+	// pendingLease bounds how long a reserved key blocks retries when its
+	// request never completes, e.g. after a crash.
+	pendingLease time.Duration
+}
+
+func NewIdempotencyService[E any](
+	txManager TxManager[E],
+	readExec E,
+	repoFact RepoFactory[E],
+	ttl time.Duration,
+	pendingLease time.Duration,
+) *IdempotencyService[E] {
+	return &IdempotencyService[E]{
+		txManager:    txManager,
+		repoFact:     repoFact,
+		readExec:     readExec,
+		ttl:          ttl,
+		pendingLease: pendingLease,
+	}
+}
+
+// Begin reserves the key for a request with the given fingerprint for the
+// pending lease. When the key already holds a completed response for the
+// same fingerprint it returns that record and replay is true.
+func (s *IdempotencyService[E]) Begin(
+	ctx context.Context,
+	key string,
+	fingerprint string,
+) (_ domain.IdempotencyRecord, _ bool, err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Begin")
+	defer func() { tracing.End(span, err) }()
+
+	var (
+		record domain.IdempotencyRecord
+		replay bool
+	)
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		localIdempotencyRepo := s.repoFact.IdempotencyRepository(tx)
+
+		reserved, err := localIdempotencyRepo.Reserve(ctx, domain.IdempotencyRecord{
+			Key:         key,
+			Fingerprint: fingerprint,
+			ExpiresAt:   time.Now().Add(s.pendingLease),
+		})
+		if err != nil {
+			return fmt.Errorf("reserve key: %w", err)
+		}
+
+		if reserved {
+			return nil
+		}
+
+		record, err = localIdempotencyRepo.GetByKey(ctx, key)
+		if err != nil {
+			return fmt.Errorf("get key: %w", err)
+		}
+
+		if record.Fingerprint != fingerprint {
+			return domain.NewError(
+				domain.ErrCodeIdempotencyKeyReused,
+				"idempotency key was already used for a different request",
+			)
+		}
+
+		if !record.Completed() {
+			return domain.NewError(
+				domain.ErrCodeRequestInProgress,
+				"request with this idempotency key is still in progress",
+			)
+		}
+
+		replay = true
+
+		return nil
+	})
+
+	var domainError *domain.Error
+	if errors.As(err, &domainError) && domainError.Code == domain.ErrCodeNotFound {
+		// The pending record was released between the reserve and the read.
+		return domain.IdempotencyRecord{}, false, domain.NewError(
+			domain.ErrCodeRequestInProgress,
+			"request with this idempotency key is still in progress",
+		)
+	}
+
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, fmt.Errorf("begin idempotent request: %w", err)
+	}
+
+	return record, replay, nil
+}
+
+// Complete stores the response for a reserved key and keeps it for the TTL.
+func (s *IdempotencyService[E]) Complete(ctx context.Context, record domain.IdempotencyRecord) (err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Complete")
+	defer func() { tracing.End(span, err) }()
+
+	record.ExpiresAt = time.Now().Add(s.ttl)
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		return s.repoFact.IdempotencyRepository(tx).Complete(ctx, record)
+	})
+	if err != nil {
+		return fmt.Errorf("complete idempotent request: %w", err)
+	}
+
+	return nil
+}
+
+// Release drops a reserved key so the request can be retried.
+func (s *IdempotencyService[E]) Release(ctx context.Context, key string) (err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Release")
+	defer func() { tracing.End(span, err) }()
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		return s.repoFact.IdempotencyRepository(tx).Delete(ctx, key)
+	})
+	if err != nil {
+		return fmt.Errorf("release idempotent request: %w", err)
+	}
+
+	return nil
+}
+
+// RunCleanup periodically deletes expired keys until ctx is done.
+func (s *IdempotencyService[E]) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+				_, err := s.repoFact.IdempotencyRepository(tx).DeleteExpired(ctx)
+				return err
+			})
+			if err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).Error("error deleting expired idempotency keys", logging.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package idempotencyservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	idempotencyservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/idempotency"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory/memorytest"
+)
+
+func newService(pendingLease time.Duration) *idempotencyservice.IdempotencyService[memory.Executor] {
+	b := memorytest.NewBackend()
+
+	return idempotencyservice.NewIdempotencyService[memory.Executor](
+		b.TxManager,
+		b.Store,
+		b.RepoFactory,
+		time.Hour,
+		pendingLease,
+	)
+}
+
+func TestBeginReplaysCompletedRecord(t *testing.T) {
+	ctx := context.Background()
+	s := newService(time.Minute)
+
+	if _, replay, err := s.Begin(ctx, "k", "fp"); err != nil || replay {
+		t.Fatalf("first begin: replay %v, error %v, want a reservation", replay, err)
+	}
+
+	if _, _, err := s.Begin(ctx, "k", "fp"); !domain.IsErrorCode(err, domain.ErrCodeRequestInProgress) {
+		t.Fatalf("begin of pending key: got error %v, want %s", err, domain.ErrCodeRequestInProgress)
+	}
+
+	err := s.Complete(ctx, domain.IdempotencyRecord{
+		Key:          "k",
+		Fingerprint:  "fp",
+		StatusCode:   201,
+		ContentType:  "application/json",
+		ResponseBody: []byte(`{}`),
+		ETag:         `"1"`,
+	})
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+
+	record, replay, err := s.Begin(ctx, "k", "fp")
+	if err != nil || !replay {
+		t.Fatalf("begin of completed key: replay %v, error %v, want a replay", replay, err)
+	}
+
+	if record.StatusCode != 201 || string(record.ResponseBody) != `{}` || record.ETag != `"1"` {
+		t.Errorf("got record %+v, want the completed response", record)
+	}
+
+	if _, _, err = s.Begin(ctx, "k", "other"); !domain.IsErrorCode(err, domain.ErrCodeIdempotencyKeyReused) {
+		t.Errorf("begin with another fingerprint: got error %v, want %s", err, domain.ErrCodeIdempotencyKeyReused)
+	}
+}
+
+func TestBeginTakesOverExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	s := newService(time.Millisecond)
+
+	if _, _, err := s.Begin(ctx, "k", "stale"); err != nil {
+		t.Fatalf("first begin: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, replay, err := s.Begin(ctx, "k", "retry"); err != nil || replay {
+		t.Fatalf("begin after the lease: replay %v, error %v, want a reservation", replay, err)
+	}
+
+	// The request that outlived its lease must not complete the key of the retry.
+	err := s.Complete(ctx, domain.IdempotencyRecord{Key: "k", Fingerprint: "stale", StatusCode: 201})
+	if !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Fatalf("complete of stale request: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+
+	if err = s.Complete(ctx, domain.IdempotencyRecord{Key: "k", Fingerprint: "retry", StatusCode: 201}); err != nil {
+		t.Fatalf("complete of retry: %v", err)
+	}
+}
+
+func TestReleaseAllowsRetry(t *testing.T) {
+	ctx := context.Background()
+	s := newService(time.Minute)
+
+	if _, _, err := s.Begin(ctx, "k", "fp"); err != nil {
+		t.Fatalf("first begin: %v", err)
+	}
+
+	if err := s.Release(ctx, "k"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+
+	if _, replay, err := s.Begin(ctx, "k", "other"); err != nil || replay {
+		t.Fatalf("begin after release: replay %v, error %v, want a reservation", replay, err)
+	}
+}
@@ -7,96 +7,168 @@ import (
 	"slices"
 	"time"
 
-	"github.com/jackc/pgx/v5"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/repository"
-	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
 )
 
-type TxManager interface {
-	TxWrapper(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error
+type TxManager[E any] interface {
+	TxWrapper(ctx context.Context, fn func(ctx context.Context, tx E) error) error
+	TxWrapperWithOptions(
+		ctx context.Context,
+		opts store.TxOptions,
+		fn func(ctx context.Context, tx E) error,
+	) error
 }
 
-type RepoFactory interface {
-	PullRequestRepository(exec postgres.Execer) repository.PullRequestRepository
-	UserRepository(exec postgres.Execer) repository.UserRepository
-	TeamRepository(exec postgres.Execer) repository.TeamRepository
+type RepoFactory[E any] interface {
+	PullRequestRepository(exec E) repository.PullRequestRepository
+	UserRepository(exec E) repository.UserRepository
+	TeamRepository(exec E) repository.TeamRepository
+	ArchiveRepository(exec E) repository.ArchiveRepository
 }
 
-type PullRequestService struct {
-	txManager TxManager
-	repoFact  RepoFactory
-	readExec  postgres.Execer
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...domain.Event)
 }
 
-func NewPullRequestService(
-	txManager TxManager,
-	readExec postgres.Execer,
-	repoFact RepoFactory,
-) *PullRequestService {
-	return &PullRequestService{
+// PolicySource returns the assignment policy in effect, it may change
+// between calls.
+type PolicySource interface {
+	Policy() domain.AssignmentPolicy
+}
+
+type PullRequestService[E any] struct {
+	txManager TxManager[E]
+	repoFact  RepoFactory[E]
+	readExec  E
+	publisher EventPublisher
+	policy    PolicySource
+}
+
+func NewPullRequestService[E any](
+	txManager TxManager[E],
+	readExec E,
+	repoFact RepoFactory[E],
+	publisher EventPublisher,
+	policy PolicySource,
+) *PullRequestService[E] {
+	return &PullRequestService[E]{
 		txManager: txManager,
 		repoFact:  repoFact,
 		readExec:  readExec,
+		publisher: publisher,
+		policy:    policy,
 	}
 }
 
-func shuffleTeamMembers(teamMembers []domain.TeamMember) []domain.TeamMember {
-	res := make([]domain.TeamMember, len(teamMembers))
-	copy(res, teamMembers)
+// rankCandidates orders the candidate reviewers according to the policy
+// and drops those who already have MaxOpenReviews open reviews.
+func rankCandidates(
+	ctx context.Context,
+	pullRequestRepo repository.PullRequestRepository,
+	policy domain.AssignmentPolicy,
+	candidates []string,
+) ([]string, error) {
+	res := slices.Clone(candidates)
 
+	// Shuffling first also breaks ties between equally loaded candidates.
 	rand.Shuffle(len(res), func(i, j int) {
 		res[i], res[j] = res[j], res[i]
 	})
 
-	return res
+	if policy.Strategy != domain.AssignmentStrategyLeastLoaded && policy.MaxOpenReviews == 0 {
+		return res, nil
+	}
+
+	loads, err := pullRequestRepo.CountOpenReviews(ctx, res)
+	if err != nil {
+		return nil, fmt.Errorf("count open reviews: %w", err)
+	}
+
+	if policy.MaxOpenReviews > 0 {
+		res = slices.DeleteFunc(res, func(userID string) bool {
+			return loads[userID] >= policy.MaxOpenReviews
+		})
+	}
+
+	if policy.Strategy == domain.AssignmentStrategyLeastLoaded {
+		slices.SortStableFunc(res, func(a, b string) int {
+			return loads[a] - loads[b]
+		})
+	}
+
+	return res, nil
+}
+
+// checkVersion implements If-Match semantics: zero expected version matches any version.
+func checkVersion(pr domain.PullRequest, expectedVersion int64) error {
+	if expectedVersion != 0 && pr.Version != expectedVersion {
+		return domain.NewError(
+			domain.ErrCodePreconditionFailed,
+			fmt.Sprintf("pull request %s has version %d, expected %d", pr.ID, pr.Version, expectedVersion),
+		)
+	}
+
+	return nil
 }
 
-func (s *PullRequestService) getTeamByUserID(
+// getUserWithTeam reads the user and their team outside of transactions,
+// so the lookups may be served from the cache.
+func (s *PullRequestService[E]) getUserWithTeam(
 	ctx context.Context,
-	exec postgres.Execer,
 	userID string,
-) (domain.TeamUpsert, error) {
-	localUserRepo := s.repoFact.UserRepository(exec)
-
-	pullRequestAuthor, err := localUserRepo.GetByID(ctx, userID)
+) (domain.User, domain.TeamUpsert, error) {
+	user, err := s.repoFact.UserRepository(s.readExec).GetByID(ctx, userID)
 	if err != nil {
-		return domain.TeamUpsert{}, fmt.Errorf("get author: %w", err)
+		return domain.User{}, domain.TeamUpsert{}, fmt.Errorf("get user: %w", err)
 	}
 
-	localTeamRepo := s.repoFact.TeamRepository(exec)
-
-	team, err := localTeamRepo.GetTeamWithMembers(ctx, pullRequestAuthor.TeamName)
+	team, err := s.repoFact.TeamRepository(s.readExec).GetTeamWithMembers(ctx, user.TeamName)
 	if err != nil {
-		return domain.TeamUpsert{}, fmt.Errorf("get team: %w", err)
+		return domain.User{}, domain.TeamUpsert{}, fmt.Errorf("get team: %w", err)
 	}
 
-	return team, nil
+	return user, team, nil
 }
 
-func (s *PullRequestService) assignReviewers(ctx context.Context, exec postgres.Execer, pr domain.PullRequest) error {
-	team, err := s.getTeamByUserID(ctx, exec, pr.AuthorID)
-	if err != nil {
-		return fmt.Errorf("get team: %w", err)
+func (s *PullRequestService[E]) assignReviewers(
+	ctx context.Context,
+	exec E,
+	pr domain.PullRequest,
+	team domain.TeamUpsert,
+) error {
+	policy := s.policy.Policy()
+
+	var candidates []string
+
+	for _, member := range team.Members {
+		if member.UserID != pr.AuthorID && member.IsActive {
+			candidates = append(candidates, member.UserID)
+		}
 	}
 
 	localPullRequestRepo := s.repoFact.PullRequestRepository(exec)
 
-	teamMembers := shuffleTeamMembers(team.Members)
+	candidates, err := rankCandidates(ctx, localPullRequestRepo, policy, candidates)
+	if err != nil {
+		return err
+	}
+
 	assignedCounter := len(pr.AssignedReviewers)
 
-	for _, member := range teamMembers {
-		if member.UserID != pr.AuthorID && member.IsActive {
-			err = localPullRequestRepo.AddReviewer(ctx, pr.ID, member.UserID)
-			if err != nil {
-				return fmt.Errorf("assign reviewer: %w", err)
-			}
-
-			assignedCounter++
-			if assignedCounter >= domain.MaxAssignedReviewers {
-				break
-			}
+	for _, reviewerID := range candidates {
+		if assignedCounter >= policy.ReviewersPerPR {
+			break
+		}
+
+		err = localPullRequestRepo.AddReviewer(ctx, pr.ID, reviewerID)
+		if err != nil {
+			return fmt.Errorf("assign reviewer: %w", err)
 		}
+
+		assignedCounter++
 	}
 
 	return nil
@@ -105,15 +177,33 @@ func (s *PullRequestService) assignReviewers(ctx context.Context, exec postgres.
 // CreatePullRequest may be used for
 // POST /pullRequest/create
 // creates pull request.
-func (s *PullRequestService) CreatePullRequest(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error) {
+func (s *PullRequestService[E]) CreatePullRequest(
+	ctx context.Context,
+	pr domain.PullRequest,
+) (_ domain.PullRequest, err error) {
+	ctx, span := tracing.Start(ctx, "PullRequestService.CreatePullRequest")
+	defer func() { tracing.End(span, err) }()
+
 	var dbPullRequest domain.PullRequest
 
-	err := s.txManager.TxWrapper(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		localUserRepo := s.repoFact.UserRepository(tx)
+	author, team, err := s.getUserWithTeam(ctx, pr.AuthorID)
+	if err != nil {
+		return dbPullRequest, fmt.Errorf("create pull request: get author: %w", err)
+	}
 
-		_, err := localUserRepo.GetByID(ctx, pr.AuthorID)
-		if err != nil {
-			return fmt.Errorf("get author: %w", err)
+	// Serializable isolation keeps reviewer selection consistent
+	// when several pull requests are created for the same team at once.
+	opts := store.TxOptions{IsoLevel: store.IsoLevelSerializable}
+
+	err = s.txManager.TxWrapperWithOptions(ctx, opts, func(ctx context.Context, tx E) error {
+		// Archived pull requests keep their IDs reserved.
+		_, err := s.repoFact.ArchiveRepository(tx).GetByID(ctx, pr.ID)
+		if err == nil {
+			return domain.NewError(domain.ErrCodePRExists, fmt.Sprintf("pull request %s already exists", pr.ID))
+		}
+
+		if !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+			return fmt.Errorf("get archived pull request: %w", err)
 		}
 
 		localPullRequestRepo := s.repoFact.PullRequestRepository(tx)
@@ -123,7 +213,7 @@ func (s *PullRequestService) CreatePullRequest(ctx context.Context, pr domain.Pu
 			return fmt.Errorf("insert pull request: %w", err)
 		}
 
-		err = s.assignReviewers(ctx, tx, pr)
+		err = s.assignReviewers(ctx, tx, pr, team)
 		if err != nil {
 			return fmt.Errorf("assign reviewers: %w", err)
 		}
@@ -140,28 +230,102 @@ func (s *PullRequestService) CreatePullRequest(ctx context.Context, pr domain.Pu
 		return dbPullRequest, fmt.Errorf("create pull request: %w", err)
 	}
 
+	now := time.Now()
+	events := make([]domain.Event, 0, len(dbPullRequest.AssignedReviewers))
+	for _, reviewerID := range dbPullRequest.AssignedReviewers {
+		events = append(events, domain.Event{
+			Type:          domain.EventReviewerAssigned,
+			PullRequestID: dbPullRequest.ID,
+			UserID:        reviewerID,
+			Reviewers:     dbPullRequest.AssignedReviewers,
+			TeamName:      author.TeamName,
+			OccurredAt:    now,
+		})
+	}
+	s.publisher.Publish(ctx, events...)
+
 	return dbPullRequest, nil
 }
 
+// GetPullRequest may be used for
+// GET /pullRequest/get
+// returns pull request with reviewers, looking into the archive if asked to.
+func (s *PullRequestService[E]) GetPullRequest(
+	ctx context.Context,
+	prID string,
+	includeArchived bool,
+) (_ domain.PullRequest, err error) {
+	ctx, span := tracing.Start(ctx, "PullRequestService.GetPullRequest")
+	defer func() { tracing.End(span, err) }()
+
+	localPullRequestRepo := s.repoFact.PullRequestRepository(s.readExec)
+
+	pullRequest, err := localPullRequestRepo.GetByID(ctx, prID)
+	if err != nil && includeArchived && domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		pullRequest, err = s.repoFact.ArchiveRepository(s.readExec).GetByID(ctx, prID)
+	}
+
+	if err != nil {
+		return domain.PullRequest{}, fmt.Errorf("service get pull request: %w", err)
+	}
+
+	return pullRequest, nil
+}
+
+// GetStats may be used for
+// GET /pullRequest/stats
+// counts pull requests and review assignments, archived ones included.
+func (s *PullRequestService[E]) GetStats(ctx context.Context) (domain.PullRequestStats, error) {
+	stats, err := s.repoFact.ArchiveRepository(s.readExec).Stats(ctx)
+	if err != nil {
+		return domain.PullRequestStats{}, fmt.Errorf("service get stats: %w", err)
+	}
+
+	return stats, nil
+}
+
 // MergePullRequest may be used for
 // POST /pullRequest/merge
 // merges pull request.
-func (s *PullRequestService) MergePullRequest(ctx context.Context, prID string) (domain.PullRequest, error) {
-	var pullRequest domain.PullRequest
-
-	err := s.txManager.TxWrapper(ctx, func(ctx context.Context, tx pgx.Tx) error {
+func (s *PullRequestService[E]) MergePullRequest(
+	ctx context.Context,
+	prID string,
+	expectedVersion int64,
+) (_ domain.PullRequest, err error) {
+	ctx, span := tracing.Start(ctx, "PullRequestService.MergePullRequest")
+	defer func() { tracing.End(span, err) }()
+
+	var (
+		pullRequest domain.PullRequest
+		author      domain.User
+		merged      bool
+	)
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
 		localPullRequestRepo := s.repoFact.PullRequestRepository(tx)
 
 		var err error
-		pullRequest, err = localPullRequestRepo.GetByID(ctx, prID)
+		pullRequest, err = localPullRequestRepo.GetByIDForUpdate(ctx, prID)
 		if err != nil {
 			return fmt.Errorf("get pull request: %w", err)
 		}
 
+		// A retry of a merge that succeeded returns the merged pull request
+		// even though its version has moved past If-Match.
 		if pullRequest.Status == domain.PRStatusMerged {
 			return nil
 		}
 
+		if err = checkVersion(pullRequest, expectedVersion); err != nil {
+			return err
+		}
+
+		// Pull requests of deleted authors can still be merged.
+		author, err = s.repoFact.UserRepository(tx).GetByIDWithDeleted(ctx, pullRequest.AuthorID)
+		if err != nil {
+			return fmt.Errorf("get author: %w", err)
+		}
+
 		now := time.Now()
 		pullRequest.MergedAt = &now
 		pullRequest.Status = domain.PRStatusMerged
@@ -170,6 +334,9 @@ func (s *PullRequestService) MergePullRequest(ctx context.Context, prID string)
 			return fmt.Errorf("service merge pull request: %w", err)
 		}
 
+		pullRequest.Version++
+		merged = true
+
 		return nil
 	})
 
@@ -177,65 +344,96 @@ func (s *PullRequestService) MergePullRequest(ctx context.Context, prID string)
 		return pullRequest, fmt.Errorf("merge pull request: %w", err)
 	}
 
+	if merged {
+		s.publisher.Publish(ctx, domain.Event{
+			Type:          domain.EventPullRequestMerged,
+			PullRequestID: pullRequest.ID,
+			UserID:        pullRequest.AuthorID,
+			Reviewers:     pullRequest.AssignedReviewers,
+			TeamName:      author.TeamName,
+			OccurredAt:    *pullRequest.MergedAt,
+		})
+	}
+
 	return pullRequest, nil
 }
 
-func (s *PullRequestService) reassignRewiewer(
+func (s *PullRequestService[E]) reassignRewiewer(
 	ctx context.Context,
-	tx pgx.Tx,
 	pr domain.PullRequest,
 	prID string,
 	oldReviewerID string,
+	team domain.TeamUpsert,
 	localPullRequestRepo repository.PullRequestRepository,
 ) (string, error) {
-	team, err := s.getTeamByUserID(ctx, tx, oldReviewerID)
-	if err != nil {
-		return "", fmt.Errorf("get team: %w", err)
-	}
+	var candidates []string
 
 	for _, member := range team.Members {
-		if member.IsActive && member.UserID != oldReviewerID && member.UserID != pr.AuthorID {
-			isInReviewers := slices.Contains(pr.AssignedReviewers, member.UserID)
-			if !isInReviewers {
-				err = localPullRequestRepo.AddReviewer(ctx, prID, member.UserID)
-				if err != nil {
-					return "", fmt.Errorf("assign reviewer: %w", err)
-				}
-
-				return member.UserID, nil
-			}
+		if member.IsActive && member.UserID != oldReviewerID && member.UserID != pr.AuthorID &&
+			!slices.Contains(pr.AssignedReviewers, member.UserID) {
+			candidates = append(candidates, member.UserID)
 		}
 	}
 
-	return "", domain.NewError(domain.ErrCodeNoCandidate, "no active replacement candidate in team")
+	candidates, err := rankCandidates(ctx, localPullRequestRepo, s.policy.Policy(), candidates)
+	if err != nil {
+		return "", err
+	}
+
+	if len(candidates) == 0 {
+		return "", domain.NewError(domain.ErrCodeNoCandidate, "no active replacement candidate in team")
+	}
+
+	err = localPullRequestRepo.AddReviewer(ctx, prID, candidates[0])
+	if err != nil {
+		return "", fmt.Errorf("assign reviewer: %w", err)
+	}
+
+	return candidates[0], nil
 }
 
 // ReassignPullRequest merges pull request
 // POST /pullRequest/reassign
 // reassigns pull request.
-func (s *PullRequestService) ReassignPullRequest(
+func (s *PullRequestService[E]) ReassignPullRequest(
 	ctx context.Context,
 	prID string,
 	oldReviewerID string,
-) (domain.PullRequest, string, error) {
+	expectedVersion int64,
+) (_ domain.PullRequest, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "PullRequestService.ReassignPullRequest")
+	defer func() { tracing.End(span, err) }()
+
 	var pullRequest domain.PullRequest
 	var reassignedUserID string
 
-	err := s.txManager.TxWrapper(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		localUserRepo := s.repoFact.UserRepository(tx)
+	// Deleted reviewers keep their assignments and may be replaced.
+	oldReviewer, err := s.repoFact.UserRepository(s.readExec).GetByIDWithDeleted(ctx, oldReviewerID)
+	if err != nil {
+		return pullRequest, reassignedUserID, fmt.Errorf("service reassign pull request: get old reviewer: %w", err)
+	}
 
-		_, err := localUserRepo.GetByID(ctx, oldReviewerID)
-		if err != nil {
-			return fmt.Errorf("get old reviewer: %w", err)
-		}
+	team, err := s.repoFact.TeamRepository(s.readExec).GetTeamWithMembers(ctx, oldReviewer.TeamName)
+	if domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		err = domain.NewError(domain.ErrCodeNoCandidate, "team of the old reviewer is deleted")
+	}
+
+	if err != nil {
+		return pullRequest, reassignedUserID, fmt.Errorf("service reassign pull request: get team: %w", err)
+	}
 
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
 		localPullRequestRepo := s.repoFact.PullRequestRepository(tx)
 
-		pr, err := localPullRequestRepo.GetByID(ctx, prID)
+		pr, err := localPullRequestRepo.GetByIDForUpdate(ctx, prID)
 		if err != nil {
 			return fmt.Errorf("get pull request: %w", err)
 		}
 
+		if err = checkVersion(pr, expectedVersion); err != nil {
+			return err
+		}
+
 		if pr.Status == domain.PRStatusMerged {
 			return domain.NewError(domain.ErrCodePRMerged, "cannot reassign on merged PR")
 		}
@@ -245,11 +443,16 @@ func (s *PullRequestService) ReassignPullRequest(
 			return fmt.Errorf("remove reviewer: %w", err)
 		}
 
-		reassignedUserID, err = s.reassignRewiewer(ctx, tx, pr, prID, oldReviewerID, localPullRequestRepo)
+		reassignedUserID, err = s.reassignRewiewer(ctx, pr, prID, oldReviewerID, team, localPullRequestRepo)
 		if err != nil {
 			return fmt.Errorf("reassign reviewer: %w", err)
 		}
 
+		err = localPullRequestRepo.IncrementVersion(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("increment version: %w", err)
+		}
+
 		pullRequest, err = localPullRequestRepo.GetByID(ctx, prID)
 		if err != nil {
 			return fmt.Errorf("get pull request: %w", err)
@@ -262,5 +465,80 @@ func (s *PullRequestService) ReassignPullRequest(
 		return pullRequest, reassignedUserID, fmt.Errorf("service reassign pull request: %w", err)
 	}
 
+	s.publisher.Publish(ctx, domain.Event{
+		Type:          domain.EventReviewerReassigned,
+		PullRequestID: pullRequest.ID,
+		UserID:        reassignedUserID,
+		OldUserID:     oldReviewerID,
+		Reviewers:     pullRequest.AssignedReviewers,
+		TeamName:      oldReviewer.TeamName,
+		OccurredAt:    time.Now(),
+	})
+
 	return pullRequest, reassignedUserID, nil
 }
+
+// DeletePullRequest may be used for
+// POST /pullRequest/delete
+// hides pull request until it is restored.
+func (s *PullRequestService[E]) DeletePullRequest(ctx context.Context, prID string) (err error) {
+	ctx, span := tracing.Start(ctx, "PullRequestService.DeletePullRequest")
+	defer func() { tracing.End(span, err) }()
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		return s.repoFact.PullRequestRepository(tx).SoftDeletePullRequest(ctx, prID, time.Now())
+	})
+
+	if err != nil {
+		return fmt.Errorf("service delete pull request: %w", err)
+	}
+
+	return nil
+}
+
+// RestorePullRequest may be used for
+// POST /pullRequest/restore
+// restores deleted pull request.
+func (s *PullRequestService[E]) RestorePullRequest(ctx context.Context, prID string) (_ domain.PullRequest, err error) {
+	ctx, span := tracing.Start(ctx, "PullRequestService.RestorePullRequest")
+	defer func() { tracing.End(span, err) }()
+
+	var pullRequest domain.PullRequest
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		localPullRequestRepo := s.repoFact.PullRequestRepository(tx)
+
+		err := localPullRequestRepo.RestorePullRequest(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("restore pull request: %w", err)
+		}
+
+		pullRequest, err = localPullRequestRepo.GetByID(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("get pull request: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return domain.PullRequest{}, fmt.Errorf("service restore pull request: %w", err)
+	}
+
+	return pullRequest, nil
+}
+
+// CountOpenByTeam may be used for
+// GET /metrics
+// counts open pull requests by the team of their author.
+func (s *PullRequestService[E]) CountOpenByTeam(ctx context.Context) (_ map[string]int, err error) {
+	ctx, span := tracing.Start(ctx, "PullRequestService.CountOpenByTeam")
+	defer func() { tracing.End(span, err) }()
+
+	counts, err := s.repoFact.PullRequestRepository(s.readExec).CountOpenByTeam(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service count open pull requests: %w", err)
+	}
+
+	return counts, nil
+}
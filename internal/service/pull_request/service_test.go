@@ -0,0 +1,463 @@
+package pullrequestservice_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	pullrequestservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/pull_request"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/cache"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory/memorytest"
+)
+
+type fixture struct {
+	*memorytest.Backend
+
+	service   *pullrequestservice.PullRequestService[memory.Executor]
+	publisher *memorytest.Publisher
+}
+
+func newFixture(t *testing.T, teams ...domain.TeamUpsert) fixture {
+	t.Helper()
+
+	return newPolicyFixture(t, domain.DefaultAssignmentPolicy(), teams...)
+}
+
+func newPolicyFixture(t *testing.T, policy domain.AssignmentPolicy, teams ...domain.TeamUpsert) fixture {
+	t.Helper()
+
+	f := fixture{Backend: memorytest.NewBackend(), publisher: &memorytest.Publisher{}}
+	f.service = pullrequestservice.NewPullRequestService[memory.Executor](
+		f.TxManager,
+		f.Store,
+		f.RepoFactory,
+		f.publisher,
+		memorytest.Policy(policy),
+	)
+	f.SeedTeams(t, teams...)
+
+	return f
+}
+
+func TestCreatePullRequestAssignsReviewers(t *testing.T) {
+	tests := []struct {
+		name      string
+		members   []domain.TeamMember
+		allowed   []string
+		wantCount int
+	}{
+		{
+			name: "two of active teammates",
+			members: []domain.TeamMember{
+				memorytest.Member("u1", true),
+				memorytest.Member("u2", true),
+				memorytest.Member("u3", true),
+				memorytest.Member("u4", true),
+			},
+			allowed:   []string{"u2", "u3", "u4"},
+			wantCount: 2,
+		},
+		{
+			name: "inactive teammates are skipped",
+			members: []domain.TeamMember{
+				memorytest.Member("u1", true),
+				memorytest.Member("u2", false),
+				memorytest.Member("u3", true),
+			},
+			allowed:   []string{"u3"},
+			wantCount: 1,
+		},
+		{
+			name:      "author alone in team",
+			members:   []domain.TeamMember{memorytest.Member("u1", true)},
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, memorytest.Team("backend", tt.members...))
+
+			pr, err := f.service.CreatePullRequest(
+				context.Background(),
+				domain.PullRequest{ID: "pr-1", Name: "pr-1", AuthorID: "u1"},
+			)
+			if err != nil {
+				t.Fatalf("create pull request: %v", err)
+			}
+
+			if len(pr.AssignedReviewers) != tt.wantCount {
+				t.Fatalf("got reviewers %v, want %d of them", pr.AssignedReviewers, tt.wantCount)
+			}
+
+			for _, reviewerID := range pr.AssignedReviewers {
+				if !slices.Contains(tt.allowed, reviewerID) {
+					t.Errorf("reviewer %s is not one of %v", reviewerID, tt.allowed)
+				}
+			}
+
+			if pr.Status != domain.PRStatusOpen || pr.Version != 1 {
+				t.Errorf("got status %s version %d, want OPEN version 1", pr.Status, pr.Version)
+			}
+
+			if got := f.publisher.Count(domain.EventReviewerAssigned); got != tt.wantCount {
+				t.Errorf("got %d assigned events, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestAssignmentPolicy(t *testing.T) {
+	members := []domain.TeamMember{
+		memorytest.Member("u1", true),
+		memorytest.Member("u2", true),
+		memorytest.Member("u3", true),
+		memorytest.Member("u4", true),
+		memorytest.Member("u5", true),
+	}
+
+	tests := []struct {
+		name   string
+		policy domain.AssignmentPolicy
+		want   []string
+	}{
+		{
+			name:   "reviewers per pr",
+			policy: domain.AssignmentPolicy{ReviewersPerPR: 4, Strategy: domain.AssignmentStrategyRandom},
+			want:   []string{"u2", "u3", "u4", "u5"},
+		},
+		{
+			name:   "least loaded first",
+			policy: domain.AssignmentPolicy{ReviewersPerPR: 2, Strategy: domain.AssignmentStrategyLeastLoaded},
+			want:   []string{"u4", "u5"},
+		},
+		{
+			name: "max open reviews",
+			policy: domain.AssignmentPolicy{
+				ReviewersPerPR: 3,
+				Strategy:       domain.AssignmentStrategyRandom,
+				MaxOpenReviews: 1,
+			},
+			want: []string{"u4", "u5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPolicyFixture(t, tt.policy, memorytest.Team("backend", members...))
+			// u2 and u3 review one open pull request each, merged ones do not count.
+			f.SeedPullRequest(t, "open", "u1", "u2", "u3")
+			f.SeedPullRequest(t, "merged", "u1", "u4", "u5")
+
+			if _, err := f.service.MergePullRequest(context.Background(), "merged", 0); err != nil {
+				t.Fatalf("merge: %v", err)
+			}
+
+			pr, err := f.service.CreatePullRequest(
+				context.Background(),
+				domain.PullRequest{ID: "pr-1", Name: "pr-1", AuthorID: "u1"},
+			)
+			if err != nil {
+				t.Fatalf("create pull request: %v", err)
+			}
+
+			got := slices.Sorted(slices.Values(pr.AssignedReviewers))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got reviewers %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReassignRespectsPolicy(t *testing.T) {
+	f := newPolicyFixture(t,
+		domain.AssignmentPolicy{ReviewersPerPR: 2, Strategy: domain.AssignmentStrategyLeastLoaded, MaxOpenReviews: 1},
+		memorytest.Team("backend",
+			memorytest.Member("u1", true),
+			memorytest.Member("u2", true),
+			memorytest.Member("u3", true),
+			memorytest.Member("u4", true),
+		),
+	)
+	f.SeedPullRequest(t, "pr-1", "u1", "u2")
+	f.SeedPullRequest(t, "pr-2", "u1", "u3")
+
+	_, replacedBy, err := f.service.ReassignPullRequest(context.Background(), "pr-1", "u2", 0)
+	if err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+
+	if replacedBy != "u4" {
+		t.Errorf("got replacement %s, want u4 since u3 is at max open reviews", replacedBy)
+	}
+
+	// u2 already reviews pr-3, u4 took pr-1 and is at the cap.
+	f.SeedPullRequest(t, "pr-3", "u1", "u2", "u3")
+
+	_, _, err = f.service.ReassignPullRequest(context.Background(), "pr-3", "u3", 0)
+	if !domain.IsErrorCode(err, domain.ErrCodeNoCandidate) {
+		t.Errorf("got error %v, want %s when every candidate is at max open reviews", err, domain.ErrCodeNoCandidate)
+	}
+}
+
+func TestCreatePullRequestErrors(t *testing.T) {
+	f := newFixture(t, memorytest.Team("backend", memorytest.Member("u1", true), memorytest.Member("u2", true)))
+	f.SeedPullRequest(t, "pr-1", "u1")
+
+	tests := []struct {
+		name     string
+		pr       domain.PullRequest
+		wantCode domain.ErrorCode
+	}{
+		{
+			name:     "duplicate id",
+			pr:       domain.PullRequest{ID: "pr-1", Name: "again", AuthorID: "u2"},
+			wantCode: domain.ErrCodePRExists,
+		},
+		{
+			name:     "unknown author",
+			pr:       domain.PullRequest{ID: "pr-2", Name: "pr-2", AuthorID: "nobody"},
+			wantCode: domain.ErrCodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.CreatePullRequest(context.Background(), tt.pr)
+			if !domain.IsErrorCode(err, tt.wantCode) {
+				t.Fatalf("got error %v, want %s", err, tt.wantCode)
+			}
+
+			if got := f.publisher.Count(domain.EventReviewerAssigned); got != 0 {
+				t.Errorf("got %d assigned events, want none", got)
+			}
+		})
+	}
+}
+
+func TestReassignPullRequest(t *testing.T) {
+	f := newFixture(t, memorytest.Team("backend",
+		memorytest.Member("u1", true),
+		memorytest.Member("u2", true),
+		memorytest.Member("u3", true),
+		memorytest.Member("u4", true),
+		memorytest.Member("u5", false),
+	))
+	f.SeedPullRequest(t, "pr-1", "u1", "u2", "u3")
+
+	pr, replacedBy, err := f.service.ReassignPullRequest(context.Background(), "pr-1", "u2", 1)
+	if err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+
+	if replacedBy != "u4" {
+		t.Errorf("got replacement %s, want the only free active teammate u4", replacedBy)
+	}
+
+	if !slices.Equal(pr.AssignedReviewers, []string{"u3", "u4"}) {
+		t.Errorf("got reviewers %v, want [u3 u4]", pr.AssignedReviewers)
+	}
+
+	if pr.Version != 2 {
+		t.Errorf("got version %d, want 2", pr.Version)
+	}
+
+	if got := f.publisher.Count(domain.EventReviewerReassigned); got != 1 {
+		t.Errorf("got %d reassigned events, want 1", got)
+	}
+}
+
+func TestReassignPullRequestErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		reviewer string
+		version  int64
+		merge    bool
+		wantCode domain.ErrorCode
+	}{
+		{name: "no candidate", reviewer: "u2", wantCode: domain.ErrCodeNoCandidate},
+		{name: "not assigned", reviewer: "u4", wantCode: domain.ErrCodeNotAssigned},
+		{name: "unknown reviewer", reviewer: "nobody", wantCode: domain.ErrCodeNotFound},
+		{name: "stale version", reviewer: "u2", version: 7, wantCode: domain.ErrCodePreconditionFailed},
+		{name: "merged", reviewer: "u2", merge: true, wantCode: domain.ErrCodePRMerged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newFixture(t, memorytest.Team("backend",
+				memorytest.Member("u1", true),
+				memorytest.Member("u2", true),
+				memorytest.Member("u3", true),
+				memorytest.Member("u4", false),
+			))
+			f.SeedPullRequest(t, "pr-1", "u1", "u2", "u3")
+
+			if tt.merge {
+				if _, err := f.service.MergePullRequest(ctx, "pr-1", 0); err != nil {
+					t.Fatalf("merge: %v", err)
+				}
+			}
+
+			_, _, err := f.service.ReassignPullRequest(ctx, "pr-1", tt.reviewer, tt.version)
+			if !domain.IsErrorCode(err, tt.wantCode) {
+				t.Fatalf("got error %v, want %s", err, tt.wantCode)
+			}
+
+			// A failed reassignment must leave the reviewers as they were.
+			pr, err := f.service.GetPullRequest(ctx, "pr-1", false)
+			if err != nil {
+				t.Fatalf("get pull request: %v", err)
+			}
+
+			if !slices.Equal(pr.AssignedReviewers, []string{"u2", "u3"}) {
+				t.Errorf("got reviewers %v after failed reassign, want [u2 u3]", pr.AssignedReviewers)
+			}
+
+			if got := f.publisher.Count(domain.EventReviewerReassigned); got != 0 {
+				t.Errorf("got %d reassigned events, want none", got)
+			}
+		})
+	}
+}
+
+func TestLookupsUseCache(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, memorytest.Team("backend",
+		memorytest.Member("u1", true),
+		memorytest.Member("u2", true),
+		memorytest.Member("u3", true),
+		memorytest.Member("u4", true),
+	))
+
+	teamCache := cache.New(16, time.Minute)
+	service := pullrequestservice.NewPullRequestService[memory.Executor](
+		f.TxManager,
+		f.Store,
+		cache.NewRepoFactory[memory.Executor](f.RepoFactory, f.Store, teamCache, nil),
+		f.publisher,
+		memorytest.Policy(domain.DefaultAssignmentPolicy()),
+	)
+
+	for _, prID := range []string{"pr-1", "pr-2"} {
+		if _, err := service.CreatePullRequest(ctx, domain.PullRequest{ID: prID, AuthorID: "u1"}); err != nil {
+			t.Fatalf("create %s: %v", prID, err)
+		}
+	}
+
+	f.SeedPullRequest(t, "pr-3", "u1", "u2")
+
+	if _, _, err := service.ReassignPullRequest(ctx, "pr-3", "u2", 0); err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+
+	// The old reviewer may be deleted, so it is read past the cache; its team is not.
+	stats := teamCache.Stats()
+	if stats.TeamHits != 2 || stats.TeamMisses != 1 || stats.UserHits != 1 || stats.UserMisses != 1 {
+		t.Errorf("got stats %+v, want team hits 2, misses 1 and user hits 1, misses 1", stats)
+	}
+}
+
+func TestMergePullRequest(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, memorytest.Team("backend", memorytest.Member("u1", true), memorytest.Member("u2", true)))
+	f.SeedPullRequest(t, "pr-1", "u1")
+
+	_, err := f.service.MergePullRequest(ctx, "pr-1", 7)
+	if !domain.IsErrorCode(err, domain.ErrCodePreconditionFailed) {
+		t.Errorf("merge with stale version: got error %v, want %s", err, domain.ErrCodePreconditionFailed)
+	}
+
+	first, err := f.service.MergePullRequest(ctx, "pr-1", 1)
+	if err != nil {
+		t.Fatalf("first merge: %v", err)
+	}
+
+	if first.Status != domain.PRStatusMerged || first.MergedAt == nil || first.Version != 2 {
+		t.Fatalf("got status %s merged at %v version %d, want MERGED version 2",
+			first.Status, first.MergedAt, first.Version)
+	}
+
+	// A retry carries the ETag of the open pull request, which the merge has moved past.
+	second, err := f.service.MergePullRequest(ctx, "pr-1", 1)
+	if err != nil {
+		t.Fatalf("retried merge: %v", err)
+	}
+
+	if second.Version != first.Version || !second.MergedAt.Equal(*first.MergedAt) {
+		t.Errorf("retried merge changed the pull request: version %d merged at %v, want version %d merged at %v",
+			second.Version, second.MergedAt, first.Version, first.MergedAt)
+	}
+
+	if got := f.publisher.Count(domain.EventPullRequestMerged); got != 1 {
+		t.Errorf("got %d merged events, want 1", got)
+	}
+
+	_, err = f.service.MergePullRequest(ctx, "missing", 0)
+	if !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Errorf("merge of missing pull request: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+}
+
+func TestDeleteAndRestorePullRequest(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, memorytest.Team("backend", memorytest.Member("u1", true), memorytest.Member("u2", true)))
+	f.SeedPullRequest(t, "pr-1", "u1", "u2")
+
+	before, err := f.service.GetPullRequest(ctx, "pr-1", false)
+	if err != nil {
+		t.Fatalf("get pull request: %v", err)
+	}
+
+	if err = f.service.DeletePullRequest(ctx, "pr-1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if _, err = f.service.GetPullRequest(ctx, "pr-1", false); !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Fatalf("get deleted pull request: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+
+	_, err = f.service.CreatePullRequest(ctx, domain.PullRequest{ID: "pr-1", AuthorID: "u1"})
+	if !domain.IsErrorCode(err, domain.ErrCodePRExists) {
+		t.Errorf("create over deleted pull request: got error %v, want %s", err, domain.ErrCodePRExists)
+	}
+
+	pr, err := f.service.RestorePullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	if pr.DeletedAt != nil || pr.Version <= before.Version || !slices.Equal(pr.AssignedReviewers, []string{"u2"}) {
+		t.Errorf("got restored %+v, want live pull request reviewed by u2 past version %d", pr, before.Version)
+	}
+
+	if _, err = f.service.RestorePullRequest(ctx, "pr-1"); !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Errorf("restore of live pull request: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+}
+
+func TestReassignDeletedReviewer(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, memorytest.Team("backend",
+		memorytest.Member("u1", true),
+		memorytest.Member("u2", true),
+		memorytest.Member("u3", true),
+	))
+	f.SeedPullRequest(t, "pr-1", "u1", "u2")
+
+	f.Seed(t, func(ctx context.Context, tx memory.Executor) error {
+		return f.RepoFactory.UserRepository(tx).SoftDeleteUser(ctx, "u2", time.Now())
+	})
+
+	_, newReviewerID, err := f.service.ReassignPullRequest(ctx, "pr-1", "u2", 0)
+	if err != nil {
+		t.Fatalf("reassign deleted reviewer: %v", err)
+	}
+
+	if newReviewerID != "u3" {
+		t.Errorf("got new reviewer %s, want u3", newReviewerID)
+	}
+}
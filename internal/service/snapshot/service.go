@@ -0,0 +1,278 @@
+package snapshotservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+type TxManager[E any] interface {
+	TxWrapper(ctx context.Context, fn func(ctx context.Context, tx E) error) error
+	TxWrapperWithOptions(
+		ctx context.Context,
+		opts store.TxOptions,
+		fn func(ctx context.Context, tx E) error,
+	) error
+}
+
+type RepoFactory[E any] interface {
+	SnapshotRepository(exec E) repository.SnapshotRepository
+	ArchiveRepository(exec E) repository.ArchiveRepository
+}
+
+// Writer receives the snapshot during export: teams first, then users,
+// then pull requests.
+type Writer interface {
+	WriteTeam(team domain.SnapshotTeam) error
+	WriteUser(user domain.User) error
+	WritePullRequest(pr domain.PullRequest) error
+}
+
+// errRollback discards the import transaction after the report is ready.
+var errRollback = errors.New("rollback import")
+
+type SnapshotService[E any] struct {
+	txManager TxManager[E]
+	repoFact  RepoFactory[E]
+}
+
+func NewSnapshotService[E any](txManager TxManager[E], repoFact RepoFactory[E]) *SnapshotService[E] {
+	return &SnapshotService[E]{
+		txManager: txManager,
+		repoFact:  repoFact,
+	}
+}
+
+// Export may be used for
+// GET /admin/export
+// streams all teams, users and pull requests read in one transaction.
+func (s *SnapshotService[E]) Export(ctx context.Context, w Writer) (err error) {
+	ctx, span := tracing.Start(ctx, "SnapshotService.Export")
+	defer func() { tracing.End(span, err) }()
+
+	opts := store.TxOptions{IsoLevel: store.IsoLevelRepeatableRead, ReadOnly: true}
+
+	err = s.txManager.TxWrapperWithOptions(ctx, opts, func(ctx context.Context, tx E) error {
+		localSnapshotRepo := s.repoFact.SnapshotRepository(tx)
+
+		if err := localSnapshotRepo.ForEachTeam(ctx, w.WriteTeam); err != nil {
+			return fmt.Errorf("export teams: %w", err)
+		}
+
+		if err := localSnapshotRepo.ForEachUser(ctx, w.WriteUser); err != nil {
+			return fmt.Errorf("export users: %w", err)
+		}
+
+		if err := localSnapshotRepo.ForEachPullRequest(ctx, w.WritePullRequest); err != nil {
+			return fmt.Errorf("export pull requests: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("service export: %w", err)
+	}
+
+	return nil
+}
+
+// Import may be used for
+// POST /admin/import
+// loads snapshot in one transaction. An invalid snapshot is not an error:
+// nothing is written and the report lists the problems. A dry run
+// validates and writes the snapshot, then rolls back.
+func (s *SnapshotService[E]) Import(
+	ctx context.Context,
+	snapshot domain.Snapshot,
+	mode domain.ImportMode,
+	dryRun bool,
+) (_ domain.ImportReport, err error) {
+	ctx, span := tracing.Start(ctx, "SnapshotService.Import")
+	defer func() { tracing.End(span, err) }()
+
+	var report domain.ImportReport
+
+	setDefaults(&snapshot)
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		report = domain.ImportReport{Mode: mode, DryRun: dryRun}
+
+		localSnapshotRepo := s.repoFact.SnapshotRepository(tx)
+
+		existing, err := existingRecords(ctx, localSnapshotRepo, mode)
+		if err != nil {
+			return err
+		}
+
+		report.Errors = validate(snapshot, existing)
+
+		archiveErrors, err := s.checkArchive(ctx, tx, snapshot.PullRequests)
+		if err != nil {
+			return err
+		}
+
+		report.Errors = append(report.Errors, archiveErrors...)
+
+		if len(report.Errors) > 0 {
+			return errRollback
+		}
+
+		if err = apply(ctx, localSnapshotRepo, snapshot, mode, &report); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errRollback
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errRollback) {
+		return domain.ImportReport{}, fmt.Errorf("service import: %w", err)
+	}
+
+	return report, nil
+}
+
+func setDefaults(snapshot *domain.Snapshot) {
+	now := time.Now()
+
+	for i := range snapshot.PullRequests {
+		pr := &snapshot.PullRequests[i]
+
+		if pr.Status == "" {
+			pr.Status = domain.PRStatusOpen
+		}
+
+		if pr.CreatedAt.IsZero() {
+			pr.CreatedAt = now
+		}
+
+		if pr.Version == 0 {
+			pr.Version = 1
+		}
+	}
+}
+
+// records holds the teams and users a snapshot may refer to besides its own.
+type records struct {
+	teams map[string]struct{}
+	users map[string]struct{}
+}
+
+func existingRecords(
+	ctx context.Context,
+	repo repository.SnapshotRepository,
+	mode domain.ImportMode,
+) (records, error) {
+	existing := records{
+		teams: make(map[string]struct{}),
+		users: make(map[string]struct{}),
+	}
+
+	if mode == domain.ImportModeReplace {
+		return existing, nil
+	}
+
+	err := repo.ForEachTeam(ctx, func(team domain.SnapshotTeam) error {
+		existing.teams[team.Name] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return records{}, fmt.Errorf("list teams: %w", err)
+	}
+
+	err = repo.ForEachUser(ctx, func(user domain.User) error {
+		existing.users[user.ID] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return records{}, fmt.Errorf("list users: %w", err)
+	}
+
+	return existing, nil
+}
+
+// checkArchive reports pull requests whose IDs are taken by archived ones.
+func (s *SnapshotService[E]) checkArchive(
+	ctx context.Context,
+	tx E,
+	pullRequests []domain.PullRequest,
+) ([]string, error) {
+	var problems []string
+
+	localArchiveRepo := s.repoFact.ArchiveRepository(tx)
+
+	for i, pr := range pullRequests {
+		_, err := localArchiveRepo.GetByID(ctx, pr.ID)
+		if err == nil {
+			problems = append(problems, fmt.Sprintf("pull_requests[%d]: pull request %s is archived", i, pr.ID))
+			continue
+		}
+
+		if !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+			return nil, fmt.Errorf("get archived pull request: %w", err)
+		}
+	}
+
+	return problems, nil
+}
+
+func apply(
+	ctx context.Context,
+	repo repository.SnapshotRepository,
+	snapshot domain.Snapshot,
+	mode domain.ImportMode,
+	report *domain.ImportReport,
+) error {
+	if mode == domain.ImportModeReplace {
+		if err := repo.DeleteAll(ctx); err != nil {
+			return fmt.Errorf("delete all: %w", err)
+		}
+	}
+
+	for _, team := range snapshot.Teams {
+		created, err := repo.UpsertTeam(ctx, team)
+		if err != nil {
+			return fmt.Errorf("upsert team %s: %w", team.Name, err)
+		}
+
+		count(&report.Teams, created)
+	}
+
+	for _, user := range snapshot.Users {
+		created, err := repo.UpsertUser(ctx, user)
+		if err != nil {
+			return fmt.Errorf("upsert user %s: %w", user.ID, err)
+		}
+
+		count(&report.Users, created)
+	}
+
+	for _, pr := range snapshot.PullRequests {
+		created, err := repo.UpsertPullRequest(ctx, pr)
+		if err != nil {
+			return fmt.Errorf("upsert pull request %s: %w", pr.ID, err)
+		}
+
+		count(&report.PullRequests, created)
+	}
+
+	return nil
+}
+
+func count(counts *domain.ImportCounts, created bool) {
+	if created {
+		counts.Created++
+	} else {
+		counts.Updated++
+	}
+}
@@ -0,0 +1,141 @@
+package snapshotservice_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	snapshotservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/snapshot"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory/memorytest"
+)
+
+// recorder collects the exported records.
+type recorder struct {
+	teams        []string
+	users        []string
+	pullRequests []string
+}
+
+func (r *recorder) WriteTeam(team domain.SnapshotTeam) error {
+	r.teams = append(r.teams, team.Name)
+	return nil
+}
+
+func (r *recorder) WriteUser(user domain.User) error {
+	r.users = append(r.users, user.ID)
+	return nil
+}
+
+func (r *recorder) WritePullRequest(pr domain.PullRequest) error {
+	r.pullRequests = append(r.pullRequests, pr.ID)
+	return nil
+}
+
+// newFixture seeds team backend with u1 and u2 and pull request pr-1 by u1 reviewed by u2.
+func newFixture(t *testing.T) (*memorytest.Backend, *snapshotservice.SnapshotService[memory.Executor]) {
+	t.Helper()
+
+	b := memorytest.NewBackend()
+	b.SeedTeams(t, memorytest.Team("backend", memorytest.Member("u1", true), memorytest.Member("u2", true)))
+	b.SeedPullRequest(t, "pr-1", "u1", "u2")
+
+	return b, snapshotservice.NewSnapshotService[memory.Executor](b.TxManager, b.RepoFactory)
+}
+
+func export(t *testing.T, s *snapshotservice.SnapshotService[memory.Executor]) *recorder {
+	t.Helper()
+
+	var r recorder
+	if err := s.Export(context.Background(), &r); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	return &r
+}
+
+func frontend() domain.Snapshot {
+	return domain.Snapshot{
+		Teams: []domain.SnapshotTeam{{Name: "frontend"}},
+		Users: []domain.User{{ID: "u3", Username: "name-u3", TeamName: "frontend", IsActive: true}},
+	}
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      domain.ImportMode
+		dryRun    bool
+		wantTeams []string
+		wantUsers []string
+	}{
+		{
+			name:      "merge",
+			mode:      domain.ImportModeMerge,
+			wantTeams: []string{"backend", "frontend"},
+			wantUsers: []string{"u1", "u2", "u3"},
+		},
+		{
+			name:      "replace",
+			mode:      domain.ImportModeReplace,
+			wantTeams: []string{"frontend"},
+			wantUsers: []string{"u3"},
+		},
+		{
+			name:      "dry run",
+			mode:      domain.ImportModeReplace,
+			dryRun:    true,
+			wantTeams: []string{"backend"},
+			wantUsers: []string{"u1", "u2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, s := newFixture(t)
+
+			report, err := s.Import(context.Background(), frontend(), tt.mode, tt.dryRun)
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+
+			if len(report.Errors) > 0 || report.Teams.Created != 1 || report.Users.Created != 1 {
+				t.Errorf("got report %+v, want one team and one user created", report)
+			}
+
+			got := export(t, s)
+			if !slices.Equal(got.teams, tt.wantTeams) || !slices.Equal(got.users, tt.wantUsers) {
+				t.Errorf("got teams %v users %v, want %v and %v", got.teams, got.users, tt.wantTeams, tt.wantUsers)
+			}
+		})
+	}
+}
+
+func TestImportInvalidWritesNothing(t *testing.T) {
+	_, s := newFixture(t)
+
+	snapshot := frontend()
+	snapshot.PullRequests = []domain.PullRequest{
+		{ID: "pr-2", AuthorID: "u3", AssignedReviewers: []string{"u3"}},
+		{ID: "pr-3", AuthorID: "nobody"},
+	}
+
+	report, err := s.Import(context.Background(), snapshot, domain.ImportModeMerge, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	want := []string{
+		"pull_requests[0]: author u3 is a reviewer",
+		"pull_requests[1]: author nobody does not exist",
+	}
+	if !slices.Equal(report.Errors, want) {
+		t.Errorf("got errors %q, want %q", report.Errors, want)
+	}
+
+	got := export(t, s)
+	if !slices.Equal(got.teams, []string{"backend"}) || !slices.Equal(got.pullRequests, []string{"pr-1"}) {
+		t.Errorf("invalid import wrote teams %v and pull requests %v", got.teams, got.pullRequests)
+	}
+}
@@ -0,0 +1,104 @@
+package snapshotservice
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+// validate checks the snapshot against itself and the records it may refer to.
+func validate(snapshot domain.Snapshot, existing records) []string {
+	var problems []string
+
+	report := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	teams := make(map[string]struct{}, len(snapshot.Teams))
+	for i, team := range snapshot.Teams {
+		if team.Name == "" {
+			report("teams[%d]: team_name is required", i)
+			continue
+		}
+
+		if _, ok := teams[team.Name]; ok {
+			report("teams[%d]: duplicate team %s", i, team.Name)
+		}
+
+		teams[team.Name] = struct{}{}
+	}
+
+	users := make(map[string]struct{}, len(snapshot.Users))
+	for i, user := range snapshot.Users {
+		if user.ID == "" {
+			report("users[%d]: user_id is required", i)
+			continue
+		}
+
+		if _, ok := users[user.ID]; ok {
+			report("users[%d]: duplicate user %s", i, user.ID)
+		}
+
+		users[user.ID] = struct{}{}
+
+		if !known(user.TeamName, teams, existing.teams) {
+			report("users[%d]: team %s does not exist", i, user.TeamName)
+		}
+	}
+
+	pullRequests := make(map[string]struct{}, len(snapshot.PullRequests))
+	for i, pr := range snapshot.PullRequests {
+		if pr.ID == "" {
+			report("pull_requests[%d]: pull_request_id is required", i)
+			continue
+		}
+
+		if _, ok := pullRequests[pr.ID]; ok {
+			report("pull_requests[%d]: duplicate pull request %s", i, pr.ID)
+		}
+
+		pullRequests[pr.ID] = struct{}{}
+
+		if !known(pr.AuthorID, users, existing.users) {
+			report("pull_requests[%d]: author %s does not exist", i, pr.AuthorID)
+		}
+
+		switch pr.Status {
+		case domain.PRStatusOpen:
+			if pr.MergedAt != nil {
+				report("pull_requests[%d]: open pull request has mergedAt", i)
+			}
+		case domain.PRStatusMerged:
+			if pr.MergedAt == nil {
+				report("pull_requests[%d]: merged pull request has no mergedAt", i)
+			}
+		default:
+			report("pull_requests[%d]: unknown status %s", i, pr.Status)
+		}
+
+		if len(pr.AssignedReviewers) > domain.MaxAssignedReviewers {
+			report("pull_requests[%d]: more than %d reviewers", i, domain.MaxAssignedReviewers)
+		}
+
+		for j, reviewerID := range pr.AssignedReviewers {
+			switch {
+			case reviewerID == pr.AuthorID:
+				report("pull_requests[%d]: author %s is a reviewer", i, reviewerID)
+			case slices.Index(pr.AssignedReviewers, reviewerID) != j:
+				report("pull_requests[%d]: duplicate reviewer %s", i, reviewerID)
+			case !known(reviewerID, users, existing.users):
+				report("pull_requests[%d]: reviewer %s does not exist", i, reviewerID)
+			}
+		}
+	}
+
+	return problems
+}
+
+func known(id string, snapshot, existing map[string]struct{}) bool {
+	_, inSnapshot := snapshot[id]
+	_, inExisting := existing[id]
+
+	return inSnapshot || inExisting
+}
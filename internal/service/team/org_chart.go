@@ -0,0 +1,222 @@
+package teamservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+// errDryRun discards the import transaction after the diff is ready.
+var errDryRun = errors.New("dry run")
+
+// orgChartPlan is a diff with the writes needed to apply it.
+type orgChartPlan struct {
+	diff           domain.OrgChartDiff
+	upserts        []domain.User
+	activityEvents []domain.User
+}
+
+// ImportOrgChart may be used for
+// POST /team/import
+// creates teams and moves, adds and deactivates users to match org chart.
+// A dry run applies the chart and rolls it back, so conflicts are reported too.
+func (s *TeamService[E]) ImportOrgChart(
+	ctx context.Context,
+	chart domain.OrgChart,
+	dryRun bool,
+) (_ domain.OrgChartDiff, err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.ImportOrgChart")
+	defer func() { tracing.End(span, err) }()
+
+	var plan orgChartPlan
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		var err error
+
+		plan, err = s.planOrgChart(ctx, tx, chart)
+		if err != nil {
+			return err
+		}
+
+		if err = s.applyOrgChart(ctx, tx, plan); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errDryRun) {
+		return domain.OrgChartDiff{}, fmt.Errorf("service import org chart: %w", err)
+	}
+
+	plan.diff.DryRun = dryRun
+
+	if !dryRun {
+		s.publishActivityChanges(ctx, plan.activityEvents)
+	}
+
+	return plan.diff, nil
+}
+
+// planOrgChart compares the chart with the current state before anything is written.
+func (s *TeamService[E]) planOrgChart(
+	ctx context.Context,
+	tx E,
+	chart domain.OrgChart,
+) (orgChartPlan, error) {
+	var plan orgChartPlan
+
+	localTeamRepo := s.repoFact.TeamRepository(tx)
+	localUserRepo := s.repoFact.UserRepository(tx)
+
+	listed := make(map[string]struct{})
+	for _, team := range chart.Teams {
+		for _, member := range team.Members {
+			listed[member.UserID] = struct{}{}
+		}
+	}
+
+	for _, team := range chart.Teams {
+		current, err := localTeamRepo.GetTeamWithMembers(ctx, team.Name)
+		switch {
+		case domain.IsErrorCode(err, domain.ErrCodeNotFound):
+			plan.diff.TeamsCreated = append(plan.diff.TeamsCreated, team.Name)
+		case err != nil:
+			return orgChartPlan{}, fmt.Errorf("get team %s: %w", team.Name, err)
+		}
+
+		for _, member := range current.Members {
+			if _, ok := listed[member.UserID]; ok || !member.IsActive {
+				continue
+			}
+
+			user := domain.User{ID: member.UserID, Username: member.Username, TeamName: team.Name}
+			plan.diff.UsersDeactivated = append(plan.diff.UsersDeactivated, orgChartChange(user, ""))
+			plan.activityEvents = append(plan.activityEvents, user)
+		}
+
+		for _, member := range team.Members {
+			if err = plan.addMember(ctx, localUserRepo.GetByIDWithDeleted, team.Name, member); err != nil {
+				return orgChartPlan{}, err
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+func (p *orgChartPlan) addMember(
+	ctx context.Context,
+	getUser func(ctx context.Context, userID string) (domain.User, error),
+	teamName string,
+	member domain.TeamMember,
+) error {
+	user := domain.User{
+		ID:       member.UserID,
+		Username: member.Username,
+		TeamName: teamName,
+		IsActive: member.IsActive,
+	}
+
+	current, err := getUser(ctx, member.UserID)
+	if domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		p.diff.UsersCreated = append(p.diff.UsersCreated, orgChartChange(user, ""))
+		p.upserts = append(p.upserts, user)
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get user %s: %w", member.UserID, err)
+	}
+
+	fromTeam := ""
+	if current.TeamName != teamName {
+		fromTeam = current.TeamName
+	}
+
+	activityChanged := current.IsActive != user.IsActive
+	if current.DeletedAt == nil && activityChanged {
+		p.activityEvents = append(p.activityEvents, user)
+	}
+
+	switch {
+	case current.DeletedAt != nil:
+		p.diff.UsersRestored = append(p.diff.UsersRestored, orgChartChange(user, fromTeam))
+	case fromTeam != "":
+		p.diff.UsersMoved = append(p.diff.UsersMoved, orgChartChange(user, fromTeam))
+	case current.Username != user.Username || activityChanged:
+		p.diff.UsersUpdated = append(p.diff.UsersUpdated, orgChartChange(user, ""))
+	default:
+		p.diff.Unchanged++
+		return nil
+	}
+
+	p.upserts = append(p.upserts, user)
+
+	return nil
+}
+
+func orgChartChange(user domain.User, fromTeam string) domain.OrgChartUserChange {
+	return domain.OrgChartUserChange{
+		UserID:   user.ID,
+		Username: user.Username,
+		TeamName: user.TeamName,
+		FromTeam: fromTeam,
+		IsActive: user.IsActive,
+	}
+}
+
+func (s *TeamService[E]) applyOrgChart(ctx context.Context, tx E, plan orgChartPlan) error {
+	localTeamRepo := s.repoFact.TeamRepository(tx)
+	localUserRepo := s.repoFact.UserRepository(tx)
+
+	for _, teamName := range plan.diff.TeamsCreated {
+		if err := localTeamRepo.InsertTeam(ctx, teamName); err != nil {
+			return fmt.Errorf("insert team: %w", err)
+		}
+	}
+
+	for _, user := range plan.upserts {
+		if err := localUserRepo.UpsertUser(ctx, user); err != nil {
+			return fmt.Errorf("upsert user %s: %w", user.ID, err)
+		}
+	}
+
+	for _, change := range plan.diff.UsersDeactivated {
+		if err := localUserRepo.SetIsActive(ctx, change.UserID, false); err != nil {
+			return fmt.Errorf("deactivate user %s: %w", change.UserID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *TeamService[E]) publishActivityChanges(ctx context.Context, users []domain.User) {
+	if len(users) == 0 {
+		return
+	}
+
+	now := time.Now()
+	events := make([]domain.Event, 0, len(users))
+
+	for _, user := range users {
+		events = append(events, domain.Event{
+			Type:       domain.EventUserActivityChanged,
+			UserID:     user.ID,
+			TeamName:   user.TeamName,
+			IsActive:   user.IsActive,
+			OccurredAt: now,
+		})
+	}
+
+	s.publisher.Publish(ctx, events...)
+}
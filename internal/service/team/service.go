@@ -3,45 +3,55 @@ package teamservice
 import (
 	"context"
 	"fmt"
+	"time"
 
-	"github.com/jackc/pgx/v5"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/repository"
-	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
 )
 
-type TxManager interface {
-	TxWrapper(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error
+type TxManager[E any] interface {
+	TxWrapper(ctx context.Context, fn func(ctx context.Context, tx E) error) error
 }
 
-type RepoFactory interface {
-	TeamRepository(exec postgres.Execer) repository.TeamRepository
-	UserRepository(exec postgres.Execer) repository.UserRepository
+type RepoFactory[E any] interface {
+	TeamRepository(exec E) repository.TeamRepository
+	UserRepository(exec E) repository.UserRepository
 }
 
-type TeamService struct {
-	txManager TxManager
-	repoFact  RepoFactory
-	readExec  postgres.Execer
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...domain.Event)
 }
 
-func NewTeamService(
-	txManager TxManager,
-	readExec postgres.Execer,
-	repoFact RepoFactory,
-) *TeamService {
-	return &TeamService{
+type TeamService[E any] struct {
+	txManager TxManager[E]
+	repoFact  RepoFactory[E]
+	readExec  E
+	publisher EventPublisher
+}
+
+func NewTeamService[E any](
+	txManager TxManager[E],
+	readExec E,
+	repoFact RepoFactory[E],
+	publisher EventPublisher,
+) *TeamService[E] {
+	return &TeamService[E]{
 		txManager: txManager,
 		repoFact:  repoFact,
 		readExec:  readExec,
+		publisher: publisher,
 	}
 }
 
 // CreateTeam may be used for
 // POST /team/add
 // creates team.
-func (s *TeamService) CreateTeam(ctx context.Context, up domain.TeamUpsert) (domain.TeamUpsert, error) {
-	err := s.txManager.TxWrapper(ctx, func(ctx context.Context, tx pgx.Tx) error {
+func (s *TeamService[E]) CreateTeam(ctx context.Context, up domain.TeamUpsert) (_ domain.TeamUpsert, err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.CreateTeam")
+	defer func() { tracing.End(span, err) }()
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
 		localTeamRepo := s.repoFact.TeamRepository(tx)
 		localUserRepo := s.repoFact.UserRepository(tx)
 
@@ -77,7 +87,10 @@ func (s *TeamService) CreateTeam(ctx context.Context, up domain.TeamUpsert) (dom
 // GetTeamWithMembers may be used for
 // GET /team/get
 // returns team with members.
-func (s *TeamService) GetTeamWithMembers(ctx context.Context, teamName string) (domain.TeamUpsert, error) {
+func (s *TeamService[E]) GetTeamWithMembers(ctx context.Context, teamName string) (_ domain.TeamUpsert, err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.GetTeamWithMembers")
+	defer func() { tracing.End(span, err) }()
+
 	localTeamRepo := s.repoFact.TeamRepository(s.readExec)
 
 	domainTeam, err := localTeamRepo.GetTeamWithMembers(ctx, teamName)
@@ -88,3 +101,53 @@ func (s *TeamService) GetTeamWithMembers(ctx context.Context, teamName string) (
 
 	return domainTeam, nil
 }
+
+// DeleteTeam may be used for
+// POST /team/delete
+// deletes team together with its members.
+func (s *TeamService[E]) DeleteTeam(ctx context.Context, teamName string) (err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.DeleteTeam")
+	defer func() { tracing.End(span, err) }()
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		return s.repoFact.TeamRepository(tx).SoftDeleteTeam(ctx, teamName, time.Now())
+	})
+
+	if err != nil {
+		return fmt.Errorf("service delete team: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreTeam may be used for
+// POST /team/restore
+// restores deleted team with the members deleted together with it.
+func (s *TeamService[E]) RestoreTeam(ctx context.Context, teamName string) (_ domain.TeamUpsert, err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.RestoreTeam")
+	defer func() { tracing.End(span, err) }()
+
+	var team domain.TeamUpsert
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		localTeamRepo := s.repoFact.TeamRepository(tx)
+
+		err := localTeamRepo.RestoreTeam(ctx, teamName)
+		if err != nil {
+			return fmt.Errorf("restore team: %w", err)
+		}
+
+		team, err = localTeamRepo.GetTeamWithMembers(ctx, teamName)
+		if err != nil {
+			return fmt.Errorf("get team: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return domain.TeamUpsert{}, fmt.Errorf("service restore team: %w", err)
+	}
+
+	return team, nil
+}
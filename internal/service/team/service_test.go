@@ -0,0 +1,210 @@
+package teamservice_test
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	teamservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/team"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory/memorytest"
+)
+
+func newService() *teamservice.TeamService[memory.Executor] {
+	s, _, _ := newServiceWithBackend()
+
+	return s
+}
+
+func newServiceWithBackend() (*teamservice.TeamService[memory.Executor], *memorytest.Backend, *memorytest.Publisher) {
+	b := memorytest.NewBackend()
+	publisher := &memorytest.Publisher{}
+
+	return teamservice.NewTeamService[memory.Executor](b.TxManager, b.Store, b.RepoFactory, publisher), b, publisher
+}
+
+func memberIDs(team domain.TeamUpsert) []string {
+	ids := make([]string, 0, len(team.Members))
+	for _, m := range team.Members {
+		ids = append(ids, m.UserID)
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
+
+func TestCreateTeam(t *testing.T) {
+	ctx := context.Background()
+	s := newService()
+
+	backend := memorytest.Team("backend", memorytest.Member("u1", true), memorytest.Member("u2", false))
+
+	if _, err := s.CreateTeam(ctx, backend); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+
+	got, err := s.GetTeamWithMembers(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get team: %v", err)
+	}
+
+	if ids := memberIDs(got); !slices.Equal(ids, []string{"u1", "u2"}) {
+		t.Errorf("got members %v, want [u1 u2]", ids)
+	}
+
+	_, err = s.CreateTeam(ctx, memorytest.Team("backend", memorytest.Member("u3", true)))
+	if !domain.IsErrorCode(err, domain.ErrCodeTeamExists) {
+		t.Errorf("create duplicate team: got error %v, want %s", err, domain.ErrCodeTeamExists)
+	}
+
+	_, err = s.GetTeamWithMembers(ctx, "frontend")
+	if !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Errorf("get missing team: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+}
+
+func TestCreateTeamMovesMembers(t *testing.T) {
+	ctx := context.Background()
+	s := newService()
+
+	if _, err := s.CreateTeam(ctx, memorytest.Team("backend", memorytest.Member("u1", true))); err != nil {
+		t.Fatalf("create backend: %v", err)
+	}
+
+	if _, err := s.CreateTeam(ctx, memorytest.Team("frontend", memorytest.Member("u1", true))); err != nil {
+		t.Fatalf("create frontend: %v", err)
+	}
+
+	backend, err := s.GetTeamWithMembers(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get backend: %v", err)
+	}
+
+	if len(backend.Members) != 0 {
+		t.Errorf("got backend members %v, want none after the move", memberIDs(backend))
+	}
+}
+
+func TestDeleteAndRestoreTeam(t *testing.T) {
+	ctx := context.Background()
+	s := newService()
+
+	_, err := s.CreateTeam(ctx, memorytest.Team("backend", memorytest.Member("u1", true)))
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+
+	if err = s.DeleteTeam(ctx, "backend"); err != nil {
+		t.Fatalf("delete team: %v", err)
+	}
+
+	if _, err = s.GetTeamWithMembers(ctx, "backend"); !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Fatalf("get deleted team: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+
+	if err = s.DeleteTeam(ctx, "backend"); !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Errorf("delete deleted team: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+
+	restored, err := s.RestoreTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("restore team: %v", err)
+	}
+
+	if ids := memberIDs(restored); !slices.Equal(ids, []string{"u1"}) {
+		t.Errorf("got restored members %v, want [u1]", ids)
+	}
+}
+
+func TestImportOrgChart(t *testing.T) {
+	ctx := context.Background()
+	s, b, publisher := newServiceWithBackend()
+
+	b.SeedTeams(t,
+		memorytest.Team("backend",
+			memorytest.Member("u1", true), memorytest.Member("u2", true), memorytest.Member("u3", true)),
+		memorytest.Team("frontend", memorytest.Member("u4", true), memorytest.Member("u5", true)),
+	)
+	b.Seed(t, func(ctx context.Context, tx memory.Executor) error {
+		return b.RepoFactory.UserRepository(tx).SoftDeleteUser(ctx, "u5", time.Now())
+	})
+
+	renamed := memorytest.Member("u3", false)
+	renamed.Username = "Carol"
+
+	chart := domain.OrgChart{Teams: []domain.TeamUpsert{
+		memorytest.Team("backend", memorytest.Member("u1", true), renamed, memorytest.Member("u4", true)),
+		memorytest.Team("mobile", memorytest.Member("u5", true), memorytest.Member("u6", true)),
+	}}
+
+	change := func(userID, username, teamName, fromTeam string, isActive bool) []domain.OrgChartUserChange {
+		return []domain.OrgChartUserChange{
+			{UserID: userID, Username: username, TeamName: teamName, FromTeam: fromTeam, IsActive: isActive},
+		}
+	}
+
+	want := domain.OrgChartDiff{
+		DryRun:           true,
+		TeamsCreated:     []string{"mobile"},
+		UsersCreated:     change("u6", "u6", "mobile", "", true),
+		UsersMoved:       change("u4", "u4", "backend", "frontend", true),
+		UsersRestored:    change("u5", "u5", "mobile", "frontend", true),
+		UsersUpdated:     change("u3", "Carol", "backend", "", false),
+		UsersDeactivated: change("u2", "u2", "backend", "", false),
+		Unchanged:        1,
+	}
+
+	diff, err := s.ImportOrgChart(ctx, chart, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("got dry run diff %+v, want %+v", diff, want)
+	}
+
+	if _, err = s.GetTeamWithMembers(ctx, "mobile"); !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Fatalf("dry run created team: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+
+	if n := len(publisher.Events()); n != 0 {
+		t.Errorf("dry run published %d events, want none", n)
+	}
+
+	diff, err = s.ImportOrgChart(ctx, chart, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	want.DryRun = false
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("got import diff %+v, want %+v", diff, want)
+	}
+
+	mobile, err := s.GetTeamWithMembers(ctx, "mobile")
+	if err != nil {
+		t.Fatalf("get mobile: %v", err)
+	}
+
+	if ids := memberIDs(mobile); !slices.Equal(ids, []string{"u5", "u6"}) {
+		t.Errorf("got mobile members %v, want [u5 u6]", ids)
+	}
+
+	// u2 and u3 are deactivated; moves and restores keep the activity as it was.
+	if n := publisher.Count(domain.EventUserActivityChanged); n != 2 {
+		t.Errorf("import published %d activity events, want 2", n)
+	}
+
+	diff, err = s.ImportOrgChart(ctx, chart, false)
+	if err != nil {
+		t.Fatalf("second import: %v", err)
+	}
+
+	if !reflect.DeepEqual(diff, domain.OrgChartDiff{Unchanged: 5}) {
+		t.Errorf("got second import diff %+v, want 5 unchanged", diff)
+	}
+}
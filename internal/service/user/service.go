@@ -3,46 +3,58 @@ package userservice
 import (
 	"context"
 	"fmt"
+	"time"
 
-	"github.com/jackc/pgx/v5"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/repository"
-	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
 )
 
-type TxManager interface {
-	TxWrapper(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error
+type TxManager[E any] interface {
+	TxWrapper(ctx context.Context, fn func(ctx context.Context, tx E) error) error
 }
 
-type RepoFactory interface {
-	UserRepository(exec postgres.Execer) repository.UserRepository
+type RepoFactory[E any] interface {
+	UserRepository(exec E) repository.UserRepository
+	TeamRepository(exec E) repository.TeamRepository
+	ArchiveRepository(exec E) repository.ArchiveRepository
 }
 
-type UserService struct {
-	txManager TxManager
-	repoFact  RepoFactory
-	readExec  postgres.Execer
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...domain.Event)
 }
 
-func NewUserService(
-	txManager TxManager,
-	readExec postgres.Execer,
-	repoFact RepoFactory,
-) *UserService {
-	return &UserService{
+type UserService[E any] struct {
+	txManager TxManager[E]
+	repoFact  RepoFactory[E]
+	readExec  E
+	publisher EventPublisher
+}
+
+func NewUserService[E any](
+	txManager TxManager[E],
+	readExec E,
+	repoFact RepoFactory[E],
+	publisher EventPublisher,
+) *UserService[E] {
+	return &UserService[E]{
 		txManager: txManager,
 		repoFact:  repoFact,
 		readExec:  readExec,
+		publisher: publisher,
 	}
 }
 
 // SetIsActive may be used for
 // POST /users/setIsActive
 // sets isActive for user.
-func (s *UserService) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error) {
+func (s *UserService[E]) SetIsActive(ctx context.Context, userID string, isActive bool) (_ domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.SetIsActive")
+	defer func() { tracing.End(span, err) }()
+
 	var dbUser domain.User
 
-	err := s.txManager.TxWrapper(ctx, func(ctx context.Context, tx pgx.Tx) error {
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
 		localUserRepo := s.repoFact.UserRepository(tx)
 
 		err := localUserRepo.SetIsActive(ctx, userID, isActive)
@@ -62,13 +74,28 @@ func (s *UserService) SetIsActive(ctx context.Context, userID string, isActive b
 		return dbUser, fmt.Errorf("service set is active transaction: %w", err)
 	}
 
+	s.publisher.Publish(ctx, domain.Event{
+		Type:       domain.EventUserActivityChanged,
+		UserID:     dbUser.ID,
+		TeamName:   dbUser.TeamName,
+		IsActive:   dbUser.IsActive,
+		OccurredAt: time.Now(),
+	})
+
 	return dbUser, nil
 }
 
 // ListReviewPRs may be used for
 // GET /users/getReview
-// returns list of pull requests assigned to user.
-func (s *UserService) ListReviewPRs(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+// returns list of pull requests assigned to user, archived ones last.
+func (s *UserService[E]) ListReviewPRs(
+	ctx context.Context,
+	userID string,
+	includeArchived bool,
+) (_ []domain.PullRequest, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.ListReviewPRs")
+	defer func() { tracing.End(span, err) }()
+
 	locaUserRepo := s.repoFact.UserRepository(s.readExec)
 
 	pullRequests, err := locaUserRepo.ListReviewPRs(ctx, userID)
@@ -76,5 +103,81 @@ func (s *UserService) ListReviewPRs(ctx context.Context, userID string) ([]domai
 		return nil, fmt.Errorf("service list review prs: %w", err)
 	}
 
-	return pullRequests, nil
+	if !includeArchived {
+		return pullRequests, nil
+	}
+
+	archived, err := s.repoFact.ArchiveRepository(s.readExec).ListReviewPRs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service list archived review prs: %w", err)
+	}
+
+	return append(pullRequests, archived...), nil
+}
+
+// DeleteUser may be used for
+// POST /users/delete
+// deletes user; its review assignments are kept.
+func (s *UserService[E]) DeleteUser(ctx context.Context, userID string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.DeleteUser")
+	defer func() { tracing.End(span, err) }()
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		return s.repoFact.UserRepository(tx).SoftDeleteUser(ctx, userID, time.Now())
+	})
+
+	if err != nil {
+		return fmt.Errorf("service delete user: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreUser may be used for
+// POST /users/restore
+// restores deleted user if its team is not deleted.
+func (s *UserService[E]) RestoreUser(ctx context.Context, userID string) (_ domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.RestoreUser")
+	defer func() { tracing.End(span, err) }()
+
+	var dbUser domain.User
+
+	err = s.txManager.TxWrapper(ctx, func(ctx context.Context, tx E) error {
+		localUserRepo := s.repoFact.UserRepository(tx)
+
+		deletedUser, err := localUserRepo.GetByIDWithDeleted(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("get user: %w", err)
+		}
+
+		_, err = s.repoFact.TeamRepository(tx).GetTeamWithMembers(ctx, deletedUser.TeamName)
+		if domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+			return domain.NewError(
+				domain.ErrCodeNotFound,
+				fmt.Sprintf("team %s of user %s is deleted, restore the team first", deletedUser.TeamName, userID),
+			)
+		}
+
+		if err != nil {
+			return fmt.Errorf("get team: %w", err)
+		}
+
+		err = localUserRepo.RestoreUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("restore user: %w", err)
+		}
+
+		dbUser, err = localUserRepo.GetByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("get user: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return domain.User{}, fmt.Errorf("service restore user: %w", err)
+	}
+
+	return dbUser, nil
 }
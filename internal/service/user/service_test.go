@@ -0,0 +1,126 @@
+package userservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	userservice "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/service/user"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory/memorytest"
+)
+
+type fixture struct {
+	*memorytest.Backend
+
+	service   *userservice.UserService[memory.Executor]
+	publisher *memorytest.Publisher
+}
+
+// newFixture seeds team backend with u1 and u2 and pull request pr-1 by u1 reviewed by u2.
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+
+	f := fixture{Backend: memorytest.NewBackend(), publisher: &memorytest.Publisher{}}
+	f.service = userservice.NewUserService[memory.Executor](f.TxManager, f.Store, f.RepoFactory, f.publisher)
+	f.SeedTeams(t, memorytest.Team("backend", memorytest.Member("u1", true), memorytest.Member("u2", true)))
+	f.SeedPullRequest(t, "pr-1", "u1", "u2")
+
+	return f
+}
+
+func TestSetIsActive(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	user, err := f.service.SetIsActive(ctx, "u2", false)
+	if err != nil {
+		t.Fatalf("set is active: %v", err)
+	}
+
+	if user.IsActive || user.TeamName != "backend" {
+		t.Errorf("got user %+v, want inactive member of backend", user)
+	}
+
+	if events := f.publisher.Events(); len(events) != 1 || events[0].Type != domain.EventUserActivityChanged {
+		t.Errorf("got events %+v, want one activity change", events)
+	}
+
+	_, err = f.service.SetIsActive(ctx, "nobody", true)
+	if !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Errorf("set is active of missing user: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+
+	if events := f.publisher.Events(); len(events) != 1 {
+		t.Errorf("failed update published events: %+v", events[1:])
+	}
+}
+
+func TestListReviewPRs(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	tests := []struct {
+		userID string
+		want   int
+	}{
+		{userID: "u1", want: 0},
+		{userID: "u2", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.userID, func(t *testing.T) {
+			pullRequests, err := f.service.ListReviewPRs(ctx, tt.userID, false)
+			if err != nil {
+				t.Fatalf("list review prs: %v", err)
+			}
+
+			if len(pullRequests) != tt.want {
+				t.Errorf("got %d pull requests, want %d", len(pullRequests), tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteAndRestoreUser(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	if err := f.service.DeleteUser(ctx, "u2"); err != nil {
+		t.Fatalf("delete user: %v", err)
+	}
+
+	if err := f.service.DeleteUser(ctx, "u2"); !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Errorf("delete deleted user: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+
+	// Review assignments of deleted users are kept.
+	pullRequests, err := f.service.ListReviewPRs(ctx, "u2", false)
+	if err != nil || len(pullRequests) != 1 {
+		t.Errorf("got %d reviews of deleted user, error %v, want 1", len(pullRequests), err)
+	}
+
+	user, err := f.service.RestoreUser(ctx, "u2")
+	if err != nil {
+		t.Fatalf("restore user: %v", err)
+	}
+
+	if user.DeletedAt != nil {
+		t.Errorf("got deleted at %v after restore", user.DeletedAt)
+	}
+}
+
+func TestRestoreUserOfDeletedTeam(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	f.Seed(t, func(ctx context.Context, tx memory.Executor) error {
+		return f.RepoFactory.TeamRepository(tx).SoftDeleteTeam(ctx, "backend", time.Now())
+	})
+
+	_, err := f.service.RestoreUser(ctx, "u2")
+	if !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Fatalf("restore user of deleted team: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+}
@@ -0,0 +1,135 @@
+// Package snapshot encodes snapshots of the service state as JSON documents:
+//
+//	{"version":1,"exportedAt":"...","teams":[...],"users":[...],"pull_requests":[...]}
+//
+// Field names follow the HTTP API.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type teamJSON struct {
+	TeamName  string     `json:"team_name"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+type userJSON struct {
+	UserID    string     `json:"user_id"`
+	Username  string     `json:"username"`
+	TeamName  string     `json:"team_name"`
+	IsActive  bool       `json:"is_active"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+type pullRequestJSON struct {
+	PullRequestID     string                   `json:"pull_request_id"`
+	PullRequestName   string                   `json:"pull_request_name"`
+	AuthorID          string                   `json:"author_id"`
+	Status            domain.PullRequestStatus `json:"status"`
+	AssignedReviewers []string                 `json:"assigned_reviewers"`
+	CreatedAt         time.Time                `json:"createdAt"`
+	MergedAt          *time.Time               `json:"mergedAt,omitempty"`
+	Version           int64                    `json:"version"`
+	DeletedAt         *time.Time               `json:"deletedAt,omitempty"`
+}
+
+type snapshotJSON struct {
+	Version      int               `json:"version"`
+	ExportedAt   time.Time         `json:"exportedAt"`
+	Teams        []teamJSON        `json:"teams"`
+	Users        []userJSON        `json:"users"`
+	PullRequests []pullRequestJSON `json:"pull_requests"`
+}
+
+// Decode reads a whole snapshot. Unknown fields are rejected to catch typos.
+func Decode(r io.Reader) (domain.Snapshot, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var doc snapshotJSON
+	if err := decoder.Decode(&doc); err != nil {
+		return domain.Snapshot{}, fmt.Errorf("error decoding snapshot: %w", err)
+	}
+
+	if doc.Version != domain.SnapshotVersion {
+		return domain.Snapshot{}, fmt.Errorf("unsupported snapshot version %d", doc.Version)
+	}
+
+	snapshot := domain.Snapshot{
+		Version:      doc.Version,
+		ExportedAt:   doc.ExportedAt,
+		Teams:        make([]domain.SnapshotTeam, 0, len(doc.Teams)),
+		Users:        make([]domain.User, 0, len(doc.Users)),
+		PullRequests: make([]domain.PullRequest, 0, len(doc.PullRequests)),
+	}
+
+	for _, team := range doc.Teams {
+		snapshot.Teams = append(snapshot.Teams, domain.SnapshotTeam{
+			Name:      team.TeamName,
+			DeletedAt: team.DeletedAt,
+		})
+	}
+
+	for _, user := range doc.Users {
+		snapshot.Users = append(snapshot.Users, domain.User{
+			ID:        user.UserID,
+			Username:  user.Username,
+			TeamName:  user.TeamName,
+			IsActive:  user.IsActive,
+			DeletedAt: user.DeletedAt,
+		})
+	}
+
+	for _, pr := range doc.PullRequests {
+		snapshot.PullRequests = append(snapshot.PullRequests, domain.PullRequest{
+			ID:                pr.PullRequestID,
+			Name:              pr.PullRequestName,
+			AuthorID:          pr.AuthorID,
+			Status:            pr.Status,
+			AssignedReviewers: pr.AssignedReviewers,
+			CreatedAt:         pr.CreatedAt,
+			MergedAt:          pr.MergedAt,
+			Version:           pr.Version,
+			DeletedAt:         pr.DeletedAt,
+		})
+	}
+
+	return snapshot, nil
+}
+
+type importCountsJSON struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// ReportJSON is the JSON form of an import report.
+type ReportJSON struct {
+	Mode         domain.ImportMode `json:"mode"`
+	DryRun       bool              `json:"dry_run"`
+	Teams        importCountsJSON  `json:"teams"`
+	Users        importCountsJSON  `json:"users"`
+	PullRequests importCountsJSON  `json:"pull_requests"`
+	Errors       []string          `json:"errors"`
+}
+
+func NewReportJSON(report domain.ImportReport) ReportJSON {
+	errs := report.Errors
+	if errs == nil {
+		errs = []string{}
+	}
+
+	return ReportJSON{
+		Mode:         report.Mode,
+		DryRun:       report.DryRun,
+		Teams:        importCountsJSON(report.Teams),
+		Users:        importCountsJSON(report.Users),
+		PullRequests: importCountsJSON(report.PullRequests),
+		Errors:       errs,
+	}
+}
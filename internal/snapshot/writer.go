@@ -0,0 +1,152 @@
+package snapshot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+var sections = []string{"teams", "users", "pull_requests"}
+
+// Writer streams a snapshot record by record, so the whole state is never
+// held in memory. Records must come in section order: teams, users, pull
+// requests. Close must be called to complete the document.
+type Writer struct {
+	w          *bufio.Writer
+	exportedAt time.Time
+	// section is the index of the open section, -1 before the first one.
+	section int
+	empty   bool
+}
+
+func NewWriter(w io.Writer, exportedAt time.Time) *Writer {
+	return &Writer{
+		w:          bufio.NewWriter(w),
+		exportedAt: exportedAt,
+		section:    -1,
+	}
+}
+
+func (w *Writer) WriteTeam(team domain.SnapshotTeam) error {
+	return w.write(0, teamJSON{
+		TeamName:  team.Name,
+		DeletedAt: team.DeletedAt,
+	})
+}
+
+func (w *Writer) WriteUser(user domain.User) error {
+	return w.write(1, userJSON{
+		UserID:    user.ID,
+		Username:  user.Username,
+		TeamName:  user.TeamName,
+		IsActive:  user.IsActive,
+		DeletedAt: user.DeletedAt,
+	})
+}
+
+func (w *Writer) WritePullRequest(pr domain.PullRequest) error {
+	reviewers := pr.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+
+	return w.write(2, pullRequestJSON{
+		PullRequestID:     pr.ID,
+		PullRequestName:   pr.Name,
+		AuthorID:          pr.AuthorID,
+		Status:            pr.Status,
+		AssignedReviewers: reviewers,
+		CreatedAt:         pr.CreatedAt,
+		MergedAt:          pr.MergedAt,
+		Version:           pr.Version,
+		DeletedAt:         pr.DeletedAt,
+	})
+}
+
+// Close writes the remaining sections and flushes the document.
+func (w *Writer) Close() error {
+	if err := w.open(len(sections)); err != nil {
+		return err
+	}
+
+	if _, err := w.w.WriteString("}\n"); err != nil {
+		return fmt.Errorf("error writing snapshot: %w", err)
+	}
+
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("error writing snapshot: %w", err)
+	}
+
+	return nil
+}
+
+func (w *Writer) write(section int, record any) error {
+	if err := w.open(section); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error encoding snapshot record: %w", err)
+	}
+
+	if !w.empty {
+		if err = w.w.WriteByte(','); err != nil {
+			return fmt.Errorf("error writing snapshot: %w", err)
+		}
+	}
+
+	w.empty = false
+
+	if _, err = w.w.Write(data); err != nil {
+		return fmt.Errorf("error writing snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// open closes the sections before the given one and opens it.
+// Skipped sections are written empty.
+func (w *Writer) open(section int) error {
+	if section < w.section {
+		return fmt.Errorf("snapshot section %s is already written", sections[section])
+	}
+
+	for w.section < section {
+		if err := w.next(); err != nil {
+			return fmt.Errorf("error writing snapshot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) next() error {
+	if w.section == -1 {
+		header, err := json.Marshal(w.exportedAt)
+		if err != nil {
+			return err
+		}
+
+		if _, err = fmt.Fprintf(w.w, `{"version":%d,"exportedAt":%s`, domain.SnapshotVersion, header); err != nil {
+			return err
+		}
+	} else if err := w.w.WriteByte(']'); err != nil {
+		return err
+	}
+
+	w.section++
+	w.empty = true
+
+	if w.section == len(sections) {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(w.w, `,"%s":[`, sections[w.section])
+
+	return err
+}
@@ -0,0 +1,150 @@
+// Package cache keeps teams and users read by the services in process memory.
+package cache
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+)
+
+// Invalidation names the entries changed by a mutation.
+// All is set by bulk mutations and drops every entry.
+type Invalidation struct {
+	TeamName string `json:"team_name,omitempty"`
+	UserID   string `json:"user_id,omitempty"`
+	All      bool   `json:"all,omitempty"`
+}
+
+// Notifier delivers invalidations to other app instances. It is called
+// once the mutation commits and logs the errors itself.
+type Notifier interface {
+	Notify(ctx context.Context, inv Invalidation)
+}
+
+// Cache is a read-through cache of teams with members and of users.
+// A nil *Cache reports zero stats.
+type Cache struct {
+	teams *lru[string, domain.TeamUpsert]
+	users *lru[string, domain.User]
+
+	// generation changes on every invalidation, so a load that started
+	// before a commit and finished after its invalidation is not cached.
+	generation atomic.Uint64
+
+	teamHits   atomic.Uint64
+	teamMisses atomic.Uint64
+	userHits   atomic.Uint64
+	userMisses atomic.Uint64
+}
+
+// New returns a cache of up to size teams and size users, each kept for ttl.
+func New(size int, ttl time.Duration) *Cache {
+	return &Cache{
+		teams: newLRU[string, domain.TeamUpsert](size, ttl),
+		users: newLRU[string, domain.User](size, ttl),
+	}
+}
+
+func cloneTeam(team domain.TeamUpsert) domain.TeamUpsert {
+	team.Members = slices.Clone(team.Members)
+	return team
+}
+
+func (c *Cache) getTeam(
+	ctx context.Context,
+	teamName string,
+	load func(ctx context.Context, teamName string) (domain.TeamUpsert, error),
+) (domain.TeamUpsert, error) {
+	if team, ok := c.teams.get(teamName); ok {
+		c.teamHits.Add(1)
+		return cloneTeam(team), nil
+	}
+
+	c.teamMisses.Add(1)
+
+	generation := c.generation.Load()
+
+	team, err := load(ctx, teamName)
+	if err != nil {
+		return domain.TeamUpsert{}, err
+	}
+
+	if c.generation.Load() == generation {
+		c.teams.add(teamName, cloneTeam(team))
+	}
+
+	return team, nil
+}
+
+func (c *Cache) getUser(
+	ctx context.Context,
+	userID string,
+	load func(ctx context.Context, userID string) (domain.User, error),
+) (domain.User, error) {
+	if user, ok := c.users.get(userID); ok {
+		c.userHits.Add(1)
+		return user, nil
+	}
+
+	c.userMisses.Add(1)
+
+	generation := c.generation.Load()
+
+	user, err := load(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if c.generation.Load() == generation {
+		c.users.add(userID, user)
+	}
+
+	return user, nil
+}
+
+// Invalidate drops the named team and user, and every cached team
+// the user is a member of.
+func (c *Cache) Invalidate(inv Invalidation) {
+	c.generation.Add(1)
+
+	if inv.All {
+		c.teams.removeFunc(func(string, domain.TeamUpsert) bool { return true })
+		c.users.removeFunc(func(string, domain.User) bool { return true })
+
+		return
+	}
+
+	if inv.TeamName != "" {
+		c.teams.remove(inv.TeamName)
+		c.users.removeFunc(func(_ string, user domain.User) bool {
+			return user.TeamName == inv.TeamName
+		})
+	}
+
+	if inv.UserID != "" {
+		c.users.remove(inv.UserID)
+		c.teams.removeFunc(func(_ string, team domain.TeamUpsert) bool {
+			return slices.ContainsFunc(team.Members, func(member domain.TeamMember) bool {
+				return member.UserID == inv.UserID
+			})
+		})
+	}
+}
+
+func (c *Cache) Stats() store.CacheStats {
+	if c == nil {
+		return store.CacheStats{}
+	}
+
+	return store.CacheStats{
+		Enabled:    true,
+		TeamHits:   c.teamHits.Load(),
+		TeamMisses: c.teamMisses.Load(),
+		UserHits:   c.userHits.Load(),
+		UserMisses: c.userMisses.Load(),
+	}
+}
@@ -0,0 +1,178 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/cache"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory/memorytest"
+)
+
+type fixture struct {
+	*memorytest.Backend
+
+	cache   *cache.Cache
+	factory *cache.RepoFactory[memory.Executor]
+}
+
+func newFixture(t *testing.T, ttl time.Duration) *fixture {
+	t.Helper()
+
+	b := memorytest.NewBackend()
+	b.SeedTeams(t, memorytest.Team("backend", memorytest.Member("u1", true), memorytest.Member("u2", true)))
+
+	c := cache.New(16, ttl)
+
+	return &fixture{
+		Backend: b,
+		cache:   c,
+		factory: cache.NewRepoFactory[memory.Executor](b.RepoFactory, b.Store, c, nil),
+	}
+}
+
+func (f *fixture) getUser(t *testing.T, userID string) domain.User {
+	t.Helper()
+
+	user, err := f.factory.UserRepository(f.Store).GetByID(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("get user %s: %v", userID, err)
+	}
+
+	return user
+}
+
+func (f *fixture) checkStats(t *testing.T, want store.CacheStats) {
+	t.Helper()
+
+	want.Enabled = true
+
+	if got := f.cache.Stats(); got != want {
+		t.Errorf("got stats %+v, want %+v", got, want)
+	}
+}
+
+func TestPrimaryReadsHitCache(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	ctx := context.Background()
+
+	f.getUser(t, "u1")
+	f.getUser(t, "u1")
+
+	for range 2 {
+		if _, err := f.factory.TeamRepository(f.Store).GetTeamWithMembers(ctx, "backend"); err != nil {
+			t.Fatalf("get team: %v", err)
+		}
+	}
+
+	f.checkStats(t, store.CacheStats{TeamHits: 1, TeamMisses: 1, UserHits: 1, UserMisses: 1})
+
+	// Reads in a transaction see its own snapshot and skip the cache.
+	f.Seed(t, func(ctx context.Context, tx memory.Executor) error {
+		_, err := f.factory.UserRepository(tx).GetByID(ctx, "u1")
+		return err
+	})
+
+	f.checkStats(t, store.CacheStats{TeamHits: 1, TeamMisses: 1, UserHits: 1, UserMisses: 1})
+}
+
+func TestEntriesExpire(t *testing.T) {
+	f := newFixture(t, time.Millisecond)
+
+	f.getUser(t, "u1")
+	time.Sleep(5 * time.Millisecond)
+	f.getUser(t, "u1")
+
+	f.checkStats(t, store.CacheStats{UserMisses: 2})
+}
+
+func TestInvalidateAfterCommit(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+
+	if !f.getUser(t, "u1").IsActive {
+		t.Fatal("u1 is not active")
+	}
+
+	err := f.TxManager.TxWrapper(ctx, func(ctx context.Context, tx memory.Executor) error {
+		if err := f.factory.UserRepository(tx).SetIsActive(ctx, "u1", false); err != nil {
+			return err
+		}
+
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("got error %v, want %v", err, errAbort)
+	}
+
+	if !f.getUser(t, "u1").IsActive {
+		t.Error("rolled back change dropped the cached user")
+	}
+
+	f.checkStats(t, store.CacheStats{UserHits: 1, UserMisses: 1})
+
+	f.Seed(t, func(ctx context.Context, tx memory.Executor) error {
+		return f.factory.UserRepository(tx).SetIsActive(ctx, "u1", false)
+	})
+
+	if f.getUser(t, "u1").IsActive {
+		t.Error("committed change did not drop the cached user")
+	}
+
+	f.checkStats(t, store.CacheStats{UserHits: 1, UserMisses: 2})
+}
+
+func TestInvalidateUserDropsTeams(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	ctx := context.Background()
+	teams := f.factory.TeamRepository(f.Store)
+
+	if _, err := teams.GetTeamWithMembers(ctx, "backend"); err != nil {
+		t.Fatalf("get team: %v", err)
+	}
+
+	f.Seed(t, func(ctx context.Context, tx memory.Executor) error {
+		return f.factory.UserRepository(tx).SetIsActive(ctx, "u2", false)
+	})
+
+	team, err := teams.GetTeamWithMembers(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get team: %v", err)
+	}
+
+	for _, member := range team.Members {
+		if member.UserID == "u2" && member.IsActive {
+			t.Error("team still lists u2 as active")
+		}
+	}
+
+	f.checkStats(t, store.CacheStats{TeamMisses: 2})
+}
+
+func TestSoftDeleteTeamDropsMembers(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	ctx := context.Background()
+
+	f.getUser(t, "u1")
+
+	f.Seed(t, func(ctx context.Context, tx memory.Executor) error {
+		return f.factory.TeamRepository(tx).SoftDeleteTeam(ctx, "backend", time.Now())
+	})
+
+	_, err := f.factory.UserRepository(f.Store).GetByID(ctx, "u1")
+	if !domain.IsErrorCode(err, domain.ErrCodeNotFound) {
+		t.Fatalf("get member of deleted team: got error %v, want %s", err, domain.ErrCodeNotFound)
+	}
+
+	f.Seed(t, func(ctx context.Context, tx memory.Executor) error {
+		return f.factory.TeamRepository(tx).RestoreTeam(ctx, "backend")
+	})
+
+	f.getUser(t, "u1")
+	f.checkStats(t, store.CacheStats{UserMisses: 3})
+}
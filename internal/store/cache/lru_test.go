@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newLRU[string, int](2, time.Minute)
+	c.add("a", 1)
+	c.add("b", 2)
+
+	// Reading a makes b the least recently used entry.
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("got no entry for a")
+	}
+
+	c.add("c", 3)
+
+	if _, ok := c.get("b"); ok {
+		t.Error("got entry for b, want it evicted")
+	}
+
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if got, ok := c.get(key); !ok || got != want {
+			t.Errorf("got %d, %v for %s, want %d", got, ok, key, want)
+		}
+	}
+}
+
+func TestLRUAddReplacesEntry(t *testing.T) {
+	c := newLRU[string, int](2, time.Minute)
+	c.add("a", 1)
+	c.add("b", 2)
+	c.add("a", 10)
+	c.add("c", 3)
+
+	if got, ok := c.get("a"); !ok || got != 10 {
+		t.Errorf("got %d, %v for a, want the replaced value kept", got, ok)
+	}
+
+	if _, ok := c.get("b"); ok {
+		t.Error("got entry for b, want it evicted")
+	}
+}
+
+func TestLRURemove(t *testing.T) {
+	c := newLRU[string, int](4, time.Minute)
+	c.add("team:a", 1)
+	c.add("team:b", 2)
+	c.add("user:a", 3)
+
+	c.remove("user:a")
+	c.removeFunc(func(key string, value int) bool { return strings.HasPrefix(key, "team:") && value > 1 })
+
+	if len(c.items) != 1 || c.order.Len() != 1 {
+		t.Fatalf("got %d items and %d list entries, want 1", len(c.items), c.order.Len())
+	}
+
+	if _, ok := c.get("team:a"); !ok {
+		t.Error("got no entry for team:a, want it kept")
+	}
+}
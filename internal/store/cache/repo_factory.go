@@ -0,0 +1,244 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/repository"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+)
+
+type backendRepoFactory[E comparable] interface {
+	TeamRepository(exec E) repository.TeamRepository
+	UserRepository(exec E) repository.UserRepository
+	PullRequestRepository(exec E) repository.PullRequestRepository
+	IdempotencyRepository(exec E) repository.IdempotencyRepository
+	ArchiveRepository(exec E) repository.ArchiveRepository
+	SnapshotRepository(exec E) repository.SnapshotRepository
+}
+
+// RepoFactory puts the cache in front of the team and user repositories
+// of a storage backend. The other repositories are passed through.
+//
+// Only reads through the primary executor use the cache: transactions
+// read their own snapshot, which may be rolled back, and a replica may
+// lag behind the invalidations.
+type RepoFactory[E comparable] struct {
+	backendRepoFactory[E]
+
+	primary  E
+	cache    *Cache
+	notifier Notifier
+}
+
+// NewRepoFactory wraps backend, whose reads through primary are cached.
+// notifier may be nil for a single instance.
+func NewRepoFactory[E comparable](
+	backend backendRepoFactory[E],
+	primary E,
+	cache *Cache,
+	notifier Notifier,
+) *RepoFactory[E] {
+	return &RepoFactory[E]{
+		backendRepoFactory: backend,
+		primary:            primary,
+		cache:              cache,
+		notifier:           notifier,
+	}
+}
+
+func (f *RepoFactory[E]) TeamRepository(exec E) repository.TeamRepository {
+	return &teamRepo[E]{
+		TeamRepository: f.backendRepoFactory.TeamRepository(exec),
+		factory:        f,
+		cached:         exec == f.primary,
+	}
+}
+
+func (f *RepoFactory[E]) UserRepository(exec E) repository.UserRepository {
+	return &userRepo[E]{
+		UserRepository: f.backendRepoFactory.UserRepository(exec),
+		factory:        f,
+		cached:         exec == f.primary,
+	}
+}
+
+func (f *RepoFactory[E]) SnapshotRepository(exec E) repository.SnapshotRepository {
+	return &snapshotRepo[E]{
+		SnapshotRepository: f.backendRepoFactory.SnapshotRepository(exec),
+		factory:            f,
+	}
+}
+
+// invalidate drops the entries once the mutation commits: dropping them
+// earlier would let a concurrent read cache the old state again until the
+// TTL.
+func (f *RepoFactory[E]) invalidate(ctx context.Context, inv Invalidation) {
+	store.AfterCommit(ctx, func() {
+		f.cache.Invalidate(inv)
+
+		if f.notifier != nil {
+			f.notifier.Notify(ctx, inv)
+		}
+	})
+}
+
+type teamRepo[E comparable] struct {
+	repository.TeamRepository
+
+	factory *RepoFactory[E]
+	cached  bool
+}
+
+func (r *teamRepo[E]) InsertTeam(ctx context.Context, teamName string) error {
+	if err := r.TeamRepository.InsertTeam(ctx, teamName); err != nil {
+		return err
+	}
+
+	r.factory.invalidate(ctx, Invalidation{TeamName: teamName})
+
+	return nil
+}
+
+// SoftDeleteTeam also deletes the members, so their cached copies are dropped with the team.
+func (r *teamRepo[E]) SoftDeleteTeam(ctx context.Context, teamName string, deletedAt time.Time) error {
+	if err := r.TeamRepository.SoftDeleteTeam(ctx, teamName, deletedAt); err != nil {
+		return err
+	}
+
+	r.factory.invalidate(ctx, Invalidation{TeamName: teamName})
+
+	return nil
+}
+
+func (r *teamRepo[E]) RestoreTeam(ctx context.Context, teamName string) error {
+	if err := r.TeamRepository.RestoreTeam(ctx, teamName); err != nil {
+		return err
+	}
+
+	r.factory.invalidate(ctx, Invalidation{TeamName: teamName})
+
+	return nil
+}
+
+func (r *teamRepo[E]) GetTeamWithMembers(ctx context.Context, teamName string) (domain.TeamUpsert, error) {
+	if !r.cached {
+		return r.TeamRepository.GetTeamWithMembers(ctx, teamName)
+	}
+
+	return r.factory.cache.getTeam(ctx, teamName, r.TeamRepository.GetTeamWithMembers)
+}
+
+type userRepo[E comparable] struct {
+	repository.UserRepository
+
+	factory *RepoFactory[E]
+	cached  bool
+}
+
+func (r *userRepo[E]) GetByID(ctx context.Context, userID string) (domain.User, error) {
+	if !r.cached {
+		return r.UserRepository.GetByID(ctx, userID)
+	}
+
+	return r.factory.cache.getUser(ctx, userID, r.UserRepository.GetByID)
+}
+
+func (r *userRepo[E]) UpsertUser(ctx context.Context, user domain.User) error {
+	if err := r.UserRepository.UpsertUser(ctx, user); err != nil {
+		return err
+	}
+
+	r.factory.invalidate(ctx, Invalidation{TeamName: user.TeamName, UserID: user.ID})
+
+	return nil
+}
+
+func (r *userRepo[E]) SetIsActive(ctx context.Context, userID string, isActive bool) error {
+	if err := r.UserRepository.SetIsActive(ctx, userID, isActive); err != nil {
+		return err
+	}
+
+	r.factory.invalidate(ctx, Invalidation{UserID: userID})
+
+	return nil
+}
+
+func (r *userRepo[E]) SoftDeleteUser(ctx context.Context, userID string, deletedAt time.Time) error {
+	if err := r.UserRepository.SoftDeleteUser(ctx, userID, deletedAt); err != nil {
+		return err
+	}
+
+	r.factory.invalidate(ctx, Invalidation{UserID: userID})
+
+	return nil
+}
+
+// RestoreUser looks the user up after the restore: the team it returns to
+// may be cached without it.
+func (r *userRepo[E]) RestoreUser(ctx context.Context, userID string) error {
+	if err := r.UserRepository.RestoreUser(ctx, userID); err != nil {
+		return err
+	}
+
+	user, err := r.UserRepository.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	r.factory.invalidate(ctx, Invalidation{TeamName: user.TeamName, UserID: userID})
+
+	return nil
+}
+
+// snapshotRepo drops the whole cache once, after the commit of an import
+// that wrote anything.
+type snapshotRepo[E comparable] struct {
+	repository.SnapshotRepository
+
+	factory     *RepoFactory[E]
+	invalidated bool
+}
+
+func (r *snapshotRepo[E]) invalidateAll(ctx context.Context) {
+	if r.invalidated {
+		return
+	}
+
+	r.invalidated = true
+
+	r.factory.invalidate(ctx, Invalidation{All: true})
+}
+
+func (r *snapshotRepo[E]) DeleteAll(ctx context.Context) error {
+	if err := r.SnapshotRepository.DeleteAll(ctx); err != nil {
+		return err
+	}
+
+	r.invalidateAll(ctx)
+
+	return nil
+}
+
+func (r *snapshotRepo[E]) UpsertTeam(ctx context.Context, team domain.SnapshotTeam) (bool, error) {
+	created, err := r.SnapshotRepository.UpsertTeam(ctx, team)
+	if err != nil {
+		return false, err
+	}
+
+	r.invalidateAll(ctx)
+
+	return created, nil
+}
+
+func (r *snapshotRepo[E]) UpsertUser(ctx context.Context, user domain.User) (bool, error) {
+	created, err := r.SnapshotRepository.UpsertUser(ctx, user)
+	if err != nil {
+		return false, err
+	}
+
+	r.invalidateAll(ctx)
+
+	return created, nil
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"sync"
+)
+
+type commitHooksKey struct{}
+
+// commitHooks are the functions to run once the transaction commits.
+type commitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// WithCommitHooks returns the context for one attempt of a transaction and
+// the function the TxManager calls after the commit. Hooks of an attempt
+// that is rolled back are dropped with its context.
+func WithCommitHooks(ctx context.Context) (context.Context, func()) {
+	hooks := &commitHooks{}
+
+	return context.WithValue(ctx, commitHooksKey{}, hooks), func() {
+		hooks.mu.Lock()
+		fns := hooks.fns
+		hooks.fns = nil
+		hooks.mu.Unlock()
+
+		for _, fn := range fns {
+			fn()
+		}
+	}
+}
+
+// AfterCommit runs fn once the transaction of ctx commits, or right away
+// outside of transactions.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(commitHooksKey{}).(*commitHooks)
+	if !ok {
+		fn()
+		return
+	}
+
+	hooks.mu.Lock()
+	hooks.fns = append(hooks.fns, fn)
+	hooks.mu.Unlock()
+}
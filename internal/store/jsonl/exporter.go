@@ -0,0 +1,87 @@
+// Package jsonl exports archived data to JSON Lines files.
+package jsonl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type pullRequestRecord struct {
+	ID                string     `json:"pull_request_id"`
+	Name              string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	CreatedAt         time.Time  `json:"created_at"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+	Version           int64      `json:"version"`
+	ExportedAt        time.Time  `json:"exported_at"`
+}
+
+// Exporter appends pull requests to a file per day named
+// pull_requests-YYYY-MM-DD.jsonl in dir.
+type Exporter struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewExporter(dir string) *Exporter {
+	return &Exporter{dir: dir}
+}
+
+// Export returns once the records are flushed to disk.
+func (e *Exporter) Export(_ context.Context, pullRequests []domain.PullRequest) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := os.MkdirAll(e.dir, 0o750); err != nil {
+		return fmt.Errorf("error creating export dir: %w", err)
+	}
+
+	now := time.Now().UTC()
+	path := filepath.Join(e.dir, "pull_requests-"+now.Format(time.DateOnly)+".jsonl")
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", path, err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+
+	for _, pr := range pullRequests {
+		err = encoder.Encode(pullRequestRecord{
+			ID:                pr.ID,
+			Name:              pr.Name,
+			AuthorID:          pr.AuthorID,
+			Status:            string(pr.Status),
+			AssignedReviewers: pr.AssignedReviewers,
+			CreatedAt:         pr.CreatedAt,
+			MergedAt:          pr.MergedAt,
+			Version:           pr.Version,
+			ExportedAt:        now,
+		})
+		if err != nil {
+			return fmt.Errorf("error encoding pull request %s: %w", pr.ID, err)
+		}
+	}
+
+	if err = writer.Flush(); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	if err = file.Sync(); err != nil {
+		return fmt.Errorf("error syncing %s: %w", path, err)
+	}
+
+	return nil
+}
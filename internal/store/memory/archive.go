@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type ArchiveRepo struct {
+	exec Executor
+}
+
+func NewArchiveRepo(exec Executor) *ArchiveRepo {
+	return &ArchiveRepo{exec: exec}
+}
+
+// ListMergedBefore returns up to limit pull requests merged before the given time, oldest first.
+func (r *ArchiveRepo) ListMergedBefore(
+	_ context.Context,
+	before time.Time,
+	limit int,
+) ([]domain.PullRequest, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	var pullRequests []domain.PullRequest
+
+	for _, pr := range st.pullRequests {
+		if pr.Status == domain.PRStatusMerged && pr.MergedAt != nil && pr.MergedAt.Before(before) {
+			pullRequests = append(pullRequests, clonePullRequest(pr))
+		}
+	}
+
+	slices.SortFunc(pullRequests, func(a, b domain.PullRequest) int {
+		return a.MergedAt.Compare(*b.MergedAt)
+	})
+
+	if len(pullRequests) > limit {
+		pullRequests = pullRequests[:limit]
+	}
+
+	return pullRequests, nil
+}
+
+// ArchivePullRequests moves pull requests with their reviewers to the archive.
+func (r *ArchiveRepo) ArchivePullRequests(_ context.Context, pullRequestIDs []string) error {
+	return r.archive(pullRequestIDs, true)
+}
+
+func (r *ArchiveRepo) TombstonePullRequests(_ context.Context, pullRequestIDs []string) error {
+	return r.archive(pullRequestIDs, false)
+}
+
+func (r *ArchiveRepo) archive(pullRequestIDs []string, withReviewers bool) error {
+	tx, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	archivedAt := time.Now()
+
+	for _, pullRequestID := range pullRequestIDs {
+		pr, ok := tx.pullRequests[pullRequestID]
+		if !ok {
+			continue
+		}
+
+		pr = clonePullRequest(pr)
+		pr.ArchivedAt = &archivedAt
+
+		if !withReviewers {
+			pr.AssignedReviewers = nil
+		}
+
+		set(tx, tx.archived, pullRequestID, pr)
+		remove(tx, tx.pullRequests, pullRequestID)
+	}
+
+	return nil
+}
+
+func (r *ArchiveRepo) GetByID(_ context.Context, pullRequestID string) (domain.PullRequest, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	pr, ok := st.archived[pullRequestID]
+	if !ok {
+		return domain.PullRequest{},
+			domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("archived pull request %s not found", pullRequestID))
+	}
+
+	return clonePullRequest(pr), nil
+}
+
+func (r *ArchiveRepo) ListReviewPRs(_ context.Context, userID string) ([]domain.PullRequest, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	var pullRequests []domain.PullRequest
+
+	for _, pr := range st.archived {
+		if slices.Contains(pr.AssignedReviewers, userID) {
+			pullRequests = append(pullRequests, clonePullRequest(pr))
+		}
+	}
+
+	sortNewestFirst(pullRequests)
+
+	return pullRequests, nil
+}
+
+func (r *ArchiveRepo) Stats(_ context.Context) (domain.PullRequestStats, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	stats := domain.PullRequestStats{Archived: len(st.archived), Merged: len(st.archived)}
+	assignments := make(map[string]int)
+
+	for _, pr := range st.pullRequests {
+		if pr.DeletedAt != nil {
+			continue
+		}
+
+		if pr.Status == domain.PRStatusMerged {
+			stats.Merged++
+		} else {
+			stats.Open++
+		}
+
+		for _, userID := range pr.AssignedReviewers {
+			assignments[userID]++
+		}
+	}
+
+	for _, pr := range st.archived {
+		for _, userID := range pr.AssignedReviewers {
+			assignments[userID]++
+		}
+	}
+
+	stats.Reviewers = make([]domain.ReviewerStats, 0, len(assignments))
+	for userID, n := range assignments {
+		stats.Reviewers = append(stats.Reviewers, domain.ReviewerStats{UserID: userID, Assignments: n})
+	}
+
+	slices.SortFunc(stats.Reviewers, func(a, b domain.ReviewerStats) int {
+		return cmp.Or(cmp.Compare(b.Assignments, a.Assignments), cmp.Compare(a.UserID, b.UserID))
+	})
+
+	return stats, nil
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type IdempotencyRepo struct {
+	exec Executor
+}
+
+func NewIdempotencyRepo(exec Executor) *IdempotencyRepo {
+	return &IdempotencyRepo{exec: exec}
+}
+
+func idempotencyKeyNotFound(key string) error {
+	return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("idempotency key %s not found", key))
+}
+
+func (r *IdempotencyRepo) Reserve(_ context.Context, record domain.IdempotencyRecord) (bool, error) {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return false, err
+	}
+
+	if existing, ok := st.idempotency[record.Key]; ok && !existing.ExpiresAt.Before(time.Now()) {
+		return false, nil
+	}
+
+	set(st, st.idempotency, record.Key, domain.IdempotencyRecord{
+		Key:         record.Key,
+		Fingerprint: record.Fingerprint,
+		ExpiresAt:   record.ExpiresAt,
+	})
+
+	return true, nil
+}
+
+func (r *IdempotencyRepo) GetByKey(_ context.Context, key string) (domain.IdempotencyRecord, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	record, ok := st.idempotency[key]
+	if !ok || record.ExpiresAt.Before(time.Now()) {
+		return domain.IdempotencyRecord{}, idempotencyKeyNotFound(key)
+	}
+
+	return record, nil
+}
+
+func (r *IdempotencyRepo) Complete(_ context.Context, record domain.IdempotencyRecord) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	existing, ok := st.idempotency[record.Key]
+	// A request that outlived its lease must not complete the key taken over by a retry.
+	if !ok || existing.Fingerprint != record.Fingerprint || existing.Completed() {
+		return idempotencyKeyNotFound(record.Key)
+	}
+
+	existing.StatusCode = record.StatusCode
+	existing.ContentType = record.ContentType
+	existing.ResponseBody = record.ResponseBody
+	existing.ETag = record.ETag
+	existing.ExpiresAt = record.ExpiresAt
+	set(st, st.idempotency, record.Key, existing)
+
+	return nil
+}
+
+func (r *IdempotencyRepo) Delete(_ context.Context, key string) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	remove(st, st.idempotency, key)
+
+	return nil
+}
+
+func (r *IdempotencyRepo) DeleteExpired(_ context.Context) (int64, error) {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+
+	now := time.Now()
+	for key, record := range st.idempotency {
+		if record.ExpiresAt.Before(now) {
+			remove(st, st.idempotency, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,138 @@
+// Package memorytest provides fixtures for tests that run the services on
+// the memory backend.
+package memorytest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/memory"
+)
+
+// Publisher records the published events.
+type Publisher struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func (p *Publisher) Publish(_ context.Context, events ...domain.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, events...)
+}
+
+// Events returns the events published so far.
+func (p *Publisher) Events() []domain.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]domain.Event(nil), p.events...)
+}
+
+// Count returns the number of published events of the type.
+func (p *Publisher) Count(eventType domain.EventType) int {
+	n := 0
+
+	for _, e := range p.Events() {
+		if e.Type == eventType {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Policy is a fixed assignment policy.
+type Policy domain.AssignmentPolicy
+
+func (p Policy) Policy() domain.AssignmentPolicy {
+	return domain.AssignmentPolicy(p)
+}
+
+// Backend is an empty memory store with its transaction manager and
+// repository factory, ready to be passed to the services.
+type Backend struct {
+	Store       *memory.Store
+	TxManager   *memory.TxManager
+	RepoFactory *memory.RepoFactory
+}
+
+func NewBackend() *Backend {
+	st := memory.NewStore()
+
+	return &Backend{
+		Store:       st,
+		TxManager:   memory.NewTxManager(st),
+		RepoFactory: memory.NewRepoFactory(),
+	}
+}
+
+// Seed runs fn in a transaction and fails the test when it returns an error.
+func (b *Backend) Seed(t testing.TB, fn func(ctx context.Context, tx memory.Executor) error) {
+	t.Helper()
+
+	if err := b.TxManager.TxWrapper(context.Background(), fn); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+}
+
+// SeedTeams inserts the teams with their members.
+func (b *Backend) SeedTeams(t testing.TB, teams ...domain.TeamUpsert) {
+	t.Helper()
+
+	b.Seed(t, func(ctx context.Context, tx memory.Executor) error {
+		for _, team := range teams {
+			if err := b.RepoFactory.TeamRepository(tx).InsertTeam(ctx, team.Name); err != nil {
+				return err
+			}
+
+			for _, member := range team.Members {
+				err := b.RepoFactory.UserRepository(tx).UpsertUser(ctx, domain.User{
+					ID:       member.UserID,
+					Username: member.Username,
+					TeamName: team.Name,
+					IsActive: member.IsActive,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// SeedPullRequest inserts an open pull request with the given reviewers.
+func (b *Backend) SeedPullRequest(t testing.TB, prID, authorID string, reviewers ...string) {
+	t.Helper()
+
+	b.Seed(t, func(ctx context.Context, tx memory.Executor) error {
+		repo := b.RepoFactory.PullRequestRepository(tx)
+
+		err := repo.InsertPullRequest(ctx, domain.PullRequest{ID: prID, Name: prID, AuthorID: authorID})
+		if err != nil {
+			return err
+		}
+
+		for _, reviewerID := range reviewers {
+			if err = repo.AddReviewer(ctx, prID, reviewerID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func Team(name string, members ...domain.TeamMember) domain.TeamUpsert {
+	return domain.TeamUpsert{Name: name, Members: members}
+}
+
+// Member returns a team member whose username is the user ID.
+func Member(userID string, isActive bool) domain.TeamMember {
+	return domain.TeamMember{UserID: userID, Username: userID, IsActive: isActive}
+}
@@ -0,0 +1,246 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type PullRequestRepo struct {
+	exec Executor
+}
+
+func NewPullRequestRepo(exec Executor) *PullRequestRepo {
+	return &PullRequestRepo{exec: exec}
+}
+
+func pullRequestNotFound(pullRequestID string) error {
+	return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("pull request %s not found", pullRequestID))
+}
+
+func (r *PullRequestRepo) InsertPullRequest(_ context.Context, pullRequest domain.PullRequest) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := st.pullRequests[pullRequest.ID]; ok {
+		return domain.NewError(
+			domain.ErrCodePRExists,
+			fmt.Sprintf("pull request %s already exists", pullRequest.ID),
+		)
+	}
+
+	if _, ok := st.users[pullRequest.AuthorID]; !ok {
+		return fmt.Errorf("author %s of pull request %s does not exist", pullRequest.AuthorID, pullRequest.ID)
+	}
+
+	set(st, st.pullRequests, pullRequest.ID, domain.PullRequest{
+		ID:        pullRequest.ID,
+		Name:      pullRequest.Name,
+		AuthorID:  pullRequest.AuthorID,
+		Status:    domain.PRStatusOpen,
+		CreatedAt: time.Now(),
+		Version:   1,
+	})
+
+	return nil
+}
+
+func (r *PullRequestRepo) GetByID(_ context.Context, pullRequestID string) (domain.PullRequest, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	pr, ok := st.pullRequests[pullRequestID]
+	if !ok || pr.DeletedAt != nil {
+		return domain.PullRequest{}, pullRequestNotFound(pullRequestID)
+	}
+
+	return clonePullRequest(pr), nil
+}
+
+func (r *PullRequestRepo) GetByIDs(_ context.Context, pullRequestIDs []string) ([]domain.PullRequest, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	var pullRequests []domain.PullRequest
+
+	for pullRequestID, pr := range st.pullRequests {
+		if pr.DeletedAt == nil && slices.Contains(pullRequestIDs, pullRequestID) {
+			pullRequests = append(pullRequests, clonePullRequest(pr))
+		}
+	}
+
+	sortNewestFirst(pullRequests)
+
+	return pullRequests, nil
+}
+
+func sortNewestFirst(pullRequests []domain.PullRequest) {
+	slices.SortFunc(pullRequests, func(a, b domain.PullRequest) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+}
+
+// GetByIDForUpdate is the same as GetByID: transactions never run concurrently.
+func (r *PullRequestRepo) GetByIDForUpdate(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
+	return r.GetByID(ctx, pullRequestID)
+}
+
+func (r *PullRequestRepo) IncrementVersion(_ context.Context, pullRequestID string) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	pr, ok := st.pullRequests[pullRequestID]
+	if !ok {
+		return pullRequestNotFound(pullRequestID)
+	}
+
+	pr.Version++
+	set(st, st.pullRequests, pullRequestID, pr)
+
+	return nil
+}
+
+func (r *PullRequestRepo) AddReviewer(_ context.Context, pullRequestID string, reviewerID string) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	pr, ok := st.pullRequests[pullRequestID]
+	if !ok {
+		return fmt.Errorf("pull request %s does not exist", pullRequestID)
+	}
+
+	if _, ok = st.users[reviewerID]; !ok {
+		return fmt.Errorf("reviewer %s does not exist", reviewerID)
+	}
+
+	if slices.Contains(pr.AssignedReviewers, reviewerID) {
+		return fmt.Errorf("reviewer %s is already assigned to pull request %s", reviewerID, pullRequestID)
+	}
+
+	pr.AssignedReviewers = append(slices.Clone(pr.AssignedReviewers), reviewerID)
+	set(st, st.pullRequests, pullRequestID, pr)
+
+	return nil
+}
+
+func (r *PullRequestRepo) RemoveReviewer(_ context.Context, pullRequestID string, reviewerID string) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	pr, ok := st.pullRequests[pullRequestID]
+	if !ok || !slices.Contains(pr.AssignedReviewers, reviewerID) {
+		return domain.NewError(domain.ErrCodeNotAssigned, "incomplete removal")
+	}
+
+	pr.AssignedReviewers = slices.DeleteFunc(slices.Clone(pr.AssignedReviewers), func(id string) bool {
+		return id == reviewerID
+	})
+	set(st, st.pullRequests, pullRequestID, pr)
+
+	return nil
+}
+
+func (r *PullRequestRepo) MergePullRequest(_ context.Context, pullRequest domain.PullRequest) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	pr, ok := st.pullRequests[pullRequest.ID]
+	if !ok {
+		return pullRequestNotFound(pullRequest.ID)
+	}
+
+	pr.Status = pullRequest.Status
+	pr.MergedAt = pullRequest.MergedAt
+	pr.Version++
+	set(st, st.pullRequests, pullRequest.ID, clonePullRequest(pr))
+
+	return nil
+}
+
+func (r *PullRequestRepo) SoftDeletePullRequest(_ context.Context, pullRequestID string, deletedAt time.Time) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	pr, ok := st.pullRequests[pullRequestID]
+	if !ok || pr.DeletedAt != nil {
+		return pullRequestNotFound(pullRequestID)
+	}
+
+	pr.DeletedAt = &deletedAt
+	pr.Version++
+	set(st, st.pullRequests, pullRequestID, pr)
+
+	return nil
+}
+
+func (r *PullRequestRepo) RestorePullRequest(_ context.Context, pullRequestID string) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	pr, ok := st.pullRequests[pullRequestID]
+	if !ok || pr.DeletedAt == nil {
+		return domain.NewError(
+			domain.ErrCodeNotFound,
+			fmt.Sprintf("deleted pull request %s not found", pullRequestID),
+		)
+	}
+
+	pr.DeletedAt = nil
+	pr.Version++
+	set(st, st.pullRequests, pullRequestID, pr)
+
+	return nil
+}
+
+func (r *PullRequestRepo) CountOpenByTeam(_ context.Context) (map[string]int, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	counts := make(map[string]int)
+
+	for _, pr := range st.pullRequests {
+		if pr.Status == domain.PRStatusOpen && pr.DeletedAt == nil {
+			counts[st.users[pr.AuthorID].TeamName]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *PullRequestRepo) CountOpenReviews(_ context.Context, userIDs []string) (map[string]int, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	counts := make(map[string]int, len(userIDs))
+
+	for _, pr := range st.pullRequests {
+		if pr.Status != domain.PRStatusOpen || pr.DeletedAt != nil {
+			continue
+		}
+
+		for _, reviewerID := range pr.AssignedReviewers {
+			if slices.Contains(userIDs, reviewerID) {
+				counts[reviewerID]++
+			}
+		}
+	}
+
+	return counts, nil
+}
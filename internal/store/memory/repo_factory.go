@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/repository"
+)
+
+type RepoFactory struct{}
+
+func NewRepoFactory() *RepoFactory {
+	return &RepoFactory{}
+}
+
+func (r *RepoFactory) TeamRepository(exec Executor) repository.TeamRepository {
+	return NewTeamRepo(exec)
+}
+
+func (r *RepoFactory) UserRepository(exec Executor) repository.UserRepository {
+	return NewUserRepo(exec)
+}
+
+func (r *RepoFactory) PullRequestRepository(exec Executor) repository.PullRequestRepository {
+	return NewPullRequestRepo(exec)
+}
+
+func (r *RepoFactory) IdempotencyRepository(exec Executor) repository.IdempotencyRepository {
+	return NewIdempotencyRepo(exec)
+}
+
+func (r *RepoFactory) ArchiveRepository(exec Executor) repository.ArchiveRepository {
+	return NewArchiveRepo(exec)
+}
+
+func (r *RepoFactory) SnapshotRepository(exec Executor) repository.SnapshotRepository {
+	return NewSnapshotRepo(exec)
+}
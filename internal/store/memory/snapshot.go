@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type SnapshotRepo struct {
+	exec Executor
+}
+
+func NewSnapshotRepo(exec Executor) *SnapshotRepo {
+	return &SnapshotRepo{exec: exec}
+}
+
+func (r *SnapshotRepo) ForEachTeam(_ context.Context, fn func(team domain.SnapshotTeam) error) error {
+	st, release := r.exec.acquire()
+	defer release()
+
+	for _, name := range slices.Sorted(maps.Keys(st.teams)) {
+		if err := fn(domain.SnapshotTeam{Name: name, DeletedAt: st.teams[name].deletedAt}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *SnapshotRepo) ForEachUser(_ context.Context, fn func(user domain.User) error) error {
+	st, release := r.exec.acquire()
+	defer release()
+
+	for _, id := range slices.Sorted(maps.Keys(st.users)) {
+		if err := fn(st.users[id]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *SnapshotRepo) ForEachPullRequest(_ context.Context, fn func(pr domain.PullRequest) error) error {
+	st, release := r.exec.acquire()
+	defer release()
+
+	for _, id := range slices.Sorted(maps.Keys(st.pullRequests)) {
+		if err := fn(clonePullRequest(st.pullRequests[id])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *SnapshotRepo) DeleteAll(_ context.Context) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	removeAll(st, st.teams)
+	removeAll(st, st.users)
+	removeAll(st, st.pullRequests)
+
+	for key, record := range st.idempotency {
+		if record.Completed() {
+			remove(st, st.idempotency, key)
+		}
+	}
+
+	return nil
+}
+
+// removeAll removes every row of the map so that the transaction can undo it.
+func removeAll[V any](tx *Tx, rows map[string]V) {
+	for key := range rows {
+		remove(tx, rows, key)
+	}
+}
+
+func (r *SnapshotRepo) UpsertTeam(_ context.Context, t domain.SnapshotTeam) (bool, error) {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return false, err
+	}
+
+	_, exists := st.teams[t.Name]
+	set(st, st.teams, t.Name, team{deletedAt: t.DeletedAt})
+
+	return !exists, nil
+}
+
+func (r *SnapshotRepo) UpsertUser(_ context.Context, user domain.User) (bool, error) {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := st.teams[user.TeamName]; !ok {
+		return false, fmt.Errorf("team %s of user %s does not exist", user.TeamName, user.ID)
+	}
+
+	_, exists := st.users[user.ID]
+	set(st, st.users, user.ID, user)
+
+	return !exists, nil
+}
+
+func (r *SnapshotRepo) UpsertPullRequest(_ context.Context, pr domain.PullRequest) (bool, error) {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return false, err
+	}
+
+	for _, userID := range append([]string{pr.AuthorID}, pr.AssignedReviewers...) {
+		if _, ok := st.users[userID]; !ok {
+			return false, fmt.Errorf("user %s of pull request %s does not exist", userID, pr.ID)
+		}
+	}
+
+	_, exists := st.pullRequests[pr.ID]
+	pr.ArchivedAt = nil
+	set(st, st.pullRequests, pr.ID, clonePullRequest(pr))
+
+	return !exists, nil
+}
@@ -0,0 +1,209 @@
+// Package memory implements the repositories on top of in-process maps.
+// Transactions run one at a time under the store lock and write to the maps
+// in place, keeping an undo log that restores the maps when they fail.
+// Reads outside of transactions wait for the running transaction.
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+var errWriteOutsideTx = errors.New("memory: writes require a transaction")
+
+// team keeps the deletion time of a team; deleted teams keep their names reserved.
+type team struct {
+	deletedAt *time.Time
+}
+
+type state struct {
+	teams        map[string]team
+	users        map[string]domain.User
+	pullRequests map[string]domain.PullRequest
+	idempotency  map[string]domain.IdempotencyRecord
+	archived     map[string]domain.PullRequest
+}
+
+func newState() *state {
+	return &state{
+		teams:        make(map[string]team),
+		users:        make(map[string]domain.User),
+		pullRequests: make(map[string]domain.PullRequest),
+		idempotency:  make(map[string]domain.IdempotencyRecord),
+		archived:     make(map[string]domain.PullRequest),
+	}
+}
+
+func clonePullRequest(pr domain.PullRequest) domain.PullRequest {
+	pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	if pr.MergedAt != nil {
+		mergedAt := *pr.MergedAt
+		pr.MergedAt = &mergedAt
+	}
+
+	if pr.ArchivedAt != nil {
+		archivedAt := *pr.ArchivedAt
+		pr.ArchivedAt = &archivedAt
+	}
+
+	if pr.DeletedAt != nil {
+		deletedAt := *pr.DeletedAt
+		pr.DeletedAt = &deletedAt
+	}
+
+	return pr
+}
+
+// Executor is the handle the repositories work on: the *Store for reads
+// outside of transactions or the *Tx of a transaction.
+type Executor interface {
+	// acquire returns the state and the function that releases it.
+	acquire() (*state, func())
+}
+
+// Store holds the state. It is also the executor for reads outside of
+// transactions.
+type Store struct {
+	mu    sync.RWMutex
+	state *state
+}
+
+func NewStore() *Store {
+	return &Store{state: newState()}
+}
+
+func (s *Store) acquire() (*state, func()) {
+	s.mu.RLock()
+	return s.state, s.mu.RUnlock
+}
+
+// Tx is the executor passed to the repositories inside a transaction.
+// It holds the store lock, so it reads the state without locking.
+type Tx struct {
+	*state
+
+	undo []func()
+}
+
+func (t *Tx) acquire() (*state, func()) {
+	return t.state, func() {}
+}
+
+// rollback undoes the writes of the transaction, latest first.
+func (t *Tx) rollback() {
+	for _, undo := range slices.Backward(t.undo) {
+		undo()
+	}
+
+	t.undo = nil
+}
+
+// set writes the row of the transaction and logs how to undo it.
+func set[V any](tx *Tx, rows map[string]V, key string, value V) {
+	previous, existed := rows[key]
+	tx.undo = append(tx.undo, func() {
+		if existed {
+			rows[key] = previous
+		} else {
+			delete(rows, key)
+		}
+	})
+
+	rows[key] = value
+}
+
+// remove deletes the row of the transaction and logs how to undo it.
+func remove[V any](tx *Tx, rows map[string]V, key string) {
+	previous, existed := rows[key]
+	if !existed {
+		return
+	}
+
+	tx.undo = append(tx.undo, func() { rows[key] = previous })
+
+	delete(rows, key)
+}
+
+type TxManager struct {
+	store *Store
+
+	transactions atomic.Uint64
+	commits      atomic.Uint64
+}
+
+func NewTxManager(s *Store) *TxManager {
+	return &TxManager{store: s}
+}
+
+func (m *TxManager) TxWrapper(ctx context.Context, fn func(ctx context.Context, tx Executor) error) error {
+	return m.TxWrapperWithOptions(ctx, store.TxOptions{}, fn)
+}
+
+// TxWrapperWithOptions runs transactions one at a time, which is at least as
+// strict as any requested isolation level, so the options are ignored.
+func (m *TxManager) TxWrapperWithOptions(
+	ctx context.Context,
+	opts store.TxOptions,
+	fn func(ctx context.Context, tx Executor) error,
+) (err error) {
+	m.transactions.Add(1)
+
+	ctx, span := store.StartTxSpan(ctx, opts)
+	defer func() { tracing.End(span, err) }()
+
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	m.store.mu.Lock()
+
+	tx := &Tx{state: m.store.state}
+	committed := false
+
+	defer func() {
+		if !committed {
+			tx.rollback()
+		}
+
+		m.store.mu.Unlock()
+	}()
+
+	txCtx, runCommitHooks := store.WithCommitHooks(ctx)
+
+	if err = fn(txCtx, tx); err != nil {
+		return err
+	}
+
+	committed = true
+
+	m.commits.Add(1)
+
+	runCommitHooks()
+
+	return nil
+}
+
+func (m *TxManager) Stats() store.TxStats {
+	return store.TxStats{
+		Transactions: m.transactions.Load(),
+		Commits:      m.commits.Load(),
+	}
+}
+
+// writeTxOf resolves an executor for writes, which are allowed only inside transactions.
+func writeTxOf(exec Executor) (*Tx, error) {
+	tx, ok := exec.(*Tx)
+	if !ok {
+		return nil, errWriteOutsideTx
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+// seed writes a team with two users, a pull request and an idempotency key.
+func seed(t *testing.T, m *TxManager) {
+	t.Helper()
+
+	err := m.TxWrapper(context.Background(), func(ctx context.Context, tx Executor) error {
+		if err := NewTeamRepo(tx).InsertTeam(ctx, "backend"); err != nil {
+			return err
+		}
+
+		for _, userID := range []string{"u1", "u2"} {
+			err := NewUserRepo(tx).UpsertUser(ctx, domain.User{ID: userID, TeamName: "backend", IsActive: true})
+			if err != nil {
+				return err
+			}
+		}
+
+		err := NewPullRequestRepo(tx).InsertPullRequest(ctx, domain.PullRequest{ID: "pr-1", AuthorID: "u1"})
+		if err != nil {
+			return err
+		}
+
+		_, err = NewIdempotencyRepo(tx).Reserve(ctx, domain.IdempotencyRecord{
+			Key:       "k",
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+}
+
+func TestTxWrapperRollsBack(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name string
+		// finish ends the transaction after its writes.
+		finish func() error
+	}{
+		{name: "error", finish: func() error { return errFailed }},
+		{name: "panic", finish: func() error { panic(errFailed) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := NewStore()
+			m := NewTxManager(st)
+			seed(t, m)
+
+			before := *st.state
+			before.teams = maps.Clone(st.state.teams)
+			before.users = maps.Clone(st.state.users)
+			before.pullRequests = maps.Clone(st.state.pullRequests)
+			before.idempotency = maps.Clone(st.state.idempotency)
+
+			func() {
+				defer func() { _ = recover() }()
+
+				_ = m.TxWrapper(context.Background(), func(ctx context.Context, tx Executor) error {
+					_ = NewTeamRepo(tx).InsertTeam(ctx, "frontend")
+					_ = NewUserRepo(tx).UpsertUser(ctx, domain.User{ID: "u1", TeamName: "frontend"})
+					_ = NewUserRepo(tx).SetIsActive(ctx, "u2", false)
+					_ = NewPullRequestRepo(tx).AddReviewer(ctx, "pr-1", "u2")
+					_ = NewPullRequestRepo(tx).IncrementVersion(ctx, "pr-1")
+					_ = NewIdempotencyRepo(tx).Delete(ctx, "k")
+
+					return tt.finish()
+				})
+			}()
+
+			if !maps.Equal(st.state.teams, before.teams) || !maps.Equal(st.state.users, before.users) {
+				t.Errorf("got teams %v users %v, want %v %v",
+					st.state.teams, st.state.users, before.teams, before.users)
+			}
+
+			if pr := st.state.pullRequests["pr-1"]; len(pr.AssignedReviewers) != 0 || pr.Version != 1 {
+				t.Errorf("got pull request %+v, want it unchanged", pr)
+			}
+
+			if _, ok := st.state.idempotency["k"]; !ok {
+				t.Errorf("deleted idempotency key was not restored")
+			}
+
+			// The store lock must be released.
+			if _, err := NewTeamRepo(st).GetTeamWithMembers(context.Background(), "backend"); err != nil {
+				t.Errorf("read after rollback: %v", err)
+			}
+		})
+	}
+}
+
+func TestWriteOutsideTx(t *testing.T) {
+	err := NewTeamRepo(NewStore()).InsertTeam(context.Background(), "backend")
+	if !errors.Is(err, errWriteOutsideTx) {
+		t.Fatalf("got error %v, want %v", err, errWriteOutsideTx)
+	}
+}
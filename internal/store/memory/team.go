@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type TeamRepo struct {
+	exec Executor
+}
+
+func NewTeamRepo(exec Executor) *TeamRepo {
+	return &TeamRepo{exec: exec}
+}
+
+func teamNotFound(teamName string) error {
+	return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("team %s not found", teamName))
+}
+
+func (r *TeamRepo) InsertTeam(_ context.Context, teamName string) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := st.teams[teamName]; ok {
+		return domain.NewError(domain.ErrCodeTeamExists, fmt.Sprintf("team %s already exists", teamName))
+	}
+
+	set(st, st.teams, teamName, team{})
+
+	return nil
+}
+
+func (r *TeamRepo) GetTeamWithMembers(_ context.Context, teamName string) (domain.TeamUpsert, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	if t, ok := st.teams[teamName]; !ok || t.deletedAt != nil {
+		return domain.TeamUpsert{}, teamNotFound(teamName)
+	}
+
+	var members []domain.TeamMember
+
+	for _, user := range st.users {
+		if user.TeamName == teamName && user.DeletedAt == nil {
+			members = append(members, domain.TeamMember{
+				UserID:   user.ID,
+				Username: user.Username,
+				IsActive: user.IsActive,
+			})
+		}
+	}
+
+	slices.SortFunc(members, func(a, b domain.TeamMember) int {
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+
+	return domain.TeamUpsert{
+		Name:    teamName,
+		Members: members,
+	}, nil
+}
+
+// SoftDeleteTeam marks the team and its members as deleted at the same time,
+// which lets RestoreTeam tell them apart from members deleted earlier.
+func (r *TeamRepo) SoftDeleteTeam(_ context.Context, teamName string, deletedAt time.Time) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	t, ok := st.teams[teamName]
+	if !ok || t.deletedAt != nil {
+		return teamNotFound(teamName)
+	}
+
+	set(st, st.teams, teamName, team{deletedAt: &deletedAt})
+
+	for id, user := range st.users {
+		if user.TeamName == teamName && user.DeletedAt == nil {
+			user.DeletedAt = &deletedAt
+			set(st, st.users, id, user)
+		}
+	}
+
+	return nil
+}
+
+// RestoreTeam restores the team and the members deleted together with it.
+func (r *TeamRepo) RestoreTeam(_ context.Context, teamName string) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	t, ok := st.teams[teamName]
+	if !ok || t.deletedAt == nil {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("deleted team %s not found", teamName))
+	}
+
+	set(st, st.teams, teamName, team{})
+
+	for id, user := range st.users {
+		if user.TeamName == teamName && user.DeletedAt != nil && user.DeletedAt.Equal(*t.deletedAt) {
+			user.DeletedAt = nil
+			set(st, st.users, id, user)
+		}
+	}
+
+	return nil
+}
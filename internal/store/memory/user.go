@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+)
+
+type UserRepo struct {
+	exec Executor
+}
+
+func NewUserRepo(exec Executor) *UserRepo {
+	return &UserRepo{exec: exec}
+}
+
+func userNotFound(userID string) error {
+	return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("user %s not found", userID))
+}
+
+// UpsertUser also restores the user if it was deleted.
+func (r *UserRepo) UpsertUser(_ context.Context, user domain.User) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := st.teams[user.TeamName]; !ok {
+		return fmt.Errorf("team %s of user %s does not exist", user.TeamName, user.ID)
+	}
+
+	user.DeletedAt = nil
+	set(st, st.users, user.ID, user)
+
+	return nil
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, userID string) (domain.User, error) {
+	user, err := r.GetByIDWithDeleted(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if user.DeletedAt != nil {
+		return domain.User{}, userNotFound(userID)
+	}
+
+	return user, nil
+}
+
+// GetByIDWithDeleted is the same as GetByID but also returns deleted users.
+func (r *UserRepo) GetByIDWithDeleted(_ context.Context, userID string) (domain.User, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	user, ok := st.users[userID]
+	if !ok {
+		return domain.User{}, userNotFound(userID)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepo) SetIsActive(_ context.Context, userID string, isActive bool) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	user, ok := st.users[userID]
+	if !ok || user.DeletedAt != nil {
+		return userNotFound(userID)
+	}
+
+	user.IsActive = isActive
+	set(st, st.users, userID, user)
+
+	return nil
+}
+
+func (r *UserRepo) SoftDeleteUser(_ context.Context, userID string, deletedAt time.Time) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	user, ok := st.users[userID]
+	if !ok || user.DeletedAt != nil {
+		return userNotFound(userID)
+	}
+
+	user.DeletedAt = &deletedAt
+	set(st, st.users, userID, user)
+
+	return nil
+}
+
+func (r *UserRepo) RestoreUser(_ context.Context, userID string) error {
+	st, err := writeTxOf(r.exec)
+	if err != nil {
+		return err
+	}
+
+	user, ok := st.users[userID]
+	if !ok || user.DeletedAt == nil {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("deleted user %s not found", userID))
+	}
+
+	user.DeletedAt = nil
+	set(st, st.users, userID, user)
+
+	return nil
+}
+
+func (r *UserRepo) ListReviewPRs(_ context.Context, userID string) ([]domain.PullRequest, error) {
+	st, release := r.exec.acquire()
+	defer release()
+
+	var pullRequests []domain.PullRequest
+
+	for _, pr := range st.pullRequests {
+		if pr.DeletedAt == nil && slices.Contains(pr.AssignedReviewers, userID) {
+			pullRequests = append(pullRequests, clonePullRequest(pr))
+		}
+	}
+
+	sortNewestFirst(pullRequests)
+
+	return pullRequests, nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/cache"
+)
+
+type CacheInvalidator interface {
+	Invalidate(inv cache.Invalidation)
+}
+
+// CacheBridge shares cache invalidations between app instances through
+// LISTEN/NOTIFY. Invalidations are sent once the mutation commits, so the
+// other instances drop the entries when the change is already visible.
+type CacheBridge struct {
+	pool    *pgxpool.Pool
+	local   CacheInvalidator
+	channel string
+}
+
+func NewCacheBridge(pool *pgxpool.Pool, local CacheInvalidator, channel string) *CacheBridge {
+	return &CacheBridge{
+		pool:    pool,
+		local:   local,
+		channel: channel,
+	}
+}
+
+// Notify sends inv to every instance listening on the channel. The entries
+// stay cached on the other instances until the TTL if it fails.
+func (b *CacheBridge) Notify(ctx context.Context, inv cache.Invalidation) {
+	payload, err := json.Marshal(inv)
+	if err != nil {
+		logging.FromContext(ctx).Error("error encoding invalidation", logging.Error(err))
+		return
+	}
+
+	_, err = b.pool.Exec(ctx, "SELECT pg_notify($1, $2)", b.channel, string(payload))
+	if err != nil {
+		logging.FromContext(ctx).Error("error notifying", slog.String("channel", b.channel), logging.Error(err))
+	}
+}
+
+// Listen blocks until ctx is done, reconnecting when the listening
+// connection is lost.
+func (b *CacheBridge) Listen(ctx context.Context) {
+	listenChannel(ctx, b.pool, b.channel, func(payload string) {
+		var inv cache.Invalidation
+		if err := json.Unmarshal([]byte(payload), &inv); err != nil {
+			logging.FromContext(ctx).Error("error decoding invalidation", logging.Error(err))
+			return
+		}
+
+		b.local.Invalidate(inv)
+	})
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...domain.Event)
+}
+
+type notifyPayload struct {
+	InstanceID    string           `json:"instance_id"`
+	Type          domain.EventType `json:"type"`
+	PullRequestID string           `json:"pull_request_id,omitempty"`
+	UserID        string           `json:"user_id,omitempty"`
+	OldUserID     string           `json:"old_user_id,omitempty"`
+	Reviewers     []string         `json:"reviewers,omitempty"`
+	TeamName      string           `json:"team_name,omitempty"`
+	IsActive      bool             `json:"is_active"`
+	OccurredAt    time.Time        `json:"occurred_at"`
+}
+
+// maxPendingNotifications bounds the queue of events waiting to be sent
+// while the database is slow or unavailable.
+const maxPendingNotifications = 1024
+
+// EventBridge shares events between app instances through LISTEN/NOTIFY.
+// Published events are delivered to the local publisher right away and
+// queued for the channel; Run sends the queue off the request path.
+// Notifications from other instances are delivered to the local publisher
+// by Listen.
+type EventBridge struct {
+	pool       *pgxpool.Pool
+	local      EventPublisher
+	channel    string
+	instanceID string
+
+	mu      sync.Mutex
+	pending []string
+	wake    chan struct{}
+}
+
+func NewEventBridge(pool *pgxpool.Pool, local EventPublisher, channel string) (*EventBridge, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("error generating instance id: %w", err)
+	}
+
+	return &EventBridge{
+		pool:       pool,
+		local:      local,
+		channel:    channel,
+		instanceID: hex.EncodeToString(id),
+		wake:       make(chan struct{}, 1),
+	}, nil
+}
+
+func (b *EventBridge) Publish(ctx context.Context, events ...domain.Event) {
+	b.local.Publish(ctx, events...)
+
+	payloads := make([]string, 0, len(events))
+
+	for _, e := range events {
+		payload, err := json.Marshal(notifyPayload{
+			InstanceID:    b.instanceID,
+			Type:          e.Type,
+			PullRequestID: e.PullRequestID,
+			UserID:        e.UserID,
+			OldUserID:     e.OldUserID,
+			Reviewers:     e.Reviewers,
+			TeamName:      e.TeamName,
+			IsActive:      e.IsActive,
+			OccurredAt:    e.OccurredAt,
+		})
+		if err != nil {
+			logging.FromContext(ctx).Error("error encoding event", logging.Error(err))
+			continue
+		}
+
+		payloads = append(payloads, string(payload))
+	}
+
+	b.mu.Lock()
+	dropped := len(b.pending) + len(payloads) - maxPendingNotifications
+	if dropped > 0 {
+		payloads = payloads[:len(payloads)-dropped]
+	}
+	b.pending = append(b.pending, payloads...)
+	b.mu.Unlock()
+
+	if dropped > 0 {
+		logging.FromContext(ctx).Warn("event bridge queue is full, events dropped", slog.Int("dropped", dropped))
+	}
+
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends the queued events until ctx is done. Events still queued
+// at that point are not sent.
+func (b *EventBridge) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.wake:
+			if err := b.Flush(ctx); err != nil {
+				logging.FromContext(ctx).Error("error flushing events", logging.Error(err))
+			}
+		}
+	}
+}
+
+// Flush sends the queued events in a single round trip.
+func (b *EventBridge) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	payloads := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if len(payloads) == 0 {
+		return nil
+	}
+
+	_, err := b.pool.Exec(ctx,
+		"SELECT pg_notify($1, payload) FROM unnest($2::text[]) WITH ORDINALITY AS t(payload, n) ORDER BY n",
+		b.channel, payloads)
+	if err != nil {
+		return fmt.Errorf("error notifying %s, %d events lost: %w", b.channel, len(payloads), err)
+	}
+
+	return nil
+}
+
+// Listen blocks until ctx is done, reconnecting when the listening
+// connection is lost.
+func (b *EventBridge) Listen(ctx context.Context) {
+	listenChannel(ctx, b.pool, b.channel, func(rawPayload string) {
+		var payload notifyPayload
+		if err := json.Unmarshal([]byte(rawPayload), &payload); err != nil {
+			logging.FromContext(ctx).Error("error decoding event", logging.Error(err))
+			return
+		}
+
+		if payload.InstanceID == b.instanceID {
+			return
+		}
+
+		b.local.Publish(ctx, domain.Event{
+			Type:          payload.Type,
+			PullRequestID: payload.PullRequestID,
+			UserID:        payload.UserID,
+			OldUserID:     payload.OldUserID,
+			Reviewers:     payload.Reviewers,
+			TeamName:      payload.TeamName,
+			IsActive:      payload.IsActive,
+			OccurredAt:    payload.OccurredAt,
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+)
+
+// listenChannel calls handle for every notification on channel. It blocks
+// until ctx is done, reconnecting when the listening connection is lost.
+func listenChannel(ctx context.Context, pool *pgxpool.Pool, channel string, handle func(payload string)) {
+	for ctx.Err() == nil {
+		err := listenOnce(ctx, pool, channel, handle)
+		if err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("error listening", slog.String("channel", channel), logging.Error(err))
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+func listenOnce(ctx context.Context, pool *pgxpool.Pool, channel string, handle func(payload string)) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring connection: %w", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+	if err != nil {
+		return fmt.Errorf("error listening %s: %w", channel, err)
+	}
+
+	// The connection must not go back to the pool still subscribed.
+	defer func() { _ = conn.Conn().Close(context.Background()) }()
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("error waiting for notification: %w", err)
+		}
+
+		handle(notification.Payload)
+	}
+}
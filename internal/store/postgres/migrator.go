@@ -0,0 +1,312 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationsLockID is the advisory lock key held while migrations run,
+// so only one app instance migrates at a time.
+const migrationsLockID = 4_631_275_290
+
+var ErrSchemaDirty = errors.New("schema is dirty after a failed migration, fix it manually")
+
+type Migration struct {
+	Version uint64
+	Name    string
+
+	up   string
+	down string
+}
+
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+// Migrator applies the migrations from fsys named <version>_<name>.up.sql
+// and <version>_<name>.down.sql. It keeps the version in the schema_migrations
+// table in the format of golang-migrate, so both tools can be used on one database.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	migrations, err := readMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+func readMigrations(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("error listing migrations: %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+
+	for _, fileName := range names {
+		base, direction, ok := strings.Cut(strings.TrimSuffix(fileName, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("unexpected migration file name %s", fileName)
+		}
+
+		versionPart, name, _ := strings.Cut(base, "_")
+
+		version, err := strconv.ParseUint(versionPart, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing migration version %s: %w", fileName, err)
+		}
+
+		body, err := fs.ReadFile(fsys, fileName)
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", fileName, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+
+		if direction == "up" {
+			m.up = string(body)
+		} else {
+			m.down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// Latest returns the version of the newest embedded migration.
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func ensureVersionTable(ctx context.Context, exec Execer) error {
+	_, err := exec.Exec(ctx,
+		`CREATE TABLE IF NOT EXISTS "schema_migrations" ("version" bigint NOT NULL PRIMARY KEY, "dirty" boolean NOT NULL)`,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+
+	return nil
+}
+
+func readVersion(ctx context.Context, exec Execer) (uint64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+
+	err := exec.QueryRow(ctx, `SELECT "version", "dirty" FROM "schema_migrations" LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.Is(err, pgx.ErrNoRows) || errors.As(err, &pgErr) && pgErr.Code == sqlStateUndefinedTable {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("error reading schema version: %w", err)
+	}
+
+	// golang-migrate keeps -1 when a migration failed on an empty schema.
+	if version < 0 {
+		return 0, dirty, nil
+	}
+
+	return uint64(version), dirty, nil
+}
+
+// Version returns the applied schema version and whether the last migration failed.
+// It only reads, so a database without schema_migrations is at version 0.
+func (m *Migrator) Version(ctx context.Context) (uint64, bool, error) {
+	return readVersion(ctx, m.pool)
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	version, _, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{
+			Migration: migration,
+			Applied:   migration.Version <= version,
+		}
+	}
+
+	return statuses, nil
+}
+
+// CheckVersion fails when the schema is behind the binary or dirty.
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	version, dirty, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("schema version %d: %w", version, ErrSchemaDirty)
+	}
+
+	if version < m.Latest() {
+		return fmt.Errorf(
+			"schema version %d is behind the binary version %d, run `app migrate up`",
+			version,
+			m.Latest(),
+		)
+	}
+
+	return nil
+}
+
+// Up applies all pending migrations and returns how many were applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if dirty {
+			return fmt.Errorf("schema version %d: %w", version, ErrSchemaDirty)
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+
+			err = applyMigration(ctx, conn, migration.up, int64(migration.Version))
+			if err != nil {
+				return fmt.Errorf("error applying migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			applied++
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back up to steps applied migrations and returns how many were rolled back.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	rolledBack := 0
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if dirty {
+			return fmt.Errorf("schema version %d: %w", version, ErrSchemaDirty)
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && rolledBack < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > version {
+				continue
+			}
+
+			previous := int64(-1)
+			if i > 0 {
+				previous = int64(m.migrations[i-1].Version)
+			}
+
+			err = applyMigration(ctx, conn, migration.down, previous)
+			if err != nil {
+				return fmt.Errorf("error rolling back migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			rolledBack++
+		}
+
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationsLockID); err != nil {
+		return fmt.Errorf("error taking migrations lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationsLockID)
+	}()
+
+	if err = ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// applyMigration runs the migration body and records the new version in one transaction.
+// A failed migration leaves the schema untouched, so the dirty flag is never set.
+func applyMigration(ctx context.Context, conn *pgxpool.Conn, body string, newVersion int64) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err = tx.Exec(ctx, body); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM "schema_migrations"`); err != nil {
+		return err
+	}
+
+	if newVersion >= 0 {
+		_, err = tx.Exec(ctx, `INSERT INTO "schema_migrations" ("version", "dirty") VALUES ($1, false)`, newVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CheckNotifyQueue fails when the NOTIFY queue shared by the event and cache
+// bridges is filled above maxUsage (0..1), that is when some listener falls
+// behind. A full queue makes pg_notify, and so every write, fail.
+func CheckNotifyQueue(ctx context.Context, pool *pgxpool.Pool, maxUsage float64) error {
+	var usage float64
+	if err := pool.QueryRow(ctx, "SELECT pg_notification_queue_usage()").Scan(&usage); err != nil {
+		return fmt.Errorf("error reading notification queue usage: %w", err)
+	}
+
+	if usage > maxUsage {
+		return fmt.Errorf("notification queue is %.0f%% full", usage*100)
+	}
+
+	return nil
+}
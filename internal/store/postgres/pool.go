@@ -6,10 +6,20 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
 )
 
 func NewPool(ctx context.Context, cfg *config.DBConfig) (*pgxpool.Pool, error) {
-	poolCfg, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+	return newPool(ctx, cfg.DatabaseURL, cfg)
+}
+
+// NewReadPool opens the pool of the read replica at cfg.ReadDatabaseURL.
+func NewReadPool(ctx context.Context, cfg *config.DBConfig) (*pgxpool.Pool, error) {
+	return newPool(ctx, cfg.ReadDatabaseURL, cfg)
+}
+
+func newPool(ctx context.Context, databaseURL string, cfg *config.DBConfig) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(databaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing cfg.DatabaseUrl: %w", err)
 	}
@@ -17,6 +27,7 @@ func NewPool(ctx context.Context, cfg *config.DBConfig) (*pgxpool.Pool, error) {
 	poolCfg.MaxConns = cfg.MaxConns
 	poolCfg.MinConns = cfg.MinConns
 	poolCfg.HealthCheckPeriod = cfg.HealthCheckInterval
+	poolCfg.ConnConfig.Tracer = queryTracer{}
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
@@ -30,3 +41,19 @@ func NewPool(ctx context.Context, cfg *config.DBConfig) (*pgxpool.Pool, error) {
 
 	return pool, nil
 }
+
+func poolStats(name string, pool *pgxpool.Pool) store.PoolStats {
+	stat := pool.Stat()
+
+	return store.PoolStats{
+		Name:              name,
+		MaxConns:          stat.MaxConns(),
+		TotalConns:        stat.TotalConns(),
+		IdleConns:         stat.IdleConns(),
+		AcquiredConns:     stat.AcquiredConns(),
+		AcquireCount:      stat.AcquireCount(),
+		EmptyAcquireCount: stat.EmptyAcquireCount(),
+		AcquireDuration:   stat.AcquireDuration(),
+		Healthy:           true,
+	}
+}
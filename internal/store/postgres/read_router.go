@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/logging"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+)
+
+// replicationLagQuery returns 0 on a primary and on a replica that has
+// replayed everything it received, so an idle cluster does not look lagging.
+const replicationLagQuery = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() THEN 0
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END::float8`
+
+// replicaCheckTimeout bounds one replica check, so a hung replica is noticed
+// long before the next check.
+const replicaCheckTimeout = 2 * time.Second
+
+// ReadRouter is an Execer for read-only queries. It sends them to the replica
+// while the replica answers and lags no more than maxLag, and to the primary otherwise.
+// A query that fails to reach the replica is retried on the primary, and the
+// replica is not used again until the next successful check.
+type ReadRouter struct {
+	primary *pgxpool.Pool
+	replica *pgxpool.Pool
+	maxLag  time.Duration
+
+	healthy atomic.Bool
+	lag     atomic.Int64
+}
+
+// NewReadRouter creates a router, replica may be nil.
+// The replica is not used until the first successful check in Run.
+func NewReadRouter(primary, replica *pgxpool.Pool, maxLag time.Duration) *ReadRouter {
+	return &ReadRouter{
+		primary: primary,
+		replica: replica,
+		maxLag:  maxLag,
+	}
+}
+
+func (r *ReadRouter) useReplica() bool {
+	return r.replica != nil && r.healthy.Load()
+}
+
+// isConnectionError reports whether err means the query never reached the
+// server, so it is safe to send it to another one.
+func isConnectionError(err error) bool {
+	var connectErr *pgconn.ConnectError
+	return errors.As(err, &connectErr) || pgconn.SafeToRetry(err)
+}
+
+// fallBack stops reading from the replica until the next successful check.
+func (r *ReadRouter) fallBack(ctx context.Context, err error) {
+	if r.healthy.Swap(false) {
+		logging.FromContext(ctx).Warn("read replica is unreachable, reading from primary", logging.Error(err))
+	}
+}
+
+func (r *ReadRouter) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	if r.useReplica() {
+		tag, err := r.replica.Exec(ctx, sql, arguments...)
+		if !isConnectionError(err) {
+			return tag, err
+		}
+
+		r.fallBack(ctx, err)
+	}
+
+	return r.primary.Exec(ctx, sql, arguments...)
+}
+
+func (r *ReadRouter) Query(ctx context.Context, sql string, arguments ...any) (pgx.Rows, error) {
+	if r.useReplica() {
+		rows, err := r.replica.Query(ctx, sql, arguments...)
+		if !isConnectionError(err) {
+			return rows, err
+		}
+
+		r.fallBack(ctx, err)
+	}
+
+	return r.primary.Query(ctx, sql, arguments...)
+}
+
+func (r *ReadRouter) QueryRow(ctx context.Context, sql string, arguments ...any) pgx.Row {
+	if !r.useReplica() {
+		return r.primary.QueryRow(ctx, sql, arguments...)
+	}
+
+	return fallbackRow{
+		ctx:    ctx,
+		router: r,
+		row:    r.replica.QueryRow(ctx, sql, arguments...),
+		primary: func() pgx.Row {
+			return r.primary.QueryRow(ctx, sql, arguments...)
+		},
+	}
+}
+
+// fallbackRow is a replica row that is read from the primary when the
+// replica cannot be reached. QueryRow reports errors only on Scan.
+type fallbackRow struct {
+	ctx     context.Context
+	router  *ReadRouter
+	row     pgx.Row
+	primary func() pgx.Row
+}
+
+func (f fallbackRow) Scan(dest ...any) error {
+	err := f.row.Scan(dest...)
+	if !isConnectionError(err) {
+		return err
+	}
+
+	f.router.fallBack(f.ctx, err)
+
+	return f.primary().Scan(dest...)
+}
+
+// Run checks the replica every interval until ctx is done.
+func (r *ReadRouter) Run(ctx context.Context, interval time.Duration) {
+	if r.replica == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *ReadRouter) check(ctx context.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+	defer cancel()
+
+	var lagInSeconds float64
+
+	err := r.replica.QueryRow(checkCtx, replicationLagQuery).Scan(&lagInSeconds)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if r.healthy.Swap(false) {
+			logging.FromContext(ctx).Warn("read replica is unavailable, reading from primary", logging.Error(err))
+		}
+
+		return
+	}
+
+	lag := time.Duration(lagInSeconds * float64(time.Second))
+	r.lag.Store(int64(lag))
+
+	healthy := lag <= r.maxLag
+	if r.healthy.Swap(healthy) != healthy {
+		if healthy {
+			logging.FromContext(ctx).Info("read replica is back", slog.Duration("lag", lag))
+		} else {
+			logging.FromContext(ctx).Warn("read replica lags, reading from primary", slog.Duration("lag", lag))
+		}
+	}
+}
+
+// PoolStats returns the stats of the primary pool and of the replica pool if any.
+func (r *ReadRouter) PoolStats() []store.PoolStats {
+	stats := []store.PoolStats{poolStats("primary", r.primary)}
+
+	if r.replica != nil {
+		replicaStats := poolStats("replica", r.replica)
+		replicaStats.Healthy = r.healthy.Load()
+		replicaStats.Lag = time.Duration(r.lag.Load())
+		stats = append(stats, replicaStats)
+	}
+
+	return stats
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// unreachablePool returns a pool that fails to connect: nothing listens on port 1.
+func unreachablePool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	pool, err := pgxpool.New(context.Background(), "postgres://app@127.0.0.1:1/app?connect_timeout=1")
+	if err != nil {
+		t.Fatalf("create pool: %v", err)
+	}
+
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func TestReadRouterFallsBackOnConnectionError(t *testing.T) {
+	tests := []struct {
+		name  string
+		query func(r *ReadRouter) error
+	}{
+		{
+			name: "query",
+			query: func(r *ReadRouter) error {
+				_, err := r.Query(context.Background(), "SELECT 1")
+				return err
+			},
+		},
+		{
+			name: "query row",
+			query: func(r *ReadRouter) error {
+				var n int
+				return r.QueryRow(context.Background(), "SELECT 1").Scan(&n)
+			},
+		},
+		{
+			name: "exec",
+			query: func(r *ReadRouter) error {
+				_, err := r.Exec(context.Background(), "SELECT 1")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReadRouter(unreachablePool(t), unreachablePool(t), time.Second)
+			r.healthy.Store(true)
+
+			// The primary is unreachable too, so the error is the one of the retry.
+			if err := tt.query(r); err == nil {
+				t.Fatalf("query succeeded without a server")
+			}
+
+			if r.healthy.Load() {
+				t.Fatalf("replica is still used after a connection error")
+			}
+		})
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "no error", err: nil, want: false},
+		{name: "server error", err: &pgconn.PgError{Code: sqlStateUndefinedTable}, want: false},
+		{name: "canceled", err: context.Canceled, want: false},
+		{name: "other", err: errors.New("scan failed"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isConnectionError(tt.err); got != tt.want {
+				t.Fatalf("isConnectionError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package postgresrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	pg "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+)
+
+type ArchiveRepo struct {
+	exec    pg.Execer
+	builder squirrel.StatementBuilderType
+}
+
+func NewArchiveRepo(exec pg.Execer, builder squirrel.StatementBuilderType) *ArchiveRepo {
+	return &ArchiveRepo{exec: exec, builder: builder}
+}
+
+// ListMergedBefore returns up to limit pull requests merged before the given time,
+// oldest first, including deleted ones. The rows stay locked until the end of
+// the transaction.
+func (r *ArchiveRepo) ListMergedBefore(
+	ctx context.Context,
+	before time.Time,
+	limit int,
+) ([]domain.PullRequest, error) {
+	query := selectPullRequestsFrom(r.builder, activeTables).
+		Where("pr.status = ?", domain.PRStatusMerged).
+		Where("pr.merged_at < ?", before).
+		OrderBy("pr.merged_at").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE OF pr SKIP LOCKED")
+
+	return loadPullRequests(ctx, r.exec, query)
+}
+
+// ArchivePullRequests moves pull requests with their reviewers to the archive tables.
+func (r *ArchiveRepo) ArchivePullRequests(ctx context.Context, pullRequestIDs []string) error {
+	return r.archive(ctx, pullRequestIDs, true)
+}
+
+func (r *ArchiveRepo) TombstonePullRequests(ctx context.Context, pullRequestIDs []string) error {
+	return r.archive(ctx, pullRequestIDs, false)
+}
+
+func (r *ArchiveRepo) archive(ctx context.Context, pullRequestIDs []string, withReviewers bool) error {
+	queries := []squirrel.Sqlizer{
+		r.builder.
+			Insert("pull_requests_archive").
+			Columns(
+				"pull_request_id",
+				"pull_request_name",
+				"author_id",
+				"status",
+				"created_at",
+				"merged_at",
+				"version",
+			).
+			Select(
+				r.builder.
+					Select(
+						"pull_request_id",
+						"pull_request_name",
+						"author_id",
+						"status",
+						"created_at",
+						"merged_at",
+						"version",
+					).
+					From("pull_requests").
+					Where("pull_request_id = ANY(?)", pullRequestIDs),
+			),
+	}
+
+	if withReviewers {
+		queries = append(queries, r.builder.
+			Insert("assigned_reviewers_archive").
+			Columns("pull_request_id", "user_id").
+			Select(
+				r.builder.
+					Select("pull_request_id", "user_id").
+					From("assigned_reviewers").
+					Where("pull_request_id = ANY(?)", pullRequestIDs),
+			))
+	}
+
+	for _, query := range queries {
+		if err := r.execQuery(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	return r.deletePullRequests(ctx, pullRequestIDs)
+}
+
+// deletePullRequests removes pull requests with their reviewers.
+func (r *ArchiveRepo) deletePullRequests(ctx context.Context, pullRequestIDs []string) error {
+	queries := []squirrel.Sqlizer{
+		r.builder.
+			Delete("assigned_reviewers").
+			Where("pull_request_id = ANY(?)", pullRequestIDs),
+		r.builder.
+			Delete("pull_requests").
+			Where("pull_request_id = ANY(?)", pullRequestIDs),
+	}
+
+	for _, query := range queries {
+		if err := r.execQuery(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ArchiveRepo) execQuery(ctx context.Context, query squirrel.Sqlizer) error {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	if _, err = r.exec.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ArchiveRepo) GetByID(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
+	query := selectPullRequestsFrom(r.builder, archiveTables).
+		Where("pr.pull_request_id = ?", pullRequestID)
+
+	pullRequests, err := loadPullRequests(ctx, r.exec, query)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if len(pullRequests) == 0 {
+		return domain.PullRequest{},
+			domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("archived pull request %s not found", pullRequestID))
+	}
+
+	return pullRequests[0], nil
+}
+
+func (r *ArchiveRepo) ListReviewPRs(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+	query := selectPullRequestsFrom(r.builder, archiveTables).
+		Join("assigned_reviewers_archive rev ON rev.pull_request_id = pr.pull_request_id").
+		Where("rev.user_id = ?", userID).
+		OrderBy("pr.created_at DESC")
+
+	return loadPullRequests(ctx, r.exec, query)
+}
+
+// Stats counts pull requests and review assignments over the hot and archive tables,
+// skipping deleted pull requests that are not archived yet.
+func (r *ArchiveRepo) Stats(ctx context.Context) (domain.PullRequestStats, error) {
+	var stats domain.PullRequestStats
+
+	sql, args, err := r.builder.Select("count(*)").From("pull_requests_archive").ToSql()
+	if err != nil {
+		return stats, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	if err = r.exec.QueryRow(ctx, sql, args...).Scan(&stats.Archived); err != nil {
+		return stats, fmt.Errorf("error counting archived pull requests: %w", err)
+	}
+
+	stats.Merged = stats.Archived
+
+	err = r.scanCounts(ctx, r.builder.
+		Select("status", "count(*)").
+		From("pull_requests").
+		Where("deleted_at IS NULL").
+		GroupBy("status"),
+		func(status string, n int) {
+			if domain.PullRequestStatus(status) == domain.PRStatusMerged {
+				stats.Merged += n
+			} else {
+				stats.Open += n
+			}
+		},
+	)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.Reviewers = []domain.ReviewerStats{}
+
+	err = r.scanCounts(ctx, r.builder.
+		Select("rev.user_id", "count(*) AS assignments").
+		From("(SELECT ar.user_id FROM assigned_reviewers ar "+
+			"JOIN pull_requests p ON p.pull_request_id = ar.pull_request_id WHERE p.deleted_at IS NULL "+
+			"UNION ALL SELECT user_id FROM assigned_reviewers_archive) rev").
+		GroupBy("rev.user_id").
+		OrderBy("assignments DESC", "rev.user_id"),
+		func(userID string, n int) {
+			stats.Reviewers = append(stats.Reviewers, domain.ReviewerStats{UserID: userID, Assignments: n})
+		},
+	)
+
+	return stats, err
+}
+
+// scanCounts calls fn for every (key, count) row of query.
+func (r *ArchiveRepo) scanCounts(ctx context.Context, query squirrel.SelectBuilder, fn func(key string, n int)) error {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	rows, err := r.exec.Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			key string
+			n   int
+		)
+
+		if err = rows.Scan(&key, &n); err != nil {
+			return fmt.Errorf("error scanning count: %w", err)
+		}
+
+		fn(key, n)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error scanning counts: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package postgresrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	pg "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+)
+
+// execAffected runs a statement and returns the number of affected rows.
+func execAffected(ctx context.Context, exec pg.Execer, query squirrel.Sqlizer) (int64, error) {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	tag, err := exec.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error executing query: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
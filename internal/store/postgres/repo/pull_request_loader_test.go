@@ -0,0 +1,266 @@
+package postgresrepo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	pg "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/migrations"
+)
+
+const (
+	benchUsers        = 50
+	benchPullRequests = 2000
+	// benchReviewer reviews 80 of the pull requests.
+	benchReviewer = "user-1"
+)
+
+// openBenchDB seeds the database at TEST_DATABASE_URL with a team of
+// benchUsers users and benchPullRequests pull requests, two reviewers each.
+func openBenchDB(b *testing.B) *pgxpool.Pool {
+	b.Helper()
+
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		b.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+
+	pool, err := pg.NewPool(ctx, &config.DBConfig{DatabaseURL: databaseURL, MaxConns: 4})
+	if err != nil {
+		b.Fatalf("open postgres: %v", err)
+	}
+
+	b.Cleanup(pool.Close)
+
+	migrator, err := pg.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		b.Fatalf("migrator: %v", err)
+	}
+
+	if _, err = migrator.Up(ctx); err != nil {
+		b.Fatalf("migrate: %v", err)
+	}
+
+	_, err = pool.Exec(ctx, `TRUNCATE assigned_reviewers_archive, pull_requests_archive, assigned_reviewers,
+		pull_requests, users, teams, idempotency_keys`)
+	if err != nil {
+		b.Fatalf("truncate: %v", err)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		b.Fatalf("begin: %v", err)
+	}
+
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	builder := pg.NewStatementBuilder()
+
+	if err = NewTeamRepo(tx, builder).InsertTeam(ctx, "backend"); err != nil {
+		b.Fatalf("insert team: %v", err)
+	}
+
+	for i := range benchUsers {
+		user := domain.User{ID: fmt.Sprintf("user-%d", i), Username: "user", TeamName: "backend", IsActive: true}
+		if err = NewUserRepo(tx, builder).UpsertUser(ctx, user); err != nil {
+			b.Fatalf("insert user: %v", err)
+		}
+	}
+
+	prRepo := NewPullRequestRepo(tx, builder)
+
+	for i := range benchPullRequests {
+		prID := fmt.Sprintf("pr-%d", i)
+
+		pr := domain.PullRequest{ID: prID, Name: prID, AuthorID: fmt.Sprintf("user-%d", i%benchUsers)}
+		if err = prRepo.InsertPullRequest(ctx, pr); err != nil {
+			b.Fatalf("insert pull request: %v", err)
+		}
+
+		for _, offset := range []int{1, 2} {
+			if err = prRepo.AddReviewer(ctx, prID, fmt.Sprintf("user-%d", (i+offset)%benchUsers)); err != nil {
+				b.Fatalf("add reviewer: %v", err)
+			}
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		b.Fatalf("commit: %v", err)
+	}
+
+	return pool
+}
+
+// getByIDPerPR is GetByID as it was before the single-query loader: the
+// pull request first, then its reviewers.
+func getByIDPerPR(
+	ctx context.Context,
+	exec pg.Execer,
+	builder squirrel.StatementBuilderType,
+	pullRequestID string,
+) (domain.PullRequest, error) {
+	var pr domain.PullRequest
+
+	sql, args, err := builder.
+		Select("pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at", "version").
+		From("pull_requests").
+		Where("pull_request_id = ?", pullRequestID).
+		ToSql()
+	if err != nil {
+		return pr, err
+	}
+
+	err = exec.QueryRow(ctx, sql, args...).
+		Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.Version)
+	if err != nil {
+		return pr, err
+	}
+
+	pr.AssignedReviewers, err = reviewersPerPR(ctx, exec, builder, pullRequestID)
+
+	return pr, err
+}
+
+// listReviewPRsPerPR lists the pull requests of a reviewer and then queries
+// the reviewers of each, the way a list endpoint would without the loader.
+func listReviewPRsPerPR(
+	ctx context.Context,
+	exec pg.Execer,
+	builder squirrel.StatementBuilderType,
+	userID string,
+) ([]domain.PullRequest, error) {
+	sql, args, err := builder.
+		Select("pr.pull_request_id", "pr.pull_request_name", "pr.author_id", "pr.status", "pr.created_at",
+			"pr.merged_at", "pr.version").
+		From("assigned_reviewers ar").
+		Join("pull_requests pr ON pr.pull_request_id = ar.pull_request_id").
+		Where("ar.user_id = ?", userID).
+		OrderBy("pr.created_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := exec.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var pullRequests []domain.PullRequest
+
+	for rows.Next() {
+		var pr domain.PullRequest
+		if err = rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt,
+			&pr.Version); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		pullRequests = append(pullRequests, pr)
+	}
+
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range pullRequests {
+		pullRequests[i].AssignedReviewers, err = reviewersPerPR(ctx, exec, builder, pullRequests[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pullRequests, nil
+}
+
+func reviewersPerPR(
+	ctx context.Context,
+	exec pg.Execer,
+	builder squirrel.StatementBuilderType,
+	pullRequestID string,
+) ([]string, error) {
+	sql, args, err := builder.
+		Select("user_id").
+		From("assigned_reviewers").
+		Where("pull_request_id = ?", pullRequestID).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := exec.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var reviewers []string
+
+	for rows.Next() {
+		var reviewerID string
+		if err = rows.Scan(&reviewerID); err != nil {
+			return nil, err
+		}
+
+		reviewers = append(reviewers, reviewerID)
+	}
+
+	return reviewers, rows.Err()
+}
+
+func BenchmarkGetByID(b *testing.B) {
+	ctx := context.Background()
+	pool := openBenchDB(b)
+	builder := pg.NewStatementBuilder()
+	repo := NewPullRequestRepo(pool, builder)
+
+	b.Run("single query", func(b *testing.B) {
+		for i := 0; b.Loop(); i++ {
+			if _, err := repo.GetByID(ctx, fmt.Sprintf("pr-%d", i%benchPullRequests)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("per-PR reviewers", func(b *testing.B) {
+		for i := 0; b.Loop(); i++ {
+			if _, err := getByIDPerPR(ctx, pool, builder, fmt.Sprintf("pr-%d", i%benchPullRequests)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkListReviewPRs(b *testing.B) {
+	ctx := context.Background()
+	pool := openBenchDB(b)
+	builder := pg.NewStatementBuilder()
+	repo := NewUserRepo(pool, builder)
+
+	b.Run("single query", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := repo.ListReviewPRs(ctx, benchReviewer); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("per-PR reviewers", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := listReviewPRsPerPR(ctx, pool, builder, benchReviewer); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
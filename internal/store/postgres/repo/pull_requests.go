@@ -4,9 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Masterminds/squirrel"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
 	pg "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/postgres"
@@ -51,91 +51,71 @@ func (r *PullRequestRepo) InsertPullRequest(ctx context.Context, pullRequest dom
 	return nil
 }
 
-func (r *PullRequestRepo) getPRBodyData(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
-	query := r.builder.
-		Select(
-			"pull_request_id",
-			"pull_request_name",
-			"author_id",
-			"status",
-			"created_at",
-			"merged_at",
-		).
-		From("pull_requests").
-		Where("pull_request_id = ?", pullRequestID)
+func (r *PullRequestRepo) getByID(
+	ctx context.Context,
+	pullRequestID string,
+	forUpdate bool,
+) (domain.PullRequest, error) {
+	query := selectPullRequests(r.builder).
+		Where("pr.pull_request_id = ?", pullRequestID)
 
-	sql, args, err := query.ToSql()
-	if err != nil {
-		return domain.PullRequest{}, fmt.Errorf("error generating sql query: %w", err)
+	if forUpdate {
+		query = query.Suffix("FOR UPDATE OF pr")
 	}
 
-	var pr domain.PullRequest
-
-	err = r.exec.QueryRow(ctx, sql, args...).Scan(
-		&pr.ID,
-		&pr.Name,
-		&pr.AuthorID,
-		&pr.Status,
-		&pr.CreatedAt,
-		&pr.MergedAt,
-	)
+	pullRequests, err := loadPullRequests(ctx, r.exec, query)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.PullRequest{},
-				domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("pull request %s not found", pullRequestID))
-		}
+		return domain.PullRequest{}, err
+	}
 
-		return domain.PullRequest{}, fmt.Errorf("error executing query: %w", err)
+	if len(pullRequests) == 0 {
+		return domain.PullRequest{},
+			domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("pull request %s not found", pullRequestID))
 	}
 
-	return pr, nil
+	return pullRequests[0], nil
 }
 
-func (r *PullRequestRepo) addReviewersIDs(
-	ctx context.Context,
-	pullRequest domain.PullRequest,
-) (domain.PullRequest, error) {
-	query := r.builder.
-		Select("user_id").
-		From("assigned_reviewers").
-		Where("pull_request_id = ?", pullRequest.ID)
-
-	sql, args, err := query.ToSql()
-	if err != nil {
-		return domain.PullRequest{}, fmt.Errorf("error generating sql query: %w", err)
-	}
-
-	rows, err := r.exec.Query(ctx, sql, args...)
-	if err != nil {
-		return domain.PullRequest{}, fmt.Errorf("error executing query: %w", err)
-	}
+func (r *PullRequestRepo) GetByID(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
+	return r.getByID(ctx, pullRequestID, false)
+}
 
-	defer rows.Close()
+// GetByIDForUpdate locks the pull request row until the end of the transaction.
+func (r *PullRequestRepo) GetByIDForUpdate(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
+	return r.getByID(ctx, pullRequestID, true)
+}
 
-	for rows.Next() {
-		var reviewerID string
-		err = rows.Scan(&reviewerID)
-		if err != nil {
-			return domain.PullRequest{}, fmt.Errorf("error scanning row: %w", err)
-		}
-		pullRequest.AssignedReviewers = append(pullRequest.AssignedReviewers, reviewerID)
-	}
+// GetByIDs loads pull requests with their reviewers in one query, newest first.
+// Unknown IDs are skipped.
+func (r *PullRequestRepo) GetByIDs(ctx context.Context, pullRequestIDs []string) ([]domain.PullRequest, error) {
+	query := selectPullRequests(r.builder).
+		Where("pr.pull_request_id = ANY(?)", pullRequestIDs).
+		OrderBy("pr.created_at DESC")
 
-	return pullRequest, nil
+	return loadPullRequests(ctx, r.exec, query)
 }
 
-func (r *PullRequestRepo) GetByID(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
-	pullRequestBase, err := r.getPRBodyData(ctx, pullRequestID)
+func (r *PullRequestRepo) IncrementVersion(ctx context.Context, pullRequestID string) error {
+	query := r.builder.
+		Update("pull_requests").
+		Set("version", squirrel.Expr("version + 1")).
+		Where("pull_request_id = ?", pullRequestID)
+
+	sql, args, err := query.ToSql()
 	if err != nil {
-		return domain.PullRequest{}, err
+		return fmt.Errorf("error generating sql query: %w", err)
 	}
 
-	pullREquest, err := r.addReviewersIDs(ctx, pullRequestBase)
+	tag, err := r.exec.Exec(ctx, sql, args...)
 	if err != nil {
-		return domain.PullRequest{}, err
+		return fmt.Errorf("error executing query: %w", err)
 	}
 
-	return pullREquest, nil
+	if tag.RowsAffected() == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("pull request %s not found", pullRequestID))
+	}
+
+	return nil
 }
 
 func (r *PullRequestRepo) AddReviewer(ctx context.Context, pullRequestID string, reviewerID string) error {
@@ -189,6 +169,7 @@ func (r *PullRequestRepo) MergePullRequest(ctx context.Context, pullRequest doma
 		Update("pull_requests").
 		Set("status", pullRequest.Status).
 		Set("merged_at", pullRequest.MergedAt).
+		Set("version", squirrel.Expr("version + 1")).
 		Where("pull_request_id = ?", pullRequest.ID)
 
 	sql, args, err := query.ToSql()
@@ -207,3 +188,138 @@ func (r *PullRequestRepo) MergePullRequest(ctx context.Context, pullRequest doma
 
 	return nil
 }
+
+// SoftDeletePullRequest hides the pull request from reads. The version is
+// bumped so that cached copies are no longer valid.
+func (r *PullRequestRepo) SoftDeletePullRequest(ctx context.Context, pullRequestID string, deletedAt time.Time) error {
+	query := r.builder.
+		Update("pull_requests").
+		Set("deleted_at", deletedAt).
+		Set("version", squirrel.Expr("version + 1")).
+		Where("pull_request_id = ?", pullRequestID).
+		Where("deleted_at IS NULL")
+
+	affected, err := execAffected(ctx, r.exec, query)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("pull request %s not found", pullRequestID))
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepo) RestorePullRequest(ctx context.Context, pullRequestID string) error {
+	query := r.builder.
+		Update("pull_requests").
+		Set("deleted_at", nil).
+		Set("version", squirrel.Expr("version + 1")).
+		Where("pull_request_id = ?", pullRequestID).
+		Where("deleted_at IS NOT NULL")
+
+	affected, err := execAffected(ctx, r.exec, query)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.NewError(
+			domain.ErrCodeNotFound,
+			fmt.Sprintf("deleted pull request %s not found", pullRequestID),
+		)
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepo) CountOpenByTeam(ctx context.Context) (map[string]int, error) {
+	query := r.builder.
+		Select("u.team_name", "COUNT(*)").
+		From("pull_requests pr").
+		Join("users u ON u.user_id = pr.author_id").
+		Where(squirrel.Eq{"pr.status": domain.PRStatusOpen}).
+		Where("pr.deleted_at IS NULL").
+		GroupBy("u.team_name")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	rows, err := r.exec.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", err)
+	}
+
+	defer rows.Close()
+
+	counts := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			teamName string
+			count    int
+		)
+
+		if err = rows.Scan(&teamName, &count); err != nil {
+			return nil, fmt.Errorf("error scanning open pull requests: %w", err)
+		}
+
+		counts[teamName] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning open pull requests: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *PullRequestRepo) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	query := r.builder.
+		Select("ar.user_id", "COUNT(*)").
+		From("assigned_reviewers ar").
+		Join("pull_requests pr ON pr.pull_request_id = ar.pull_request_id").
+		Where("ar.user_id = ANY(?)", userIDs).
+		Where(squirrel.Eq{"pr.status": domain.PRStatusOpen}).
+		Where("pr.deleted_at IS NULL").
+		GroupBy("ar.user_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	rows, err := r.exec.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			userID string
+			count  int
+		)
+
+		if err = rows.Scan(&userID, &count); err != nil {
+			return nil, fmt.Errorf("error scanning open reviews: %w", err)
+		}
+
+		counts[userID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning open reviews: %w", err)
+	}
+
+	return counts, nil
+}
@@ -25,3 +25,15 @@ func (r *PostgreRepoFactory) UserRepository(exec pg.Execer) repository.UserRepos
 func (r *PostgreRepoFactory) PullRequestRepository(exec pg.Execer) repository.PullRequestRepository {
 	return NewPullRequestRepo(exec, r.builder)
 }
+
+func (r *PostgreRepoFactory) IdempotencyRepository(exec pg.Execer) repository.IdempotencyRepository {
+	return NewIdempotencyRepo(exec, r.builder)
+}
+
+func (r *PostgreRepoFactory) ArchiveRepository(exec pg.Execer) repository.ArchiveRepository {
+	return NewArchiveRepo(exec, r.builder)
+}
+
+func (r *PostgreRepoFactory) SnapshotRepository(exec pg.Execer) repository.SnapshotRepository {
+	return NewSnapshotRepo(exec, r.builder)
+}
@@ -5,6 +5,7 @@ import (
 	databasesql "database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -48,8 +49,9 @@ func (r *TeamRepo) GetTeamWithMembers(ctx context.Context, teamName string) (dom
 	query := r.builder.
 		Select("u.user_id", "u.username", "u.is_active").
 		From("teams t").
-		LeftJoin("users u ON u.team_name = t.team_name").
-		Where("t.team_name = ?", teamName)
+		LeftJoin("users u ON u.team_name = t.team_name AND u.deleted_at IS NULL").
+		Where("t.team_name = ?", teamName).
+		Where("t.deleted_at IS NULL")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -104,3 +106,57 @@ func (r *TeamRepo) GetTeamWithMembers(ctx context.Context, teamName string) (dom
 		Members: members,
 	}, nil
 }
+
+// SoftDeleteTeam marks the team and its members as deleted at the same time,
+// which lets RestoreTeam tell them apart from members deleted earlier.
+func (r *TeamRepo) SoftDeleteTeam(ctx context.Context, teamName string, deletedAt time.Time) error {
+	affected, err := execAffected(ctx, r.exec, r.builder.
+		Update("teams").
+		Set("deleted_at", deletedAt).
+		Where("team_name = ?", teamName).
+		Where("deleted_at IS NULL"))
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("team %s not found", teamName))
+	}
+
+	_, err = execAffected(ctx, r.exec, r.builder.
+		Update("users").
+		Set("deleted_at", deletedAt).
+		Where("team_name = ?", teamName).
+		Where("deleted_at IS NULL"))
+
+	return err
+}
+
+// RestoreTeam restores the team and the members deleted together with it.
+func (r *TeamRepo) RestoreTeam(ctx context.Context, teamName string) error {
+	_, err := execAffected(ctx, r.exec, r.builder.
+		Update("users u").
+		Set("deleted_at", nil).
+		From("teams t").
+		Where("t.team_name = u.team_name").
+		Where("t.team_name = ?", teamName).
+		Where("u.deleted_at = t.deleted_at"))
+	if err != nil {
+		return err
+	}
+
+	affected, err := execAffected(ctx, r.exec, r.builder.
+		Update("teams").
+		Set("deleted_at", nil).
+		Where("team_name = ?", teamName).
+		Where("deleted_at IS NOT NULL"))
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("deleted team %s not found", teamName))
+	}
+
+	return nil
+}
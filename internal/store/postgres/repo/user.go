@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -20,6 +21,7 @@ func NewUserRepo(exec pg.Execer, builder squirrel.StatementBuilderType) *UserRep
 	return &UserRepo{exec: exec, builder: builder}
 }
 
+// UpsertUser also restores the user if it was deleted.
 func (r *UserRepo) UpsertUser(ctx context.Context, user domain.User) error {
 	query := r.builder.
 		Insert("users").
@@ -30,7 +32,8 @@ func (r *UserRepo) UpsertUser(ctx context.Context, user domain.User) error {
 		Suffix(
 			"ON CONFLICT (user_id) " +
 				"DO UPDATE SET " +
-				"username = EXCLUDED.username, team_name = EXCLUDED.team_name, is_active = EXCLUDED.is_active",
+				"username = EXCLUDED.username, team_name = EXCLUDED.team_name, is_active = EXCLUDED.is_active, " +
+				"deleted_at = NULL",
 		)
 
 	sql, args, err := withUpdate.ToSql()
@@ -51,11 +54,24 @@ func (r *UserRepo) UpsertUser(ctx context.Context, user domain.User) error {
 }
 
 func (r *UserRepo) GetByID(ctx context.Context, userID string) (domain.User, error) {
+	return r.getByID(ctx, userID, false)
+}
+
+// GetByIDWithDeleted is the same as GetByID but also returns deleted users.
+func (r *UserRepo) GetByIDWithDeleted(ctx context.Context, userID string) (domain.User, error) {
+	return r.getByID(ctx, userID, true)
+}
+
+func (r *UserRepo) getByID(ctx context.Context, userID string, withDeleted bool) (domain.User, error) {
 	query := r.builder.
-		Select("user_id", "username", "team_name", "is_active").
+		Select("user_id", "username", "team_name", "is_active", "deleted_at").
 		From("users").
 		Where("user_id = ?", userID)
 
+	if !withDeleted {
+		query = query.Where("deleted_at IS NULL")
+	}
+
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return domain.User{}, fmt.Errorf("error generating sql query: %w", err)
@@ -63,7 +79,13 @@ func (r *UserRepo) GetByID(ctx context.Context, userID string) (domain.User, err
 
 	var user domain.User
 
-	err = r.exec.QueryRow(ctx, sql, args...).Scan(&user.ID, &user.Username, &user.TeamName, &user.IsActive)
+	err = r.exec.QueryRow(ctx, sql, args...).Scan(
+		&user.ID,
+		&user.Username,
+		&user.TeamName,
+		&user.IsActive,
+		&user.DeletedAt,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.User{}, domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("user %s not found", userID))
@@ -79,7 +101,8 @@ func (r *UserRepo) SetIsActive(ctx context.Context, userID string, isActive bool
 	query := r.builder.
 		Update("users").
 		Set("is_active", isActive).
-		Where("user_id = ?", userID)
+		Where("user_id = ?", userID).
+		Where("deleted_at IS NULL")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -98,48 +121,49 @@ func (r *UserRepo) SetIsActive(ctx context.Context, userID string, isActive bool
 	return nil
 }
 
-func (r *UserRepo) ListReviewPRs(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+func (r *UserRepo) SoftDeleteUser(ctx context.Context, userID string, deletedAt time.Time) error {
 	query := r.builder.
-		Select(
-			"pr.pull_request_id",
-			"pr.pull_request_name",
-			"pr.author_id",
-			"pr.status",
-			"pr.created_at",
-			"pr.merged_at",
-		).
-		From("assigned_reviewers ar").
-		Where("ar.user_id = ?", userID).
-		Join("pull_requests pr ON pr.pull_request_id = ar.pull_request_id").
-		OrderBy("pr.created_at DESC")
+		Update("users").
+		Set("deleted_at", deletedAt).
+		Where("user_id = ?", userID).
+		Where("deleted_at IS NULL")
 
-	sql, args, err := query.ToSql()
+	affected, err := execAffected(ctx, r.exec, query)
 	if err != nil {
-		return nil, fmt.Errorf("error generating sql query: %w", err)
+		return err
 	}
 
-	rows, err := r.exec.Query(ctx, sql, args...)
-	if err != nil {
-		return nil, fmt.Errorf("error executing query: %w", err)
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("user %s not found", userID))
 	}
 
-	defer rows.Close()
-
-	var pullRequests []domain.PullRequest
+	return nil
+}
 
-	for rows.Next() {
-		var pr domain.PullRequest
-		err = rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
-		if err != nil {
-			return nil, fmt.Errorf("error scanning pull request: %w", err)
-		}
+func (r *UserRepo) RestoreUser(ctx context.Context, userID string) error {
+	query := r.builder.
+		Update("users").
+		Set("deleted_at", nil).
+		Where("user_id = ?", userID).
+		Where("deleted_at IS NOT NULL")
 
-		pullRequests = append(pullRequests, pr)
+	affected, err := execAffected(ctx, r.exec, query)
+	if err != nil {
+		return err
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error scanning pull requests: %w", err)
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("deleted user %s not found", userID))
 	}
 
-	return pullRequests, nil
+	return nil
+}
+
+func (r *UserRepo) ListReviewPRs(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+	query := selectPullRequests(r.builder).
+		Join("assigned_reviewers rev ON rev.pull_request_id = pr.pull_request_id").
+		Where("rev.user_id = ?", userID).
+		OrderBy("pr.created_at DESC")
+
+	return loadPullRequests(ctx, r.exec, query)
 }
@@ -2,30 +2,181 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+	sqlStateUndefinedTable       = "42P01"
 )
 
 type TxManager struct {
 	pool *pgxpool.Pool
+
+	maxRetries     int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+
+	transactions          atomic.Uint64
+	commits               atomic.Uint64
+	retries               atomic.Uint64
+	retriesExhausted      atomic.Uint64
+	serializationFailures atomic.Uint64
+	deadlocks             atomic.Uint64
+}
+
+func NewTxManager(pool *pgxpool.Pool, cfg *config.TxConfig) *TxManager {
+	return &TxManager{
+		pool:           pool,
+		maxRetries:     cfg.MaxRetries,
+		retryBaseDelay: cfg.RetryBaseDelay,
+		retryMaxDelay:  cfg.RetryMaxDelay,
+	}
+}
+
+func (m *TxManager) TxWrapper(ctx context.Context, fn func(ctx context.Context, tx Execer) error) error {
+	return m.TxWrapperWithOptions(ctx, store.TxOptions{}, fn)
+}
+
+// TxWrapperWithOptions runs fn in a transaction with the given options.
+// Serialization failures and deadlocks restart the whole transaction,
+// so fn must not have side effects outside of tx; those go to
+// store.AfterCommit.
+func (m *TxManager) TxWrapperWithOptions(
+	ctx context.Context,
+	opts store.TxOptions,
+	fn func(ctx context.Context, tx Execer) error,
+) (err error) {
+	ctx, span := store.StartTxSpan(ctx, opts)
+
+	attempts := 0
+	defer func() {
+		span.SetAttributes(attribute.Int("db.transaction.attempts", attempts))
+		tracing.End(span, err)
+	}()
+
+	return m.withRetries(ctx, func() error {
+		attempts++
+
+		return m.runTx(ctx, opts, fn)
+	})
 }
 
-func NewTxManager(pool *pgxpool.Pool) *TxManager {
-	return &TxManager{pool: pool}
+// withRetries runs the attempts of one transaction.
+func (m *TxManager) withRetries(ctx context.Context, attemptTx func() error) error {
+	m.transactions.Add(1)
+
+	for attempt := 0; ; attempt++ {
+		err := attemptTx()
+		if err == nil {
+			m.commits.Add(1)
+			return nil
+		}
+
+		if !m.isRetryable(err) {
+			return err
+		}
+
+		if attempt >= m.maxRetries {
+			m.retriesExhausted.Add(1)
+			return fmt.Errorf("transaction failed after %d retries: %w", attempt, err)
+		}
+
+		m.retries.Add(1)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.backoff(attempt)):
+		}
+	}
 }
 
-func (m *TxManager) TxWrapper(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
-	tx, err := m.pool.BeginTx(ctx, pgx.TxOptions{})
+func (m *TxManager) runTx(
+	ctx context.Context,
+	opts store.TxOptions,
+	fn func(ctx context.Context, tx Execer) error,
+) error {
+	accessMode := pgx.ReadWrite
+	if opts.ReadOnly {
+		accessMode = pgx.ReadOnly
+	}
+
+	tx, err := m.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.TxIsoLevel(opts.IsoLevel),
+		AccessMode: accessMode,
+	})
 	if err != nil {
 		return err
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
-	err = fn(ctx, tx)
+	txCtx, runCommitHooks := store.WithCommitHooks(ctx)
+
+	err = fn(txCtx, tx)
 	if err != nil {
 		return err
 	}
 
-	return tx.Commit(ctx)
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	runCommitHooks()
+
+	return nil
+}
+
+func (m *TxManager) isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	switch pgErr.Code {
+	case sqlStateSerializationFailure:
+		m.serializationFailures.Add(1)
+		return true
+	case sqlStateDeadlockDetected:
+		m.deadlocks.Add(1)
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns an exponential delay with jitter in [delay/2, delay].
+func (m *TxManager) backoff(attempt int) time.Duration {
+	delay := m.retryBaseDelay << attempt
+	if delay <= 0 || delay > m.retryMaxDelay {
+		delay = m.retryMaxDelay
+	}
+
+	half := delay / 2
+
+	return half + rand.N(half+1)
+}
+
+func (m *TxManager) Stats() store.TxStats {
+	return store.TxStats{
+		Transactions:          m.transactions.Load(),
+		Commits:               m.commits.Load(),
+		Retries:               m.retries.Load(),
+		RetriesExhausted:      m.retriesExhausted.Load(),
+		SerializationFailures: m.serializationFailures.Load(),
+		Deadlocks:             m.deadlocks.Load(),
+	}
 }
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+)
+
+func newTestTxManager(maxRetries int) *TxManager {
+	return NewTxManager(nil, &config.TxConfig{
+		MaxRetries:     maxRetries,
+		RetryBaseDelay: time.Microsecond,
+		RetryMaxDelay:  time.Millisecond,
+	})
+}
+
+// failing returns an attempt that fails with the errors in order, then succeeds.
+func failing(errs ...error) (func() error, *int) {
+	attempts := 0
+
+	return func() error {
+		attempts++
+		if attempts <= len(errs) {
+			return errs[attempts-1]
+		}
+
+		return nil
+	}, &attempts
+}
+
+func TestWithRetries(t *testing.T) {
+	serialization := &pgconn.PgError{Code: sqlStateSerializationFailure}
+	deadlock := &pgconn.PgError{Code: sqlStateDeadlockDetected}
+	uniqueViolation := &pgconn.PgError{Code: "23505"}
+
+	tests := []struct {
+		name         string
+		errs         []error
+		wantErr      error
+		wantAttempts int
+		wantStats    store.TxStats
+	}{
+		{
+			name:         "no failure",
+			wantAttempts: 1,
+			wantStats:    store.TxStats{Transactions: 1, Commits: 1},
+		},
+		{
+			name:         "serialization failure then success",
+			errs:         []error{serialization, serialization},
+			wantAttempts: 3,
+			wantStats:    store.TxStats{Transactions: 1, Commits: 1, Retries: 2, SerializationFailures: 2},
+		},
+		{
+			name:         "deadlock then success",
+			errs:         []error{deadlock},
+			wantAttempts: 2,
+			wantStats:    store.TxStats{Transactions: 1, Commits: 1, Retries: 1, Deadlocks: 1},
+		},
+		{
+			name:         "retries exhausted",
+			errs:         []error{serialization, serialization, serialization, serialization},
+			wantErr:      serialization,
+			wantAttempts: 3,
+			wantStats: store.TxStats{
+				Transactions:          1,
+				Retries:               2,
+				RetriesExhausted:      1,
+				SerializationFailures: 3,
+			},
+		},
+		{
+			name:         "not retryable",
+			errs:         []error{uniqueViolation},
+			wantErr:      uniqueViolation,
+			wantAttempts: 1,
+			wantStats:    store.TxStats{Transactions: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestTxManager(2)
+			attempt, attempts := failing(tt.errs...)
+
+			err := m.withRetries(context.Background(), attempt)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if *attempts != tt.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", *attempts, tt.wantAttempts)
+			}
+
+			if stats := m.Stats(); stats != tt.wantStats {
+				t.Fatalf("stats = %+v, want %+v", stats, tt.wantStats)
+			}
+		})
+	}
+}
+
+func TestWithRetriesStopsWhenContextIsDone(t *testing.T) {
+	m := newTestTxManager(5)
+	m.retryBaseDelay = time.Hour
+	m.retryMaxDelay = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempt, attempts := failing(&pgconn.PgError{Code: sqlStateSerializationFailure})
+
+	if err := m.withRetries(ctx, attempt); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want %v", err, context.Canceled)
+	}
+
+	if *attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", *attempts)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	m := newTestTxManager(10)
+	m.retryBaseDelay = 10 * time.Millisecond
+	m.retryMaxDelay = 50 * time.Millisecond
+
+	for attempt, want := range []time.Duration{10, 20, 40, 50, 50} {
+		want *= time.Millisecond
+
+		for range 20 {
+			if delay := m.backoff(attempt); delay < want/2 || delay > want {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", attempt, delay, want/2, want)
+			}
+		}
+	}
+}
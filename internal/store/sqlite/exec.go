@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// timeLayout has a fixed width, so stored timestamps compare correctly as text.
+const timeLayout = "2006-01-02 15:04:05.000000000"
+
+// FormatTime converts t to the representation stored in timestamp columns.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// FormatNullTime is FormatTime for nullable columns.
+func FormatNullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+
+	return FormatTime(*t)
+}
@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.up.sql
+var migrations embed.FS
+
+// migrate applies the embedded migrations newer than the recorded schema version.
+// Files are named like the Postgres ones: <version>_<name>.up.sql.
+func migrate(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS "schema_migrations" ("version" integer PRIMARY KEY)`)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+
+	current, err := schemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	names, err := migrationNames()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		version, err := migrationVersion(name)
+		if err != nil {
+			return err
+		}
+
+		if version <= current {
+			continue
+		}
+
+		if err = applyMigration(ctx, db, name, version); err != nil {
+			return fmt.Errorf("error applying migration %s: %w", strings.TrimPrefix(name, "migrations/"), err)
+		}
+	}
+
+	return nil
+}
+
+// CheckVersion fails when the schema is behind the embedded migrations,
+// e.g. when the database file was replaced while the app is running.
+func CheckVersion(ctx context.Context, db *sql.DB) error {
+	current, err := schemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	names, err := migrationNames()
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	latest, err := migrationVersion(names[len(names)-1])
+	if err != nil {
+		return err
+	}
+
+	if current < latest {
+		return fmt.Errorf("schema version %d is behind the binary version %d", current, latest)
+	}
+
+	return nil
+}
+
+func schemaVersion(ctx context.Context, db *sql.DB) (int64, error) {
+	var current int64
+
+	err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX("version"), 0) FROM "schema_migrations"`).Scan(&current)
+	if err != nil {
+		return 0, fmt.Errorf("error reading schema version: %w", err)
+	}
+
+	return current, nil
+}
+
+// migrationNames returns the embedded migrations sorted by version.
+func migrationNames() ([]string, error) {
+	names, err := fs.Glob(migrations, "migrations/*.up.sql")
+	if err != nil {
+		return nil, fmt.Errorf("error listing migrations: %w", err)
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+func migrationVersion(name string) (int64, error) {
+	base := strings.TrimPrefix(name, "migrations/")
+
+	version, err := strconv.ParseInt(base[:strings.IndexByte(base, '_')], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing migration version %s: %w", base, err)
+	}
+
+	return version, nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, name string, version int64) error {
+	body, err := migrations.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.ExecContext(ctx, string(body)); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `INSERT INTO "schema_migrations" ("version") VALUES (?)`, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
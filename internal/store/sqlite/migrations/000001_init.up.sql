@@ -0,0 +1,43 @@
+CREATE TABLE "teams" (
+  "team_name" text PRIMARY KEY
+);
+
+CREATE TABLE "users" (
+  "user_id" text PRIMARY KEY,
+  "username" text NOT NULL,
+  "team_name" text NOT NULL REFERENCES "teams" ("team_name"),
+  "is_active" boolean NOT NULL DEFAULT true
+);
+
+CREATE TABLE "pull_requests" (
+  "pull_request_id" text PRIMARY KEY,
+  "pull_request_name" text NOT NULL,
+  "author_id" text NOT NULL REFERENCES "users" ("user_id"),
+  "status" text NOT NULL DEFAULT 'OPEN' CHECK ("status" IN ('OPEN', 'MERGED')),
+  "created_at" timestamp NOT NULL,
+  "merged_at" timestamp,
+  "version" integer NOT NULL DEFAULT 1
+);
+
+CREATE TABLE "assigned_reviewers" (
+  "pull_request_id" text NOT NULL REFERENCES "pull_requests" ("pull_request_id"),
+  "user_id" text NOT NULL REFERENCES "users" ("user_id"),
+  PRIMARY KEY ("pull_request_id", "user_id")
+);
+
+CREATE INDEX "idx_users_team_name" ON "users" ("team_name");
+
+CREATE INDEX "idx_assigned_reviewers_user_id" ON "assigned_reviewers" ("user_id");
+
+CREATE TABLE "idempotency_keys" (
+  "idempotency_key" text PRIMARY KEY,
+  "fingerprint" text NOT NULL,
+  "status_code" integer,
+  "content_type" text,
+  "response_body" blob,
+  "etag" text,
+  "created_at" timestamp NOT NULL,
+  "expires_at" timestamp NOT NULL
+);
+
+CREATE INDEX "idx_idempotency_keys_expires_at" ON "idempotency_keys" ("expires_at");
@@ -0,0 +1,3 @@
+ALTER TABLE "teams" ADD COLUMN "deleted_at" timestamp;
+ALTER TABLE "users" ADD COLUMN "deleted_at" timestamp;
+ALTER TABLE "pull_requests" ADD COLUMN "deleted_at" timestamp;
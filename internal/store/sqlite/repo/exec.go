@@ -0,0 +1,29 @@
+package sqliterepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	sq "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite"
+)
+
+// execAffected runs a statement and returns the number of affected rows.
+func execAffected(ctx context.Context, exec sq.Execer, query squirrel.Sqlizer) (int64, error) {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	res, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error executing query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error reading rows affected: %w", err)
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,175 @@
+package sqliterepo
+
+import (
+	"context"
+	databasesql "database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	sq "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite"
+)
+
+type IdempotencyRepo struct {
+	exec    sq.Execer
+	builder squirrel.StatementBuilderType
+}
+
+func NewIdempotencyRepo(exec sq.Execer, builder squirrel.StatementBuilderType) *IdempotencyRepo {
+	return &IdempotencyRepo{exec: exec, builder: builder}
+}
+
+// Reserve inserts a pending record for the key. An expired record with the
+// same key is replaced. It returns false when a live record already exists.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, record domain.IdempotencyRecord) (bool, error) {
+	now := sq.FormatTime(time.Now())
+
+	query := r.builder.
+		Insert("idempotency_keys").
+		Columns("idempotency_key", "fingerprint", "created_at", "expires_at").
+		Values(record.Key, record.Fingerprint, now, sq.FormatTime(record.ExpiresAt)).
+		Suffix(
+			"ON CONFLICT (idempotency_key) "+
+				"DO UPDATE SET "+
+				"fingerprint = excluded.fingerprint, status_code = NULL, content_type = NULL, "+
+				"response_body = NULL, etag = NULL, "+
+				"created_at = excluded.created_at, expires_at = excluded.expires_at "+
+				"WHERE idempotency_keys.expires_at < ?",
+			now,
+		)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return false, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	res, err := r.exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return false, fmt.Errorf("error executing query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error reading rows affected: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *IdempotencyRepo) GetByKey(ctx context.Context, key string) (domain.IdempotencyRecord, error) {
+	query := r.builder.
+		Select("idempotency_key", "fingerprint", "status_code", "content_type", "response_body", "etag", "expires_at").
+		From("idempotency_keys").
+		Where("idempotency_key = ?", key).
+		Where("expires_at >= ?", sq.FormatTime(time.Now()))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.IdempotencyRecord{}, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	var (
+		record      domain.IdempotencyRecord
+		statusCode  databasesql.NullInt32
+		contentType databasesql.NullString
+		etag        databasesql.NullString
+	)
+
+	err = r.exec.QueryRowContext(ctx, sql, args...).Scan(
+		&record.Key,
+		&record.Fingerprint,
+		&statusCode,
+		&contentType,
+		&record.ResponseBody,
+		&etag,
+		&record.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, databasesql.ErrNoRows) {
+			return domain.IdempotencyRecord{},
+				domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("idempotency key %s not found", key))
+		}
+
+		return domain.IdempotencyRecord{}, fmt.Errorf("error executing query: %w", err)
+	}
+
+	record.StatusCode = int(statusCode.Int32)
+	record.ContentType = contentType.String
+	record.ETag = etag.String
+
+	return record, nil
+}
+
+// Complete stores the response of a pending record and extends its expiry.
+func (r *IdempotencyRepo) Complete(ctx context.Context, record domain.IdempotencyRecord) error {
+	query := r.builder.
+		Update("idempotency_keys").
+		Set("status_code", record.StatusCode).
+		Set("content_type", record.ContentType).
+		Set("response_body", record.ResponseBody).
+		Set("etag", record.ETag).
+		Set("expires_at", sq.FormatTime(record.ExpiresAt)).
+		Where("idempotency_key = ?", record.Key).
+		// A request that outlived its lease must not complete the key taken over by a retry.
+		Where("fingerprint = ?", record.Fingerprint).
+		Where("status_code IS NULL")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	res, err := r.exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error reading rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("idempotency key %s not found", record.Key))
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepo) Delete(ctx context.Context, key string) error {
+	query := r.builder.
+		Delete("idempotency_keys").
+		Where("idempotency_key = ?", key)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	_, err = r.exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	query := r.builder.
+		Delete("idempotency_keys").
+		Where("expires_at < ?", sq.FormatTime(time.Now()))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	res, err := r.exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error executing query: %w", err)
+	}
+
+	return res.RowsAffected()
+}
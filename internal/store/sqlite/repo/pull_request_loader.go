@@ -0,0 +1,134 @@
+package sqliterepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	sq "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite"
+)
+
+type pullRequestTables struct {
+	pullRequests string
+	reviewers    string
+	archivedAt   string
+	deletedAt    string
+}
+
+var (
+	activeTables = pullRequestTables{
+		pullRequests: "pull_requests",
+		reviewers:    "assigned_reviewers",
+		archivedAt:   "NULL",
+		deletedAt:    "pr.deleted_at",
+	}
+	archiveTables = pullRequestTables{
+		pullRequests: "pull_requests_archive",
+		reviewers:    "assigned_reviewers_archive",
+		archivedAt:   "pr.archived_at",
+		deletedAt:    "NULL",
+	}
+)
+
+// selectPullRequests starts a query returning pull requests aliased as pr
+// together with their reviewers, skipping deleted ones. Callers add joins,
+// filters and ordering.
+func selectPullRequests(builder squirrel.StatementBuilderType) squirrel.SelectBuilder {
+	return selectPullRequestsFrom(builder, activeTables).Where("pr.deleted_at IS NULL")
+}
+
+// selectPullRequestsFrom is selectPullRequests over the given tables.
+// Reviewers are collected in the same row as a JSON array.
+func selectPullRequestsFrom(builder squirrel.StatementBuilderType, tables pullRequestTables) squirrel.SelectBuilder {
+	reviewersColumn := "(SELECT json_group_array(ar.user_id) FROM " + tables.reviewers + " ar " +
+		"WHERE ar.pull_request_id = pr.pull_request_id) AS assigned_reviewers"
+
+	return builder.
+		Select(
+			"pr.pull_request_id",
+			"pr.pull_request_name",
+			"pr.author_id",
+			"pr.status",
+			"pr.created_at",
+			"pr.merged_at",
+			"pr.version",
+			tables.archivedAt+" AS archived_at",
+			tables.deletedAt+" AS deleted_at",
+			reviewersColumn,
+		).
+		From(tables.pullRequests + " pr")
+}
+
+// loadPullRequests runs a query built on selectPullRequests in one round trip.
+func loadPullRequests(ctx context.Context, exec sq.Execer, query squirrel.SelectBuilder) ([]domain.PullRequest, error) {
+	var pullRequests []domain.PullRequest
+
+	err := forEachPullRequest(ctx, exec, query, func(pr domain.PullRequest) error {
+		pullRequests = append(pullRequests, pr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pullRequests, nil
+}
+
+// forEachPullRequest is loadPullRequests passing rows to fn as they are read.
+func forEachPullRequest(
+	ctx context.Context,
+	exec sq.Execer,
+	query squirrel.SelectBuilder,
+	fn func(pr domain.PullRequest) error,
+) error {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	rows, err := exec.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pr        domain.PullRequest
+			reviewers string
+		)
+
+		err = rows.Scan(
+			&pr.ID,
+			&pr.Name,
+			&pr.AuthorID,
+			&pr.Status,
+			&pr.CreatedAt,
+			&pr.MergedAt,
+			&pr.Version,
+			&pr.ArchivedAt,
+			&pr.DeletedAt,
+			&reviewers,
+		)
+		if err != nil {
+			return fmt.Errorf("error scanning pull request: %w", err)
+		}
+
+		if err = json.Unmarshal([]byte(reviewers), &pr.AssignedReviewers); err != nil {
+			return fmt.Errorf("error decoding reviewers: %w", err)
+		}
+
+		if err = fn(pr); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error scanning pull requests: %w", err)
+	}
+
+	return nil
+}
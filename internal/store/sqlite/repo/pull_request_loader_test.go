@@ -0,0 +1,249 @@
+package sqliterepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/config"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	sq "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite"
+)
+
+const (
+	benchUsers        = 50
+	benchPullRequests = 2000
+	// benchReviewer reviews 80 of the pull requests.
+	benchReviewer = "user-1"
+)
+
+// openBenchDB seeds a temporary database with a team of benchUsers users
+// and benchPullRequests pull requests, two reviewers each.
+func openBenchDB(b *testing.B) *sql.DB {
+	b.Helper()
+
+	ctx := context.Background()
+	cfg := &config.SQLiteConfig{Path: filepath.Join(b.TempDir(), "bench.db"), BusyTimeout: 5 * time.Second}
+
+	db, err := sq.Open(ctx, cfg)
+	if err != nil {
+		b.Fatalf("open sqlite: %v", err)
+	}
+
+	b.Cleanup(func() { _ = db.Close() })
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		b.Fatalf("begin: %v", err)
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	builder := sq.NewStatementBuilder()
+
+	if err = NewTeamRepo(tx, builder).InsertTeam(ctx, "backend"); err != nil {
+		b.Fatalf("insert team: %v", err)
+	}
+
+	for i := range benchUsers {
+		user := domain.User{ID: fmt.Sprintf("user-%d", i), Username: "user", TeamName: "backend", IsActive: true}
+		if err = NewUserRepo(tx, builder).UpsertUser(ctx, user); err != nil {
+			b.Fatalf("insert user: %v", err)
+		}
+	}
+
+	prRepo := NewPullRequestRepo(tx, builder)
+
+	for i := range benchPullRequests {
+		prID := fmt.Sprintf("pr-%d", i)
+
+		pr := domain.PullRequest{ID: prID, Name: prID, AuthorID: fmt.Sprintf("user-%d", i%benchUsers)}
+		if err = prRepo.InsertPullRequest(ctx, pr); err != nil {
+			b.Fatalf("insert pull request: %v", err)
+		}
+
+		for _, offset := range []int{1, 2} {
+			if err = prRepo.AddReviewer(ctx, prID, fmt.Sprintf("user-%d", (i+offset)%benchUsers)); err != nil {
+				b.Fatalf("add reviewer: %v", err)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		b.Fatalf("commit: %v", err)
+	}
+
+	return db
+}
+
+// getByIDPerPR is GetByID as it was before the single-query loader: the
+// pull request first, then its reviewers.
+func getByIDPerPR(
+	ctx context.Context,
+	exec sq.Execer,
+	builder squirrel.StatementBuilderType,
+	pullRequestID string,
+) (domain.PullRequest, error) {
+	var pr domain.PullRequest
+
+	query, args, err := builder.
+		Select("pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at", "version").
+		From("pull_requests").
+		Where("pull_request_id = ?", pullRequestID).
+		ToSql()
+	if err != nil {
+		return pr, err
+	}
+
+	err = exec.QueryRowContext(ctx, query, args...).
+		Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.Version)
+	if err != nil {
+		return pr, err
+	}
+
+	pr.AssignedReviewers, err = reviewersPerPR(ctx, exec, builder, pullRequestID)
+
+	return pr, err
+}
+
+// listReviewPRsPerPR lists the pull requests of a reviewer and then queries
+// the reviewers of each, the way a list endpoint would without the loader.
+func listReviewPRsPerPR(
+	ctx context.Context,
+	exec sq.Execer,
+	builder squirrel.StatementBuilderType,
+	userID string,
+) ([]domain.PullRequest, error) {
+	query, args, err := builder.
+		Select("pr.pull_request_id", "pr.pull_request_name", "pr.author_id", "pr.status", "pr.created_at",
+			"pr.merged_at", "pr.version").
+		From("assigned_reviewers ar").
+		Join("pull_requests pr ON pr.pull_request_id = ar.pull_request_id").
+		Where("ar.user_id = ?", userID).
+		OrderBy("pr.created_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var pullRequests []domain.PullRequest
+
+	for rows.Next() {
+		var pr domain.PullRequest
+		if err = rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt,
+			&pr.Version); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+
+		pullRequests = append(pullRequests, pr)
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range pullRequests {
+		pullRequests[i].AssignedReviewers, err = reviewersPerPR(ctx, exec, builder, pullRequests[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pullRequests, nil
+}
+
+func reviewersPerPR(
+	ctx context.Context,
+	exec sq.Execer,
+	builder squirrel.StatementBuilderType,
+	pullRequestID string,
+) ([]string, error) {
+	query, args, err := builder.
+		Select("user_id").
+		From("assigned_reviewers").
+		Where("pull_request_id = ?", pullRequestID).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var reviewers []string
+
+	for rows.Next() {
+		var reviewerID string
+		if err = rows.Scan(&reviewerID); err != nil {
+			return nil, err
+		}
+
+		reviewers = append(reviewers, reviewerID)
+	}
+
+	return reviewers, rows.Err()
+}
+
+func BenchmarkGetByID(b *testing.B) {
+	ctx := context.Background()
+	db := openBenchDB(b)
+	builder := sq.NewStatementBuilder()
+	repo := NewPullRequestRepo(db, builder)
+
+	b.Run("single query", func(b *testing.B) {
+		for i := 0; b.Loop(); i++ {
+			if _, err := repo.GetByID(ctx, fmt.Sprintf("pr-%d", i%benchPullRequests)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("per-PR reviewers", func(b *testing.B) {
+		for i := 0; b.Loop(); i++ {
+			if _, err := getByIDPerPR(ctx, db, builder, fmt.Sprintf("pr-%d", i%benchPullRequests)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkListReviewPRs(b *testing.B) {
+	ctx := context.Background()
+	db := openBenchDB(b)
+	builder := sq.NewStatementBuilder()
+	repo := NewUserRepo(db, builder)
+
+	b.Run("single query", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := repo.ListReviewPRs(ctx, benchReviewer); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("per-PR reviewers", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := listReviewPRsPerPR(ctx, db, builder, benchReviewer); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,322 @@
+package sqliterepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	sq "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite"
+)
+
+type PullRequestRepo struct {
+	exec    sq.Execer
+	builder squirrel.StatementBuilderType
+}
+
+func NewPullRequestRepo(exec sq.Execer, builder squirrel.StatementBuilderType) *PullRequestRepo {
+	return &PullRequestRepo{exec: exec, builder: builder}
+}
+
+func (r *PullRequestRepo) InsertPullRequest(ctx context.Context, pullRequest domain.PullRequest) error {
+	query := r.builder.
+		Insert("pull_requests").
+		Columns("pull_request_id", "pull_request_name", "author_id", "created_at").
+		Values(pullRequest.ID, pullRequest.Name, pullRequest.AuthorID, sq.FormatTime(time.Now()))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	res, err := r.exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		if sq.IsUniqueViolation(err) {
+			return domain.NewError(
+				domain.ErrCodePRExists,
+				fmt.Sprintf("pull request %s already exists", pullRequest.ID),
+			)
+		}
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error reading rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return errors.New("no rows affected")
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepo) GetByID(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
+	query := selectPullRequests(r.builder).
+		Where("pr.pull_request_id = ?", pullRequestID)
+
+	pullRequests, err := loadPullRequests(ctx, r.exec, query)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if len(pullRequests) == 0 {
+		return domain.PullRequest{},
+			domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("pull request %s not found", pullRequestID))
+	}
+
+	return pullRequests[0], nil
+}
+
+// GetByIDs loads pull requests with their reviewers in one query, newest first.
+// Unknown IDs are skipped.
+func (r *PullRequestRepo) GetByIDs(ctx context.Context, pullRequestIDs []string) ([]domain.PullRequest, error) {
+	query := selectPullRequests(r.builder).
+		Where(squirrel.Eq{"pr.pull_request_id": pullRequestIDs}).
+		OrderBy("pr.created_at DESC")
+
+	return loadPullRequests(ctx, r.exec, query)
+}
+
+// GetByIDForUpdate is the same as GetByID: SQLite has no row locks,
+// and write transactions already hold the database lock from BEGIN.
+func (r *PullRequestRepo) GetByIDForUpdate(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
+	return r.GetByID(ctx, pullRequestID)
+}
+
+func (r *PullRequestRepo) IncrementVersion(ctx context.Context, pullRequestID string) error {
+	query := r.builder.
+		Update("pull_requests").
+		Set("version", squirrel.Expr("version + 1")).
+		Where("pull_request_id = ?", pullRequestID)
+
+	return r.execUpdate(ctx, query, pullRequestID)
+}
+
+func (r *PullRequestRepo) AddReviewer(ctx context.Context, pullRequestID string, reviewerID string) error {
+	query := r.builder.
+		Insert("assigned_reviewers").
+		Columns("pull_request_id", "user_id").
+		Values(pullRequestID, reviewerID)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	res, err := r.exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error reading rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return errors.New("no rows affected")
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepo) RemoveReviewer(ctx context.Context, pullRequestID string, reviewerID string) error {
+	query := r.builder.
+		Delete("assigned_reviewers").
+		Where("pull_request_id = ?", pullRequestID).
+		Where("user_id = ?", reviewerID)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	res, err := r.exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error reading rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotAssigned, "incomplete removal")
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepo) MergePullRequest(ctx context.Context, pullRequest domain.PullRequest) error {
+	query := r.builder.
+		Update("pull_requests").
+		Set("status", pullRequest.Status).
+		Set("merged_at", sq.FormatNullTime(pullRequest.MergedAt)).
+		Set("version", squirrel.Expr("version + 1")).
+		Where("pull_request_id = ?", pullRequest.ID)
+
+	return r.execUpdate(ctx, query, pullRequest.ID)
+}
+
+func (r *PullRequestRepo) execUpdate(ctx context.Context, query squirrel.UpdateBuilder, pullRequestID string) error {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	res, err := r.exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error reading rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("pull request %s not found", pullRequestID))
+	}
+
+	return nil
+}
+
+// SoftDeletePullRequest hides the pull request from reads. The version is
+// bumped so that cached copies are no longer valid.
+func (r *PullRequestRepo) SoftDeletePullRequest(ctx context.Context, pullRequestID string, deletedAt time.Time) error {
+	query := r.builder.
+		Update("pull_requests").
+		Set("deleted_at", sq.FormatTime(deletedAt)).
+		Set("version", squirrel.Expr("version + 1")).
+		Where("pull_request_id = ?", pullRequestID).
+		Where("deleted_at IS NULL")
+
+	affected, err := execAffected(ctx, r.exec, query)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("pull request %s not found", pullRequestID))
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepo) RestorePullRequest(ctx context.Context, pullRequestID string) error {
+	query := r.builder.
+		Update("pull_requests").
+		Set("deleted_at", nil).
+		Set("version", squirrel.Expr("version + 1")).
+		Where("pull_request_id = ?", pullRequestID).
+		Where("deleted_at IS NOT NULL")
+
+	affected, err := execAffected(ctx, r.exec, query)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.NewError(
+			domain.ErrCodeNotFound,
+			fmt.Sprintf("deleted pull request %s not found", pullRequestID),
+		)
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepo) CountOpenByTeam(ctx context.Context) (map[string]int, error) {
+	query := r.builder.
+		Select("u.team_name", "COUNT(*)").
+		From("pull_requests pr").
+		Join("users u ON u.user_id = pr.author_id").
+		Where(squirrel.Eq{"pr.status": domain.PRStatusOpen}).
+		Where("pr.deleted_at IS NULL").
+		GroupBy("u.team_name")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	rows, err := r.exec.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", err)
+	}
+
+	defer rows.Close()
+
+	counts := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			teamName string
+			count    int
+		)
+
+		if err = rows.Scan(&teamName, &count); err != nil {
+			return nil, fmt.Errorf("error scanning open pull requests: %w", err)
+		}
+
+		counts[teamName] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning open pull requests: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *PullRequestRepo) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	query := r.builder.
+		Select("ar.user_id", "COUNT(*)").
+		From("assigned_reviewers ar").
+		Join("pull_requests pr ON pr.pull_request_id = ar.pull_request_id").
+		Where(squirrel.Eq{"ar.user_id": userIDs}).
+		Where(squirrel.Eq{"pr.status": domain.PRStatusOpen}).
+		Where("pr.deleted_at IS NULL").
+		GroupBy("ar.user_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	rows, err := r.exec.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			userID string
+			count  int
+		)
+
+		if err = rows.Scan(&userID, &count); err != nil {
+			return nil, fmt.Errorf("error scanning open reviews: %w", err)
+		}
+
+		counts[userID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning open reviews: %w", err)
+	}
+
+	return counts, nil
+}
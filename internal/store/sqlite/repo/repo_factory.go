@@ -0,0 +1,39 @@
+package sqliterepo
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/repository"
+	sq "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite"
+)
+
+type SQLiteRepoFactory struct {
+	builder squirrel.StatementBuilderType
+}
+
+func NewRepoFactory(builder squirrel.StatementBuilderType) *SQLiteRepoFactory {
+	return &SQLiteRepoFactory{builder: builder}
+}
+
+func (r *SQLiteRepoFactory) TeamRepository(exec sq.Execer) repository.TeamRepository {
+	return NewTeamRepo(exec, r.builder)
+}
+
+func (r *SQLiteRepoFactory) UserRepository(exec sq.Execer) repository.UserRepository {
+	return NewUserRepo(exec, r.builder)
+}
+
+func (r *SQLiteRepoFactory) PullRequestRepository(exec sq.Execer) repository.PullRequestRepository {
+	return NewPullRequestRepo(exec, r.builder)
+}
+
+func (r *SQLiteRepoFactory) IdempotencyRepository(exec sq.Execer) repository.IdempotencyRepository {
+	return NewIdempotencyRepo(exec, r.builder)
+}
+
+func (r *SQLiteRepoFactory) ArchiveRepository(exec sq.Execer) repository.ArchiveRepository {
+	return NewArchiveRepo(exec, r.builder)
+}
+
+func (r *SQLiteRepoFactory) SnapshotRepository(exec sq.Execer) repository.SnapshotRepository {
+	return NewSnapshotRepo(exec, r.builder)
+}
@@ -0,0 +1,227 @@
+package sqliterepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	sq "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite"
+)
+
+type SnapshotRepo struct {
+	exec    sq.Execer
+	builder squirrel.StatementBuilderType
+}
+
+func NewSnapshotRepo(exec sq.Execer, builder squirrel.StatementBuilderType) *SnapshotRepo {
+	return &SnapshotRepo{exec: exec, builder: builder}
+}
+
+func (r *SnapshotRepo) ForEachTeam(ctx context.Context, fn func(team domain.SnapshotTeam) error) error {
+	query := r.builder.
+		Select("team_name", "deleted_at").
+		From("teams").
+		OrderBy("team_name")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	rows, err := r.exec.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var team domain.SnapshotTeam
+		if err = rows.Scan(&team.Name, &team.DeletedAt); err != nil {
+			return fmt.Errorf("error scanning team: %w", err)
+		}
+
+		if err = fn(team); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error scanning teams: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SnapshotRepo) ForEachUser(ctx context.Context, fn func(user domain.User) error) error {
+	query := r.builder.
+		Select("user_id", "username", "team_name", "is_active", "deleted_at").
+		From("users").
+		OrderBy("user_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	rows, err := r.exec.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var user domain.User
+		if err = rows.Scan(&user.ID, &user.Username, &user.TeamName, &user.IsActive, &user.DeletedAt); err != nil {
+			return fmt.Errorf("error scanning user: %w", err)
+		}
+
+		if err = fn(user); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error scanning users: %w", err)
+	}
+
+	return nil
+}
+
+// ForEachPullRequest passes pull requests to fn while reading them, so the
+// connection is busy until it returns.
+func (r *SnapshotRepo) ForEachPullRequest(ctx context.Context, fn func(pr domain.PullRequest) error) error {
+	query := selectPullRequestsFrom(r.builder, activeTables).
+		OrderBy("pr.pull_request_id")
+
+	return forEachPullRequest(ctx, r.exec, query, fn)
+}
+
+func (r *SnapshotRepo) DeleteAll(ctx context.Context) error {
+	queries := []squirrel.DeleteBuilder{
+		r.builder.Delete("assigned_reviewers"),
+		r.builder.Delete("pull_requests"),
+		r.builder.Delete("users"),
+		r.builder.Delete("teams"),
+		r.builder.Delete("idempotency_keys").Where("status_code IS NOT NULL"),
+	}
+
+	for _, query := range queries {
+		if _, err := execAffected(ctx, r.exec, query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// upsert runs the query after checking whether the row with the given key exists.
+func (r *SnapshotRepo) upsert(
+	ctx context.Context,
+	table string,
+	keyColumn string,
+	key string,
+	query squirrel.InsertBuilder,
+) (bool, error) {
+	sql, args, err := r.builder.
+		Select("COUNT(*)").
+		From(table).
+		Where(squirrel.Eq{keyColumn: key}).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	var count int
+	if err = r.exec.QueryRowContext(ctx, sql, args...).Scan(&count); err != nil {
+		return false, fmt.Errorf("error executing query: %w", err)
+	}
+
+	if _, err = execAffected(ctx, r.exec, query); err != nil {
+		return false, err
+	}
+
+	return count == 0, nil
+}
+
+func (r *SnapshotRepo) UpsertTeam(ctx context.Context, team domain.SnapshotTeam) (bool, error) {
+	return r.upsert(ctx, "teams", "team_name", team.Name, r.builder.
+		Insert("teams").
+		Columns("team_name", "deleted_at").
+		Values(team.Name, sq.FormatNullTime(team.DeletedAt)).
+		Suffix("ON CONFLICT (team_name) DO UPDATE SET deleted_at = excluded.deleted_at"))
+}
+
+func (r *SnapshotRepo) UpsertUser(ctx context.Context, user domain.User) (bool, error) {
+	return r.upsert(ctx, "users", "user_id", user.ID, r.builder.
+		Insert("users").
+		Columns("user_id", "username", "team_name", "is_active", "deleted_at").
+		Values(user.ID, user.Username, user.TeamName, user.IsActive, sq.FormatNullTime(user.DeletedAt)).
+		Suffix(
+			"ON CONFLICT (user_id) DO UPDATE SET "+
+				"username = excluded.username, team_name = excluded.team_name, "+
+				"is_active = excluded.is_active, deleted_at = excluded.deleted_at",
+		))
+}
+
+// UpsertPullRequest writes the pull request and replaces its reviewers.
+func (r *SnapshotRepo) UpsertPullRequest(ctx context.Context, pr domain.PullRequest) (bool, error) {
+	created, err := r.upsert(ctx, "pull_requests", "pull_request_id", pr.ID, r.builder.
+		Insert("pull_requests").
+		Columns(
+			"pull_request_id",
+			"pull_request_name",
+			"author_id",
+			"status",
+			"created_at",
+			"merged_at",
+			"version",
+			"deleted_at",
+		).
+		Values(
+			pr.ID,
+			pr.Name,
+			pr.AuthorID,
+			pr.Status,
+			sq.FormatTime(pr.CreatedAt),
+			sq.FormatNullTime(pr.MergedAt),
+			pr.Version,
+			sq.FormatNullTime(pr.DeletedAt),
+		).
+		Suffix(
+			"ON CONFLICT (pull_request_id) DO UPDATE SET "+
+				"pull_request_name = excluded.pull_request_name, author_id = excluded.author_id, "+
+				"status = excluded.status, created_at = excluded.created_at, merged_at = excluded.merged_at, "+
+				"version = excluded.version, deleted_at = excluded.deleted_at",
+		))
+	if err != nil {
+		return false, err
+	}
+
+	_, err = execAffected(ctx, r.exec, r.builder.
+		Delete("assigned_reviewers").
+		Where("pull_request_id = ?", pr.ID))
+	if err != nil {
+		return false, err
+	}
+
+	if len(pr.AssignedReviewers) == 0 {
+		return created, nil
+	}
+
+	insertReviewers := r.builder.
+		Insert("assigned_reviewers").
+		Columns("pull_request_id", "user_id")
+
+	for _, reviewerID := range pr.AssignedReviewers {
+		insertReviewers = insertReviewers.Values(pr.ID, reviewerID)
+	}
+
+	if _, err = execAffected(ctx, r.exec, insertReviewers); err != nil {
+		return false, err
+	}
+
+	return created, nil
+}
@@ -0,0 +1,159 @@
+package sqliterepo
+
+import (
+	"context"
+	databasesql "database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	sq "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite"
+)
+
+type TeamRepo struct {
+	exec    sq.Execer
+	builder squirrel.StatementBuilderType
+}
+
+func NewTeamRepo(exec sq.Execer, builder squirrel.StatementBuilderType) *TeamRepo {
+	return &TeamRepo{exec: exec, builder: builder}
+}
+
+func (r *TeamRepo) InsertTeam(ctx context.Context, teamName string) error {
+	query := r.builder.
+		Insert("teams").
+		Columns("team_name").
+		Values(teamName)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	_, err = r.exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		if sq.IsUniqueViolation(err) {
+			return domain.NewError(domain.ErrCodeTeamExists, fmt.Sprintf("team %s already exists", teamName))
+		}
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TeamRepo) GetTeamWithMembers(ctx context.Context, teamName string) (domain.TeamUpsert, error) {
+	query := r.builder.
+		Select("u.user_id", "u.username", "u.is_active").
+		From("teams t").
+		LeftJoin("users u ON u.team_name = t.team_name AND u.deleted_at IS NULL").
+		Where("t.team_name = ?", teamName).
+		Where("t.deleted_at IS NULL")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.TeamUpsert{}, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	rows, err := r.exec.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return domain.TeamUpsert{}, fmt.Errorf("error executing query: %w", err)
+	}
+
+	defer rows.Close()
+
+	var (
+		members    []domain.TeamMember
+		seenAnyRow bool
+	)
+
+	for rows.Next() {
+		seenAnyRow = true
+
+		var (
+			memberUserID   databasesql.NullString
+			memberUsername databasesql.NullString
+			memberIsActive databasesql.NullBool
+		)
+
+		err = rows.Scan(&memberUserID, &memberUsername, &memberIsActive)
+		if err != nil {
+			return domain.TeamUpsert{}, fmt.Errorf("error scanning member: %w", err)
+		}
+
+		if memberUserID.Valid {
+			members = append(members, domain.TeamMember{
+				UserID:   memberUserID.String,
+				Username: memberUsername.String,
+				IsActive: memberIsActive.Bool,
+			})
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return domain.TeamUpsert{}, fmt.Errorf("error scanning members: %w", err)
+	}
+
+	if !seenAnyRow {
+		return domain.TeamUpsert{}, domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("team %s not found", teamName))
+	}
+
+	return domain.TeamUpsert{
+		Name:    teamName,
+		Members: members,
+	}, nil
+}
+
+// SoftDeleteTeam marks the team and its members as deleted at the same time,
+// which lets RestoreTeam tell them apart from members deleted earlier.
+func (r *TeamRepo) SoftDeleteTeam(ctx context.Context, teamName string, deletedAt time.Time) error {
+	affected, err := execAffected(ctx, r.exec, r.builder.
+		Update("teams").
+		Set("deleted_at", sq.FormatTime(deletedAt)).
+		Where("team_name = ?", teamName).
+		Where("deleted_at IS NULL"))
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("team %s not found", teamName))
+	}
+
+	_, err = execAffected(ctx, r.exec, r.builder.
+		Update("users").
+		Set("deleted_at", sq.FormatTime(deletedAt)).
+		Where("team_name = ?", teamName).
+		Where("deleted_at IS NULL"))
+
+	return err
+}
+
+// RestoreTeam restores the team and the members deleted together with it.
+func (r *TeamRepo) RestoreTeam(ctx context.Context, teamName string) error {
+	_, err := execAffected(ctx, r.exec, r.builder.
+		Update("users AS u").
+		Set("deleted_at", nil).
+		From("teams t").
+		Where("t.team_name = u.team_name").
+		Where("t.team_name = ?", teamName).
+		Where("u.deleted_at = t.deleted_at"))
+	if err != nil {
+		return err
+	}
+
+	affected, err := execAffected(ctx, r.exec, r.builder.
+		Update("teams").
+		Set("deleted_at", nil).
+		Where("team_name = ?", teamName).
+		Where("deleted_at IS NOT NULL"))
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("deleted team %s not found", teamName))
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package sqliterepo
+
+import (
+	"context"
+	databasesql "database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/domain"
+	sq "github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store/sqlite"
+)
+
+type UserRepo struct {
+	exec    sq.Execer
+	builder squirrel.StatementBuilderType
+}
+
+func NewUserRepo(exec sq.Execer, builder squirrel.StatementBuilderType) *UserRepo {
+	return &UserRepo{exec: exec, builder: builder}
+}
+
+// UpsertUser also restores the user if it was deleted.
+func (r *UserRepo) UpsertUser(ctx context.Context, user domain.User) error {
+	query := r.builder.
+		Insert("users").
+		Columns("user_id", "username", "team_name", "is_active").
+		Values(user.ID, user.Username, user.TeamName, user.IsActive)
+
+	withUpdate := query.
+		Suffix(
+			"ON CONFLICT (user_id) " +
+				"DO UPDATE SET " +
+				"username = excluded.username, team_name = excluded.team_name, is_active = excluded.is_active, " +
+				"deleted_at = NULL",
+		)
+
+	sql, args, err := withUpdate.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	res, err := r.exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error reading rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return errors.New("no rows affected")
+	}
+
+	return nil
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, userID string) (domain.User, error) {
+	return r.getByID(ctx, userID, false)
+}
+
+// GetByIDWithDeleted is the same as GetByID but also returns deleted users.
+func (r *UserRepo) GetByIDWithDeleted(ctx context.Context, userID string) (domain.User, error) {
+	return r.getByID(ctx, userID, true)
+}
+
+func (r *UserRepo) getByID(ctx context.Context, userID string, withDeleted bool) (domain.User, error) {
+	query := r.builder.
+		Select("user_id", "username", "team_name", "is_active", "deleted_at").
+		From("users").
+		Where("user_id = ?", userID)
+
+	if !withDeleted {
+		query = query.Where("deleted_at IS NULL")
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.User{}, fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	var user domain.User
+
+	err = r.exec.QueryRowContext(ctx, sql, args...).Scan(
+		&user.ID,
+		&user.Username,
+		&user.TeamName,
+		&user.IsActive,
+		&user.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, databasesql.ErrNoRows) {
+			return domain.User{}, domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("user %s not found", userID))
+		}
+
+		return domain.User{}, fmt.Errorf("error scanning user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepo) SetIsActive(ctx context.Context, userID string, isActive bool) error {
+	query := r.builder.
+		Update("users").
+		Set("is_active", isActive).
+		Where("user_id = ?", userID).
+		Where("deleted_at IS NULL")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("error generating sql query: %w", err)
+	}
+
+	res, err := r.exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error reading rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("user %s not found", userID))
+	}
+
+	return nil
+}
+
+func (r *UserRepo) SoftDeleteUser(ctx context.Context, userID string, deletedAt time.Time) error {
+	query := r.builder.
+		Update("users").
+		Set("deleted_at", sq.FormatTime(deletedAt)).
+		Where("user_id = ?", userID).
+		Where("deleted_at IS NULL")
+
+	affected, err := execAffected(ctx, r.exec, query)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("user %s not found", userID))
+	}
+
+	return nil
+}
+
+func (r *UserRepo) RestoreUser(ctx context.Context, userID string) error {
+	query := r.builder.
+		Update("users").
+		Set("deleted_at", nil).
+		Where("user_id = ?", userID).
+		Where("deleted_at IS NOT NULL")
+
+	affected, err := execAffected(ctx, r.exec, query)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("deleted user %s not found", userID))
+	}
+
+	return nil
+}
+
+func (r *UserRepo) ListReviewPRs(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+	query := selectPullRequests(r.builder).
+		Join("assigned_reviewers rev ON rev.pull_request_id = pr.pull_request_id").
+		Where("rev.user_id = ?", userID).
+		OrderBy("pr.created_at DESC")
+
+	return loadPullRequests(ctx, r.exec, query)
+}
@@ -0,0 +1,7 @@
+package sqlite
+
+import "github.com/Masterminds/squirrel"
+
+func NewStatementBuilder() squirrel.StatementBuilderType {
+	return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question)
+}
@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/store"
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
+)
+
+type TxManager struct {
+	db *sql.DB
+
+	transactions atomic.Uint64
+	commits      atomic.Uint64
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+func (m *TxManager) TxWrapper(ctx context.Context, fn func(ctx context.Context, tx Execer) error) error {
+	return m.TxWrapperWithOptions(ctx, store.TxOptions{}, fn)
+}
+
+// TxWrapperWithOptions ignores the options: SQLite transactions are always
+// serializable and every transaction takes the write lock on BEGIN.
+func (m *TxManager) TxWrapperWithOptions(
+	ctx context.Context,
+	opts store.TxOptions,
+	fn func(ctx context.Context, tx Execer) error,
+) (err error) {
+	m.transactions.Add(1)
+
+	ctx, span := store.StartTxSpan(ctx, opts)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	txCtx, runCommitHooks := store.WithCommitHooks(ctx)
+
+	err = fn(txCtx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	m.commits.Add(1)
+
+	runCommitHooks()
+
+	return nil
+}
+
+func (m *TxManager) Stats() store.TxStats {
+	return store.TxStats{
+		Transactions: m.transactions.Load(),
+		Commits:      m.commits.Load(),
+	}
+}
@@ -0,0 +1,51 @@
+// Package store holds the contract shared by the storage backends.
+package store
+
+import "time"
+
+// IsoLevel values match the PostgreSQL isolation level names.
+type IsoLevel string
+
+const (
+	IsoLevelDefault        IsoLevel = ""
+	IsoLevelReadCommitted  IsoLevel = "read committed"
+	IsoLevelRepeatableRead IsoLevel = "repeatable read"
+	IsoLevelSerializable   IsoLevel = "serializable"
+)
+
+type TxOptions struct {
+	IsoLevel IsoLevel
+	ReadOnly bool
+}
+
+type TxStats struct {
+	Transactions          uint64
+	Commits               uint64
+	Retries               uint64
+	RetriesExhausted      uint64
+	SerializationFailures uint64
+	Deadlocks             uint64
+}
+
+// PoolStats describes a connection pool. Healthy and Lag are meaningful
+// for read replicas only.
+type PoolStats struct {
+	Name              string
+	MaxConns          int32
+	TotalConns        int32
+	IdleConns         int32
+	AcquiredConns     int32
+	AcquireCount      int64
+	EmptyAcquireCount int64
+	AcquireDuration   time.Duration
+	Healthy           bool
+	Lag               time.Duration
+}
+
+type CacheStats struct {
+	Enabled    bool
+	TeamHits   uint64
+	TeamMisses uint64
+	UserHits   uint64
+	UserMisses uint64
+}
@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+
+	"github.com/std46d6b/Backend-trainee-assignment-autumn-2025/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartTxSpan starts the span covering a transaction with all its retries.
+func StartTxSpan(ctx context.Context, opts TxOptions) (context.Context, trace.Span) {
+	isoLevel := string(opts.IsoLevel)
+	if isoLevel == "" {
+		isoLevel = "default"
+	}
+
+	return tracing.Start(ctx, "tx",
+		attribute.String("db.transaction.isolation", isoLevel),
+		attribute.Bool("db.transaction.read_only", opts.ReadOnly),
+	)
+}
@@ -0,0 +1,11 @@
+CREATE TABLE "idempotency_keys" (
+  "idempotency_key" text PRIMARY KEY,
+  "fingerprint" text NOT NULL,
+  "status_code" integer,
+  "content_type" text,
+  "response_body" bytea,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expires_at" timestamptz NOT NULL
+);
+
+CREATE INDEX "idx_idempotency_keys_expires_at" ON "idempotency_keys" ("expires_at");
@@ -0,0 +1,2 @@
+ALTER TABLE "idempotency_keys" DROP COLUMN IF EXISTS "etag";
+ALTER TABLE "pull_requests" DROP COLUMN IF EXISTS "version";
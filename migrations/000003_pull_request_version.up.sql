@@ -0,0 +1,2 @@
+ALTER TABLE "pull_requests" ADD COLUMN "version" bigint NOT NULL DEFAULT 1;
+ALTER TABLE "idempotency_keys" ADD COLUMN "etag" text;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_pull_requests_merged_at";
+DROP TABLE IF EXISTS "assigned_reviewers_archive";
+DROP TABLE IF EXISTS "pull_requests_archive";
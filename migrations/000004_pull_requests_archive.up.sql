@@ -0,0 +1,20 @@
+CREATE TABLE "pull_requests_archive" (
+  "pull_request_id" text PRIMARY KEY,
+  "pull_request_name" text NOT NULL,
+  "author_id" text NOT NULL,
+  "status" pull_request_status NOT NULL,
+  "created_at" timestamptz NOT NULL,
+  "merged_at" timestamptz,
+  "version" bigint NOT NULL,
+  "archived_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "assigned_reviewers_archive" (
+  "pull_request_id" text NOT NULL REFERENCES "pull_requests_archive" ("pull_request_id") ON DELETE CASCADE,
+  "user_id" text NOT NULL,
+  PRIMARY KEY ("pull_request_id", "user_id")
+);
+
+CREATE INDEX "idx_assigned_reviewers_archive_user_id" ON "assigned_reviewers_archive" ("user_id");
+
+CREATE INDEX "idx_pull_requests_merged_at" ON "pull_requests" ("merged_at") WHERE "status" = 'MERGED';
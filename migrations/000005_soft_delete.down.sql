@@ -0,0 +1,3 @@
+ALTER TABLE "pull_requests" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "teams" DROP COLUMN IF EXISTS "deleted_at";
@@ -0,0 +1,3 @@
+ALTER TABLE "teams" ADD COLUMN "deleted_at" timestamptz;
+ALTER TABLE "users" ADD COLUMN "deleted_at" timestamptz;
+ALTER TABLE "pull_requests" ADD COLUMN "deleted_at" timestamptz;
@@ -0,0 +1,7 @@
+// Package migrations embeds the Postgres schema migrations into the app binary.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS